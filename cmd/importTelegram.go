@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/filestore"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/postgresql"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	importExportPath  string
+	importMappingFile string
+	importUserMapping map[string]string
+	importTimezone    string
+)
+
+// importTelegramCmd represents the importTelegram command
+var importTelegramCmd = &cobra.Command{
+	Use:   "importTelegram",
+	Short: "Importing a Telegram Desktop chat export",
+	Long: `Imports personal chats from a Telegram Desktop result.json export.
+
+Export users (from_id values such as user123456) are mapped to existing accounts
+by email, either with repeated --map flags or a JSON --mapping file. Running the
+command again on the same export only imports what is still missing.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("importTelegram called")
+
+		slogLogger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+		logger := utils.NewLoggerContext(slogLogger)
+
+		cfg, err := config.GetCfg()
+		if err != nil {
+			logger.Error("failed to get the config", "error", err)
+			return
+		}
+
+		input := &dto.TelegramImportRequest{
+			ExportPath:  importExportPath,
+			UserMapping: make(map[string]string),
+			Timezone:    importTimezone,
+		}
+
+		if importMappingFile != "" {
+			data, err := os.ReadFile(importMappingFile)
+			if err != nil {
+				logger.Error("failed to read the mapping file", "error", err)
+				return
+			}
+			if err := json.Unmarshal(data, &input.UserMapping); err != nil {
+				logger.Error("failed to parse the mapping file", "error", err)
+				return
+			}
+		}
+
+		for fromId, email := range importUserMapping {
+			input.UserMapping[fromId] = email
+		}
+
+		v := helper.NewValidator()
+		dto.ValidateTelegramImportRequest(v, input)
+		if !v.Valid() {
+			logger.Error("invalid import input", "errors", v.Errors)
+			return
+		}
+
+		postDB := postgresql.NewPostgresql(
+			postgresql.WithHost(cfg.Postgresql.Host),
+			postgresql.WithPort(cfg.Postgresql.Port),
+			postgresql.WithUser(cfg.Postgresql.User),
+			postgresql.WithPassword(cfg.Postgresql.Password),
+			postgresql.WithName(cfg.Postgresql.Name),
+			postgresql.WithMaxOpenConn(cfg.Postgresql.MaxOpenConn),
+			postgresql.WithMaxIdleConn(cfg.Postgresql.MaxIdleConn),
+			postgresql.WithMaxIdleTime(cfg.Postgresql.MaxIdleTime),
+			postgresql.WithSSLMode(cfg.Postgresql.SSLMode),
+			postgresql.WithTimeout(cfg.Postgresql.Timeout),
+			postgresql.WithLogger(logger),
+		)
+
+		gormDB, _, err := postDB.Connect()
+		if err != nil {
+			logger.Error("failed to connect to the database", "error", err)
+			return
+		}
+
+		fileStore := filestore.NewLocal(
+			filestore.WithRoot("files"),
+			filestore.WithURLPrefix("/v1/files"),
+		)
+
+		importService := service.NewImportService(
+			repository.NewImportRepository(gormDB, gormDB),
+			repository.NewUserRepository(gormDB, gormDB),
+			repository.NewPrivateRepository(gormDB, gormDB),
+			fileStore,
+			logger,
+			cfg,
+		)
+
+		job, err := importService.ImportTelegram(context.Background(), input)
+		if err != nil {
+			logger.Error("failed to import telegram export", "error", err)
+			return
+		}
+
+		logger.Info("import finished", "job", job.Id, "status", job.Status, "total", job.TotalMessages, "imported", job.ImportedMessages, "skipped", job.SkippedMessages)
+	},
+}
+
+func init() {
+	importTelegramCmd.Flags().StringVarP(&importExportPath, "path", "p", "", "Path to result.json or the export directory containing it")
+	importTelegramCmd.Flags().StringVar(&importMappingFile, "mapping", "", "JSON file mapping export user ids to account emails")
+	importTelegramCmd.Flags().StringToStringVar(&importUserMapping, "map", nil, "Export user id to account email, e.g. --map user123456=alice@example.com")
+	importTelegramCmd.Flags().StringVar(&importTimezone, "timezone", "", "IANA time zone the export was made in, for exports without Unix timestamps (default UTC)")
+	_ = importTelegramCmd.MarkFlagRequired("path")
+	rootCmd.AddCommand(importTelegramCmd)
+}
@@ -47,7 +47,7 @@ var migrateDownCmd = &cobra.Command{
 			return
 		}
 
-		if err := gormDB.Migrator().DropTable(&domain.User{}, &domain.Private{}, &domain.Message{}); err != nil {
+		if err := gormDB.Migrator().DropTable(&domain.User{}, &domain.UsernameChange{}, &domain.Session{}, &domain.RefreshToken{}, &domain.PasswordReset{}, &domain.TwoFactor{}, &domain.RecoveryCode{}, &domain.AuditLog{}, &domain.UserIdentity{}, &domain.PersonalAccessToken{}, &domain.Private{}, &domain.Message{}, &domain.ImportJob{}, &domain.ImportedMessage{}, &domain.Bot{}, &domain.BotUpdate{}, &domain.BotCallbackQuery{}, &domain.Webhook{}, &domain.WebhookDelivery{}, &domain.IncomingWebhook{}, &domain.Contact{}, &domain.Block{}, &domain.PrivacyRule{}); err != nil {
 			logger.Error("failed to drop table", "error", err)
 			return
 		}
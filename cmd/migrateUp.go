@@ -48,10 +48,56 @@ var migrateUpCmd = &cobra.Command{
 			return
 		}
 
-		if err := gormDB.Migrator().AutoMigrate(&domain.User{}, &domain.Private{}, &domain.Message{}); err != nil {
+		// pg_trgm backs the fuzzy username search
+		if err := gormDB.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+			logger.Error("failed to create pg_trgm extension", "error", err)
+			return
+		}
+
+		// Accounts from before email verification are trusted as they are
+		verifyExisting := gormDB.Migrator().HasTable(&domain.User{}) && !gormDB.Migrator().HasColumn(&domain.User{}, "email_verified_at")
+
+		if err := gormDB.Migrator().AutoMigrate(&domain.User{}, &domain.UsernameChange{}, &domain.Session{}, &domain.RefreshToken{}, &domain.PasswordReset{}, &domain.TwoFactor{}, &domain.RecoveryCode{}, &domain.AuditLog{}, &domain.UserIdentity{}, &domain.PersonalAccessToken{}, &domain.Private{}, &domain.Message{}, &domain.ImportJob{}, &domain.ImportedMessage{}, &domain.Bot{}, &domain.BotUpdate{}, &domain.BotCallbackQuery{}, &domain.Webhook{}, &domain.WebhookDelivery{}, &domain.IncomingWebhook{}, &domain.Contact{}, &domain.Block{}, &domain.PrivacyRule{}); err != nil {
 			logger.Error("failed to migrate up", "error", err)
 			return
 		}
+
+		// Contact import looks accounts up by lower(email)
+		if err := gormDB.Exec("CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email))").Error; err != nil {
+			logger.Error("failed to create users email index", "error", err)
+			return
+		}
+
+		if verifyExisting {
+			if err := gormDB.Exec("UPDATE users SET email_verified_at = created_at").Error; err != nil {
+				logger.Error("failed to verify existing users", "error", err)
+				return
+			}
+		}
+
+		// Refresh tokens belong to sessions now, don't keep the old ones around
+		for _, column := range []string{"refresh_token_web", "refresh_token_web_at", "refresh_token_mobile", "refresh_token_mobile_at"} {
+			if !gormDB.Migrator().HasColumn(&domain.User{}, column) {
+				continue
+			}
+			if err := gormDB.Migrator().DropColumn(&domain.User{}, column); err != nil {
+				logger.Error("failed to drop users column", "column", column, "error", err)
+				return
+			}
+		}
+
+		// Sessions only keep hashes of their refresh tokens in refresh_tokens. The
+		// old sessions have no token left to refresh with, so they go as well
+		if gormDB.Migrator().HasColumn(&domain.Session{}, "refresh_token") {
+			if err := gormDB.Exec("DELETE FROM sessions").Error; err != nil {
+				logger.Error("failed to delete old sessions", "error", err)
+				return
+			}
+			if err := gormDB.Migrator().DropColumn(&domain.Session{}, "refresh_token"); err != nil {
+				logger.Error("failed to drop sessions column", "column", "refresh_token", "error", err)
+				return
+			}
+		}
 	},
 }
 
@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/filestore"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/keyring"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/mailer"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/oidc"
 	"github.com/saleh-ghazimoradi/TeleGopher/infra/postgresql"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/revocation"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/throttle"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/route"
@@ -13,7 +21,9 @@ import (
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
@@ -46,6 +56,18 @@ var runCmd = &cobra.Command{
 			return
 		}
 
+		platforms := slices.Concat(
+			slices.Collect(maps.Keys(cfg.Platforms.AccessTokenExpires)),
+			slices.Collect(maps.Keys(cfg.Platforms.RefreshTokenExpires)),
+			slices.Collect(maps.Keys(cfg.Platforms.MaxSessions)),
+		)
+		for _, platform := range platforms {
+			if !domain.IsValidPlatform(platform) {
+				logger.Error("unknown platform in config", "platform", platform)
+				return
+			}
+		}
+
 		/*----------Postgresql----------*/
 		postDB := postgresql.NewPostgresql(
 			postgresql.WithHost(cfg.Postgresql.Host),
@@ -68,30 +90,128 @@ var runCmd = &cobra.Command{
 		}
 
 		/*----------Dependencies----------*/
-		middlewares := middleware.NewMiddleware(logger, cfg)
+		revocationStore := revocation.NewMemory()
+		throttleStore := throttle.NewMemory()
+		tokenKeys := keyring.NewKeyring(
+			keyring.WithAlgorithm(cfg.JWT.Algorithm),
+			keyring.WithSecret(cfg.JWT.Secret),
+			keyring.WithDir(cfg.JWT.KeysDir),
+			keyring.WithRotationInterval(cfg.JWT.KeyRotationInterval),
+			keyring.WithOverlap(max(cfg.JWT.KeyOverlap, cfg.MaxAccessTokenExpires())),
+			keyring.WithHS256Fallback(cfg.JWT.HS256Fallback),
+			keyring.WithLogger(logger),
+		)
+		if err := tokenKeys.Load(); err != nil {
+			logger.Error("failed to load signing keys", "error", err)
+			return
+		}
+		fileStore := filestore.NewLocal(
+			filestore.WithRoot("files"),
+			filestore.WithURLPrefix("/v1/files"),
+		)
+
+		var mail mailer.Mailer
+		switch cfg.Mail.Driver {
+		case "smtp":
+			mail = mailer.NewSMTP(
+				mailer.WithHost(cfg.Mail.SMTPHost),
+				mailer.WithPort(cfg.Mail.SMTPPort),
+				mailer.WithUsername(cfg.Mail.SMTPUsername),
+				mailer.WithPassword(cfg.Mail.SMTPPassword),
+				mailer.WithFrom(cfg.Mail.From),
+			)
+		case "file":
+			mail = mailer.NewFile(cfg.Mail.FileDir, cfg.Mail.From)
+		case "log":
+			mail = mailer.NewLog(logger)
+		default:
+			logger.Error("unknown mail driver", "driver", cfg.Mail.Driver)
+			return
+		}
+
+		var oidcProvider *oidc.Provider
+		if cfg.OIDC.Issuer != "" {
+			oidcProvider = oidc.NewProvider(
+				oidc.WithIssuer(cfg.OIDC.Issuer),
+				oidc.WithClientId(cfg.OIDC.ClientId),
+				oidc.WithClientSecret(cfg.OIDC.ClientSecret),
+				oidc.WithRedirectURL(cfg.OIDC.RedirectURL),
+				oidc.WithScopes(cfg.OIDC.Scopes),
+			)
+		}
 
 		/*----------Repositories----------*/
 		userRepository := repository.NewUserRepository(gormDB, gormDB)
 		privateRepository := repository.NewPrivateRepository(gormDB, gormDB)
 		messageRepository := repository.NewMessageRepository(gormDB, gormDB)
+		importRepository := repository.NewImportRepository(gormDB, gormDB)
+		botRepository := repository.NewBotRepository(gormDB, gormDB)
+		webhookRepository := repository.NewWebhookRepository(gormDB, gormDB)
+		incomingWebhookRepository := repository.NewIncomingWebhookRepository(gormDB, gormDB)
+		contactRepository := repository.NewContactRepository(gormDB, gormDB)
+		blockRepository := repository.NewBlockRepository(gormDB, gormDB)
+		privacyRepository := repository.NewPrivacyRepository(gormDB, gormDB)
+		sessionRepository := repository.NewSessionRepository(gormDB, gormDB)
+		passwordResetRepository := repository.NewPasswordResetRepository(gormDB, gormDB)
+		twoFactorRepository := repository.NewTwoFactorRepository(gormDB, gormDB)
+		auditLogRepository := repository.NewAuditLogRepository(gormDB, gormDB)
+		userIdentityRepository := repository.NewUserIdentityRepository(gormDB, gormDB)
+		accountDeletionRepository := repository.NewAccountDeletionRepository(gormDB, gormDB)
+		personalAccessTokenRepository := repository.NewPersonalAccessTokenRepository(gormDB, gormDB)
+		adminRepository := repository.NewAdminRepository(gormDB, gormDB)
 
 		/*----------Services----------*/
-		authService := service.NewAuthService(userRepository, cfg)
-		userService := service.NewUserService(userRepository)
-		privateService := service.NewPrivateService(privateRepository, userRepository)
-		messageService := service.NewMessageService(messageRepository, privateRepository)
+		twoFactorService := service.NewTwoFactorService(twoFactorRepository, userRepository, cfg)
+		authService := service.NewAuthService(userRepository, sessionRepository, passwordResetRepository, personalAccessTokenRepository, auditLogRepository, twoFactorService, revocationStore, tokenKeys, throttleStore, mail, logger, cfg)
+		oidcService := service.NewOIDCService(oidcProvider, authService, userRepository, userIdentityRepository, logger, cfg)
+		qrLoginService := service.NewQRLoginService(authService, cfg)
+		privacyService := service.NewPrivacyService(privacyRepository, contactRepository)
+		lastSeenService := service.NewLastSeenService(userRepository, logger, cfg)
+		userService := service.NewUserService(userRepository, privacyService, blockRepository, fileStore, logger)
+		privateService := service.NewPrivateService(privateRepository, userRepository, blockRepository, privacyService)
+		botUpdateService := service.NewBotUpdateService(botRepository, logger)
+		webhookService := service.NewWebhookService(webhookRepository, privateRepository, logger, cfg)
+		messageService := service.NewMessageService(messageRepository, privateRepository, blockRepository, botUpdateService, webhookService)
+		importService := service.NewImportService(importRepository, userRepository, privateRepository, fileStore, logger, cfg)
+		if err := importService.FailInterruptedJobs(context.Background()); err != nil {
+			logger.Error("failed to clean up import jobs", "error", err)
+		}
+		botService := service.NewBotService(botRepository, userRepository, privateRepository, messageRepository, messageService, botUpdateService, webhookService)
+		incomingWebhookService := service.NewIncomingWebhookService(incomingWebhookRepository, privateRepository, messageService, cfg)
+		contactService := service.NewContactService(contactRepository, userRepository, blockRepository, privacyService)
+		blockService := service.NewBlockService(blockRepository, userRepository)
+		accountDeletionService := service.NewAccountDeletionService(accountDeletionRepository, userRepository, privateRepository, revocationStore, fileStore, logger, cfg)
+		personalAccessTokenService := service.NewPersonalAccessTokenService(personalAccessTokenRepository, logger)
+		adminService := service.NewAdminService(adminRepository, userRepository, sessionRepository, personalAccessTokenRepository, auditLogRepository, revocationStore, logger, cfg)
+
+		/*----------Middleware----------*/
+		middlewares := middleware.NewMiddleware(logger, revocationStore, tokenKeys, personalAccessTokenService, adminService, cfg)
 
 		/*----------WS HUB----------*/
-		wsHub := ws.NewHub(privateService, messageService, logger)
+		wsHub := ws.NewHub(privateService, messageService, blockService, privacyService, lastSeenService, logger)
 
 		/*----------Handlers----------*/
 		healthCheck := handler.NewHealthCheckHandler(cfg)
-		authHandler := handler.NewAuthHandler(authService)
-		userHandler := handler.NewUserHandler(userService)
+		authHandler := handler.NewAuthHandler(authService, wsHub)
+		userHandler := handler.NewUserHandler(userService, privateService, wsHub)
 		privateHandler := handler.NewPrivateHandler(privateService)
 		messageHandler := handler.NewMessageHandler(messageService)
-		uploadFileHandler := handler.NewUploadFileHandler()
-		wsHandler := handler.NewWebSocketHandler(userService, messageService, logger, wsHub, cfg)
+		uploadFileHandler := handler.NewUploadFileHandler(fileStore)
+		wsHandler := handler.NewWebSocketHandler(userService, messageService, botService, blockService, revocationStore, tokenKeys, logger, wsHub, cfg)
+		importHandler := handler.NewImportHandler(importService)
+		botHandler := handler.NewBotHandler(botService, wsHub)
+		webhookHandler := handler.NewWebhookHandler(webhookService)
+		incomingWebhookHandler := handler.NewIncomingWebhookHandler(incomingWebhookService, wsHub)
+		contactHandler := handler.NewContactHandler(contactService, wsHub)
+		blockHandler := handler.NewBlockHandler(blockService)
+		privacyHandler := handler.NewPrivacyHandler(privacyService)
+		twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+		jwksHandler := handler.NewJWKSHandler(tokenKeys)
+		oidcHandler := handler.NewOIDCHandler(oidcService)
+		accountDeletionHandler := handler.NewAccountDeletionHandler(accountDeletionService)
+		qrLoginHandler := handler.NewQRLoginHandler(qrLoginService, logger)
+		personalAccessTokenHandler := handler.NewPersonalAccessTokenHandler(personalAccessTokenService)
+		adminHandler := handler.NewAdminHandler(adminService, wsHub)
 
 		/*----------Routes----------*/
 		healthRoute := route.NewHealthCheckRoute(healthCheck)
@@ -101,6 +221,20 @@ var runCmd = &cobra.Command{
 		messageRoute := route.NewMessageRoute(middlewares, messageHandler)
 		uploadFileRoute := route.NewUploadFileRoute(middlewares, uploadFileHandler)
 		wsRoute := route.NewWSRoute(wsHandler)
+		importRoute := route.NewImportRoute(middlewares, importHandler)
+		botRoute := route.NewBotRoute(middlewares, botHandler)
+		webhookRoute := route.NewWebhookRoute(middlewares, webhookHandler)
+		incomingWebhookRoute := route.NewIncomingWebhookRoute(middlewares, incomingWebhookHandler)
+		contactRoute := route.NewContactRoute(middlewares, contactHandler)
+		blockRoute := route.NewBlockRoute(middlewares, blockHandler)
+		privacyRoute := route.NewPrivacyRoute(middlewares, privacyHandler)
+		twoFactorRoute := route.NewTwoFactorRoute(middlewares, twoFactorHandler)
+		jwksRoute := route.NewJWKSRoute(jwksHandler)
+		oidcRoute := route.NewOIDCRoute(oidcHandler)
+		accountDeletionRoute := route.NewAccountDeletionRoute(middlewares, accountDeletionHandler)
+		qrLoginRoute := route.NewQRLoginRoute(middlewares, qrLoginHandler)
+		personalAccessTokenRoute := route.NewPersonalAccessTokenRoute(middlewares, personalAccessTokenHandler)
+		adminRoute := route.NewAdminRoute(middlewares, adminHandler)
 
 		/*----------Route Registery----------*/
 		register := route.NewRegisterRoute(
@@ -112,6 +246,20 @@ var runCmd = &cobra.Command{
 			route.WithMessageRoute(messageRoute),
 			route.WithUploadFileRoute(uploadFileRoute),
 			route.WithWsRoute(wsRoute),
+			route.WithImportRoute(importRoute),
+			route.WithBotRoute(botRoute),
+			route.WithWebhookRoute(webhookRoute),
+			route.WithIncomingWebhookRoute(incomingWebhookRoute),
+			route.WithContactRoute(contactRoute),
+			route.WithBlockRoute(blockRoute),
+			route.WithPrivacyRoute(privacyRoute),
+			route.WithTwoFactorRoute(twoFactorRoute),
+			route.WithJWKSRoute(jwksRoute),
+			route.WithOIDCRoute(oidcRoute),
+			route.WithAccountDeletionRoute(accountDeletionRoute),
+			route.WithQRLoginRoute(qrLoginRoute),
+			route.WithPersonalAccessTokenRoute(personalAccessTokenRoute),
+			route.WithAdminRoute(adminRoute),
 		)
 
 		/*----------HTTP Server----------*/
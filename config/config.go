@@ -13,10 +13,33 @@ var (
 )
 
 type Config struct {
-	Application Application
-	JWT         JWT
-	Postgresql  Postgresql
-	Server      Server
+	AccountDeletion AccountDeletion
+	Application     Application
+	Admin           Admin
+	Import          Import
+	IncomingWebhook IncomingWebhook
+	JWT             JWT
+	LastSeen        LastSeen
+	LoginThrottle   LoginThrottle
+	Mail            Mail
+	OIDC            OIDC
+	PasswordReset   PasswordReset
+	Platforms       Platforms
+	Postgresql      Postgresql
+	QRLogin         QRLogin
+	Server          Server
+	TwoFactor       TwoFactor
+	Verification    Verification
+	Webhook         Webhook
+}
+
+// AccountDeletion anonymizes accounts GracePeriod after their owner asked for
+// it. Messages is keep, leaving text messages under "Deleted Account", or
+// purge, removing them as well.
+type AccountDeletion struct {
+	GracePeriod   time.Duration `env:"ACCOUNT_DELETION_GRACE_PERIOD" envDefault:"720h"`
+	Messages      string        `env:"ACCOUNT_DELETION_MESSAGES" envDefault:"keep"`
+	CheckInterval time.Duration `env:"ACCOUNT_DELETION_CHECK_INTERVAL" envDefault:"1h"`
 }
 
 type Application struct {
@@ -24,6 +47,84 @@ type Application struct {
 	Environment string `env:"APP_ENVIRONMENT"`
 }
 
+// Admin lists users who are admins whatever role they have in the database,
+// so there's always someone to hand out roles and they can't be demoted.
+type Admin struct {
+	UserIds []uint `env:"ADMIN_USER_IDS" envSeparator:","`
+}
+
+type Import struct {
+	RootDir string `env:"IMPORT_ROOT_DIR"`
+}
+
+type IncomingWebhook struct {
+	RatePerMinute int `env:"INCOMING_WEBHOOK_RATE_PER_MINUTE" envDefault:"20"`
+	Burst         int `env:"INCOMING_WEBHOOK_BURST" envDefault:"5"`
+}
+
+type LastSeen struct {
+	FlushInterval time.Duration `env:"LAST_SEEN_FLUSH_INTERVAL" envDefault:"10s"`
+}
+
+// LoginThrottle slows down password guessing. After the free attempts every
+// failure doubles the wait, starting at BaseDelay and capped at MaxDelay, and
+// reaching the lockout threshold blocks the account or IP for LockoutDuration.
+// Failures are forgotten after Window without any.
+type LoginThrottle struct {
+	Window              time.Duration `env:"LOGIN_THROTTLE_WINDOW" envDefault:"15m"`
+	BaseDelay           time.Duration `env:"LOGIN_THROTTLE_BASE_DELAY" envDefault:"1s"`
+	MaxDelay            time.Duration `env:"LOGIN_THROTTLE_MAX_DELAY" envDefault:"5m"`
+	LockoutDuration     time.Duration `env:"LOGIN_THROTTLE_LOCKOUT_DURATION" envDefault:"15m"`
+	AccountFreeAttempts int           `env:"LOGIN_THROTTLE_ACCOUNT_FREE_ATTEMPTS" envDefault:"3"`
+	AccountLockoutAfter int           `env:"LOGIN_THROTTLE_ACCOUNT_LOCKOUT_AFTER" envDefault:"10"`
+	IPFreeAttempts      int           `env:"LOGIN_THROTTLE_IP_FREE_ATTEMPTS" envDefault:"10"`
+	IPLockoutAfter      int           `env:"LOGIN_THROTTLE_IP_LOCKOUT_AFTER" envDefault:"50"`
+	SignupIPRatePerHour int           `env:"LOGIN_THROTTLE_SIGNUP_IP_RATE_PER_HOUR" envDefault:"10"`
+}
+
+// Mail picks the mailer by Driver: smtp, file (writes .eml files into FileDir)
+// or log.
+type Mail struct {
+	Driver       string `env:"MAIL_DRIVER" envDefault:"log"`
+	From         string `env:"MAIL_FROM" envDefault:"TeleGopher <no-reply@telegopher.local>"`
+	SMTPHost     string `env:"MAIL_SMTP_HOST"`
+	SMTPPort     string `env:"MAIL_SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"MAIL_SMTP_USERNAME"`
+	SMTPPassword string `env:"MAIL_SMTP_PASSWORD"`
+	FileDir      string `env:"MAIL_FILE_DIR" envDefault:"mails"`
+}
+
+// OIDC enables single sign-on with an OpenID Connect provider when Issuer is
+// set. RedirectURL is the client page the provider sends the user back to, it
+// passes code and state on to /v1/auth/oidc/callback.
+type OIDC struct {
+	Issuer       string        `env:"OIDC_ISSUER"`
+	ClientId     string        `env:"OIDC_CLIENT_ID"`
+	ClientSecret string        `env:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string        `env:"OIDC_REDIRECT_URL" envDefault:"http://localhost:3000/oidc/callback"`
+	Scopes       []string      `env:"OIDC_SCOPES" envSeparator:"," envDefault:"openid,email,profile"`
+	StateExpires time.Duration `env:"OIDC_STATE_EXPIRES" envDefault:"10m"`
+}
+
+// PasswordReset limits how often reset emails can be requested, per address
+// and per client IP, so the endpoint can't be used to flood inboxes.
+type PasswordReset struct {
+	TokenExpires     time.Duration `env:"PASSWORD_RESET_TOKEN_EXPIRES" envDefault:"1h"`
+	URL              string        `env:"PASSWORD_RESET_URL" envDefault:"http://localhost:3000/reset-password"`
+	EmailRatePerHour int           `env:"PASSWORD_RESET_EMAIL_RATE_PER_HOUR" envDefault:"3"`
+	IPRatePerHour    int           `env:"PASSWORD_RESET_IP_RATE_PER_HOUR" envDefault:"20"`
+}
+
+// Platforms overrides token lifetimes and limits concurrent sessions per
+// platform, as comma separated platform:value pairs, e.g.
+// PLATFORM_MAX_SESSIONS=mobile:2,api:10. Platforms not listed get the JWT
+// lifetimes and any number of sessions.
+type Platforms struct {
+	AccessTokenExpires  map[string]time.Duration `env:"PLATFORM_ACCESS_TOKEN_EXPIRES" envKeyValSeparator:":"`
+	RefreshTokenExpires map[string]time.Duration `env:"PLATFORM_REFRESH_TOKEN_EXPIRES" envKeyValSeparator:":"`
+	MaxSessions         map[string]int           `env:"PLATFORM_MAX_SESSIONS" envKeyValSeparator:":"`
+}
+
 type Postgresql struct {
 	Host        string        `env:"POSTGRES_HOST"`
 	Port        string        `env:"POSTGRES_PORT"`
@@ -37,10 +138,27 @@ type Postgresql struct {
 	Timeout     time.Duration `env:"POSTGRES_TIMEOUT"`
 }
 
+// QRLogin lets a logged-in device approve a login shown as a QR code on a
+// new one. Each code is valid for TokenExpires and only while the new device
+// stays connected.
+type QRLogin struct {
+	TokenExpires    time.Duration `env:"QR_LOGIN_TOKEN_EXPIRES" envDefault:"2m"`
+	IPRatePerMinute int           `env:"QR_LOGIN_IP_RATE_PER_MINUTE" envDefault:"10"`
+}
+
+// JWT signs access tokens with Algorithm: HS256 with Secret, or RS256 or
+// EdDSA with rotating keys kept in KeysDir. Secret is needed either way, it
+// also signs email and login challenge tokens. HS256Fallback keeps accepting
+// HS256 access tokens after switching to an asymmetric algorithm.
 type JWT struct {
 	Secret              string        `env:"JWT_SECRET"`
 	ExpiresIn           time.Duration `env:"JWT_EXPIRES_IN"`
-	RefreshTokenExpires time.Duration `env:"JWT_REFRESH_TOKEN_EXPIRES"`
+	RefreshTokenExpires time.Duration `env:"JWT_REFRESH_TOKEN_EXPIRES" envDefault:"720h"`
+	Algorithm           string        `env:"JWT_ALGORITHM" envDefault:"HS256"`
+	KeysDir             string        `env:"JWT_KEYS_DIR" envDefault:"keys"`
+	KeyRotationInterval time.Duration `env:"JWT_KEY_ROTATION_INTERVAL" envDefault:"720h"`
+	KeyOverlap          time.Duration `env:"JWT_KEY_OVERLAP" envDefault:"24h"`
+	HS256Fallback       bool          `env:"JWT_HS256_FALLBACK" envDefault:"true"`
 }
 
 type Server struct {
@@ -51,6 +169,59 @@ type Server struct {
 	WriteTimeout time.Duration `env:"SERVER_WRITE_TIMEOUT"`
 }
 
+type TwoFactor struct {
+	Issuer            string        `env:"TWO_FACTOR_ISSUER" envDefault:"TeleGopher"`
+	ChallengeExpires  time.Duration `env:"TWO_FACTOR_CHALLENGE_EXPIRES" envDefault:"5m"`
+	RecoveryCodes     int           `env:"TWO_FACTOR_RECOVERY_CODES" envDefault:"10"`
+	AttemptsPerMinute int           `env:"TWO_FACTOR_ATTEMPTS_PER_MINUTE" envDefault:"5"`
+}
+
+// Verification configures email verification. UnverifiedCapabilities lists
+// what accounts with an unverified email may still do: login, messaging
+// and bots.
+type Verification struct {
+	TokenExpires           time.Duration `env:"VERIFICATION_TOKEN_EXPIRES" envDefault:"24h"`
+	ResendCooldown         time.Duration `env:"VERIFICATION_RESEND_COOLDOWN" envDefault:"1m"`
+	URL                    string        `env:"VERIFICATION_URL" envDefault:"http://localhost:3000/verify-email"`
+	UnverifiedCapabilities []string      `env:"VERIFICATION_UNVERIFIED_CAPABILITIES" envSeparator:"," envDefault:"login"`
+}
+
+type Webhook struct {
+	Timeout        time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	MaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	InitialBackoff time.Duration `env:"WEBHOOK_INITIAL_BACKOFF" envDefault:"2s"`
+	MaxFailures    int           `env:"WEBHOOK_MAX_FAILURES" envDefault:"10"`
+}
+
+func (c *Config) AccessTokenExpires(platform string) time.Duration {
+	if expires, ok := c.Platforms.AccessTokenExpires[platform]; ok {
+		return expires
+	}
+	return c.JWT.ExpiresIn
+}
+
+// MaxAccessTokenExpires is the longest lifetime of any access token, the time
+// revocations have to be kept for.
+func (c *Config) MaxAccessTokenExpires() time.Duration {
+	longest := c.JWT.ExpiresIn
+	for _, expires := range c.Platforms.AccessTokenExpires {
+		longest = max(longest, expires)
+	}
+	return longest
+}
+
+func (c *Config) RefreshTokenExpires(platform string) time.Duration {
+	if expires, ok := c.Platforms.RefreshTokenExpires[platform]; ok {
+		return expires
+	}
+	return c.JWT.RefreshTokenExpires
+}
+
+// MaxSessions is 0 for platforms without a limit.
+func (c *Config) MaxSessions(platform string) int {
+	return c.Platforms.MaxSessions[platform]
+}
+
 func GetCfg() (*Config, error) {
 	once.Do(func() {
 		cfg = &Config{}
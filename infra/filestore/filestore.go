@@ -0,0 +1,121 @@
+package filestore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid file key")
+
+type FileStore interface {
+	Save(key string, r io.Reader) (string, error)
+	URL(key string) string
+	Key(url string) (string, bool)
+	Delete(key string) error
+}
+
+type Local struct {
+	Root      string
+	URLPrefix string
+}
+
+type Options func(*Local)
+
+func WithRoot(root string) Options {
+	return func(l *Local) {
+		l.Root = root
+	}
+}
+
+func WithURLPrefix(prefix string) Options {
+	return func(l *Local) {
+		l.URLPrefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// Save writes r under the slash separated key, replacing any existing file,
+// and returns the public URL of the stored file.
+func (l *Local) Save(key string, r io.Reader) (string, error) {
+	filePath, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, r); err != nil {
+		return "", fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	return l.URL(key), nil
+}
+
+// Delete removes the file stored under key, or everything below it when key
+// names a directory. Missing files are not an error.
+func (l *Local) Delete(key string) error {
+	filePath, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(filePath); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) URL(key string) string {
+	segments := strings.Split(path.Clean(key), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return l.URLPrefix + "/" + strings.Join(segments, "/")
+}
+
+// Key is the reverse of URL, it reports false for URLs of other stores.
+func (l *Local) Key(fileURL string) (string, bool) {
+	escaped, ok := strings.CutPrefix(fileURL, l.URLPrefix+"/")
+	if !ok {
+		return "", false
+	}
+
+	segments := strings.Split(escaped, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return "", false
+		}
+		segments[i] = unescaped
+	}
+	return strings.Join(segments, "/"), true
+}
+
+func (l *Local) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || cleaned != "/"+key {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.Root, filepath.FromSlash(cleaned)), nil
+}
+
+func NewLocal(opts ...Options) *Local {
+	l := &Local{}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
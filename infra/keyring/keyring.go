@@ -0,0 +1,428 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// JWK is the public half of a signing key, as published in the JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type key struct {
+	id        string
+	algorithm string
+	createdAt time.Time
+	signer    crypto.Signer
+}
+
+// Keyring signs access tokens and hands out the keys to verify them.
+//
+// With HS256 every token is signed with Secret. With RS256 or EdDSA the keys
+// are kept as PEM files in Dir and identified by the kid header. A new key is
+// created Overlap before the current one is RotationInterval old, so it is in
+// the JWKS before it signs anything, and a replaced key keeps verifying for
+// Overlap after its successor took over. Overlap should be at least the access
+// token lifetime.
+//
+// HS256Fallback keeps accepting HS256 tokens while signing asymmetrically, for
+// the tokens issued before switching.
+type Keyring struct {
+	Algorithm        string
+	Secret           string
+	Dir              string
+	RotationInterval time.Duration
+	Overlap          time.Duration
+	HS256Fallback    bool
+	logger           utils.LoggerStrategy
+
+	mu        sync.RWMutex
+	keys      []*key
+	lastCheck time.Time
+}
+
+type Options func(*Keyring)
+
+func WithAlgorithm(algorithm string) Options {
+	return func(k *Keyring) {
+		k.Algorithm = algorithm
+	}
+}
+
+func WithSecret(secret string) Options {
+	return func(k *Keyring) {
+		k.Secret = secret
+	}
+}
+
+func WithDir(dir string) Options {
+	return func(k *Keyring) {
+		k.Dir = dir
+	}
+}
+
+func WithRotationInterval(interval time.Duration) Options {
+	return func(k *Keyring) {
+		k.RotationInterval = interval
+	}
+}
+
+func WithOverlap(overlap time.Duration) Options {
+	return func(k *Keyring) {
+		k.Overlap = overlap
+	}
+}
+
+func WithHS256Fallback(fallback bool) Options {
+	return func(k *Keyring) {
+		k.HS256Fallback = fallback
+	}
+}
+
+func WithLogger(logger utils.LoggerStrategy) Options {
+	return func(k *Keyring) {
+		k.logger = logger
+	}
+}
+
+// Load reads the keys from Dir and creates the first one if there is none.
+func (k *Keyring) Load() error {
+	switch k.Algorithm {
+	case AlgorithmHS256:
+		if k.Secret == "" {
+			return errors.New("HS256 needs a secret")
+		}
+		return nil
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", k.Algorithm)
+	}
+
+	if k.RotationInterval <= k.Overlap {
+		return errors.New("key rotation interval must be longer than the overlap")
+	}
+
+	if err := os.MkdirAll(k.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.rotate(time.Now())
+}
+
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	if k.Algorithm == AlgorithmHS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(k.Secret))
+	}
+
+	k.check()
+
+	k.mu.RLock()
+	signingKey := k.signingKey(time.Now())
+	k.mu.RUnlock()
+
+	if signingKey == nil {
+		return "", ErrUnknownKey
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(signingKey.algorithm), claims)
+	token.Header["kid"] = signingKey.id
+	return token.SignedString(signingKey.signer)
+}
+
+// Keyfunc finds the key to verify token with. The algorithm has to match the
+// key's, so a public key is never mistaken for an HMAC secret.
+func (k *Keyring) Keyfunc(token *jwt.Token) (any, error) {
+	if token.Method == jwt.SigningMethodHS256 {
+		if k.Algorithm == AlgorithmHS256 || k.HS256Fallback {
+			return []byte(k.Secret), nil
+		}
+		return nil, errors.New("unexpected signing method")
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	verificationKey := k.find(kid)
+	if verificationKey == nil {
+		// Another instance may have created it since we last looked
+		k.check()
+		if verificationKey = k.find(kid); verificationKey == nil {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	if token.Method.Alg() != verificationKey.algorithm {
+		return nil, errors.New("unexpected signing method")
+	}
+	return verificationKey.signer.Public(), nil
+}
+
+// JWKS lists the public keys tokens may currently be signed with, including
+// the next one.
+func (k *Keyring) JWKS() *JWKS {
+	jwks := &JWKS{Keys: []JWK{}}
+	if k.Algorithm == AlgorithmHS256 {
+		return jwks
+	}
+
+	k.check()
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, publicKey := range k.keys {
+		jwk := JWK{Kid: publicKey.id, Use: "sig", Alg: publicKey.algorithm}
+		switch pub := publicKey.signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
+
+// check rotates at most once a minute, on the first use after it's due.
+func (k *Keyring) check() {
+	now := time.Now()
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if now.Sub(k.lastCheck) < time.Minute {
+		return
+	}
+
+	if err := k.rotate(now); err != nil {
+		k.logger.Error("failed to rotate signing keys", "error", err)
+	}
+}
+
+// rotate picks up keys other instances created, creates the next key when
+// it's due and removes keys no token can be signed with anymore.
+func (k *Keyring) rotate(now time.Time) error {
+	k.lastCheck = now
+
+	if err := k.read(); err != nil {
+		return err
+	}
+
+	if newest := k.newest(); newest == nil || now.Sub(newest.createdAt) >= k.RotationInterval-k.Overlap {
+		next, err := k.generate(now)
+		if err != nil {
+			return err
+		}
+		k.keys = append(k.keys, next)
+	}
+
+	for i := 0; i < len(k.keys)-1; i++ {
+		successor := k.keys[i+1]
+		if now.Before(successor.createdAt.Add(2 * k.Overlap)) {
+			continue
+		}
+		if err := os.Remove(k.path(k.keys[i].id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove expired key: %w", err)
+		}
+		k.keys = slices.Delete(k.keys, i, i+1)
+		i--
+	}
+	return nil
+}
+
+// signingKey is the newest key of the configured algorithm that has been
+// published for Overlap, or the newest one at all when none has.
+func (k *Keyring) signingKey(now time.Time) *key {
+	var newest *key
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if k.keys[i].algorithm != k.Algorithm {
+			continue
+		}
+		if newest == nil {
+			newest = k.keys[i]
+		}
+		if !now.Before(k.keys[i].createdAt.Add(k.Overlap)) {
+			return k.keys[i]
+		}
+	}
+	return newest
+}
+
+func (k *Keyring) find(id string) *key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, found := range k.keys {
+		if found.id == id {
+			return found
+		}
+	}
+	return nil
+}
+
+func (k *Keyring) newest() *key {
+	for i := len(k.keys) - 1; i >= 0; i-- {
+		if k.keys[i].algorithm == k.Algorithm {
+			return k.keys[i]
+		}
+	}
+	return nil
+}
+
+func (k *Keyring) read() error {
+	entries, err := os.ReadDir(k.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read key directory: %w", err)
+	}
+
+	keys := make([]*key, 0, len(entries))
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".pem")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		loaded, err := k.load(id)
+		if err != nil {
+			return fmt.Errorf("failed to load key %s: %w", id, err)
+		}
+		keys = append(keys, loaded)
+	}
+
+	slices.SortFunc(keys, func(a, b *key) int {
+		return a.createdAt.Compare(b.createdAt)
+	})
+	k.keys = keys
+	return nil
+}
+
+func (k *Keyring) load(id string) (*key, error) {
+	data, err := os.ReadFile(k.path(id))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("not a PEM private key")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, block.Headers["Created"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid Created header: %w", err)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := &key{id: id, createdAt: createdAt}
+	switch privateKey := privateKey.(type) {
+	case *rsa.PrivateKey:
+		loaded.algorithm, loaded.signer = AlgorithmRS256, privateKey
+	case ed25519.PrivateKey:
+		loaded.algorithm, loaded.signer = AlgorithmEdDSA, privateKey
+	default:
+		return nil, errors.New("unsupported key type")
+	}
+	return loaded, nil
+}
+
+func (k *Keyring) generate(now time.Time) (*key, error) {
+	var signer crypto.Signer
+	switch k.Algorithm {
+	case AlgorithmRS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		signer = privateKey
+	case AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer = privateKey
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(b)
+
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:    "PRIVATE KEY",
+		Headers: map[string]string{"Created": now.UTC().Format(time.RFC3339)},
+		Bytes:   der,
+	})
+	if err := os.WriteFile(k.path(id), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to save key: %w", err)
+	}
+
+	k.logger.Info("created signing key", "kid", id, "algorithm", k.Algorithm)
+	return &key{id: id, algorithm: k.Algorithm, createdAt: now.UTC().Truncate(time.Second), signer: signer}, nil
+}
+
+func (k *Keyring) path(id string) string {
+	return filepath.Join(k.Dir, id+".pem")
+}
+
+func NewKeyring(opts ...Options) *Keyring {
+	k := &Keyring{}
+	for _, opt := range opts {
+		opt(k)
+	}
+	return k
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File writes every email into Dir as an .eml file instead of sending it, for
+// local development and tests.
+type File struct {
+	Dir  string
+	From string
+}
+
+func (f *File) Send(ctx context.Context, message *Message) error {
+	if err := validate(message); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.Dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create dir: %w", err)
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	if err := os.WriteFile(filepath.Join(f.Dir, name), compose(f.From, message), 0o600); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	return nil
+}
+
+func NewFile(dir, from string) *File {
+	return &File{
+		Dir:  dir,
+		From: from,
+	}
+}
@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+)
+
+// Log only logs emails. Their bodies carry secrets like verification links,
+// so it is not meant for production.
+type Log struct {
+	logger utils.LoggerStrategy
+}
+
+func (l *Log) Send(ctx context.Context, message *Message) error {
+	if err := validate(message); err != nil {
+		return err
+	}
+
+	l.logger.Info("mail", "to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
+
+func NewLog(logger utils.LoggerStrategy) *Log {
+	return &Log{
+		logger: logger,
+	}
+}
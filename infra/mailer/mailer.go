@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeader = errors.New("mail headers must not contain line breaks")
+
+// Mailer sends plain text emails.
+type Mailer interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// compose renders message as an RFC 5322 email from from.
+func compose(from string, message *Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// validHeader rejects values that would let a caller inject extra headers.
+func validHeader(value string) bool {
+	return !strings.ContainsAny(value, "\r\n")
+}
+
+func validate(message *Message) error {
+	if !validHeader(message.To) || !validHeader(message.Subject) {
+		return ErrInvalidHeader
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type SMTPOptions func(*SMTP)
+
+func WithHost(host string) SMTPOptions {
+	return func(s *SMTP) {
+		s.Host = host
+	}
+}
+
+func WithPort(port string) SMTPOptions {
+	return func(s *SMTP) {
+		s.Port = port
+	}
+}
+
+func WithUsername(username string) SMTPOptions {
+	return func(s *SMTP) {
+		s.Username = username
+	}
+}
+
+func WithPassword(password string) SMTPOptions {
+	return func(s *SMTP) {
+		s.Password = password
+	}
+}
+
+func WithFrom(from string) SMTPOptions {
+	return func(s *SMTP) {
+		s.From = from
+	}
+}
+
+// Send uses STARTTLS whenever the server offers it. net/smtp has no context
+// support, so ctx is only checked before connecting.
+func (s *SMTP) Send(ctx context.Context, message *Message) error {
+	if err := validate(message); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	return smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, s.From, []string{message.To}, compose(s.From, message))
+}
+
+func NewSMTP(opts ...SMTPOptions) *SMTP {
+	s := &SMTP{Port: "587"}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
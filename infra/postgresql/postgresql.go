@@ -99,7 +99,8 @@ func (p *Postgresql) uri() string {
 
 func (p *Postgresql) Connect() (*gorm.DB, *sql.DB, error) {
 	db, err := gorm.Open(postgres.Open(p.uri()), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 	if err != nil {
 		p.logger.Error("failed to connect database", "error", err.Error())
@@ -0,0 +1,116 @@
+package revocation
+
+import (
+	"sync"
+	"time"
+)
+
+// Store remembers revoked access tokens until they would have expired anyway.
+// A token is revoked either by its own id (jti) or together with every other
+// token of its session.
+type Store interface {
+	RevokeToken(tokenId string, until time.Time)
+	RevokeSession(sessionId uint, until time.Time)
+	IsRevoked(tokenId string, sessionId uint) bool
+	// ConsumeToken revokes a single-use token, reporting false when it was
+	// already revoked.
+	ConsumeToken(tokenId string, until time.Time) bool
+}
+
+// Memory keeps revocations in process memory, so they only cover a single
+// server instance and are lost on restart.
+type Memory struct {
+	mu        sync.RWMutex
+	tokens    map[string]time.Time
+	sessions  map[uint]time.Time
+	lastSweep time.Time
+}
+
+func (m *Memory) RevokeToken(tokenId string, until time.Time) {
+	if tokenId == "" {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(time.Now())
+	m.tokens[tokenId] = later(m.tokens[tokenId], until)
+}
+
+func (m *Memory) RevokeSession(sessionId uint, until time.Time) {
+	if sessionId == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(time.Now())
+	m.sessions[sessionId] = later(m.sessions[sessionId], until)
+}
+
+func (m *Memory) ConsumeToken(tokenId string, until time.Time) bool {
+	if tokenId == "" {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+	if revokedUntil, ok := m.tokens[tokenId]; ok && now.Before(revokedUntil) {
+		return false
+	}
+	m.tokens[tokenId] = until
+	return true
+}
+
+func (m *Memory) IsRevoked(tokenId string, sessionId uint) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	if until, ok := m.tokens[tokenId]; ok && tokenId != "" && now.Before(until) {
+		return true
+	}
+	if until, ok := m.sessions[sessionId]; ok && sessionId != 0 && now.Before(until) {
+		return true
+	}
+	return false
+}
+
+// sweep drops revocations of tokens that have expired by now, expired tokens
+// are rejected regardless.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for tokenId, until := range m.tokens {
+		if !now.Before(until) {
+			delete(m.tokens, tokenId)
+		}
+	}
+	for sessionId, until := range m.sessions {
+		if !now.Before(until) {
+			delete(m.sessions, sessionId)
+		}
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		tokens:   make(map[string]time.Time),
+		sessions: make(map[uint]time.Time),
+	}
+}
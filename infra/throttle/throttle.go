@@ -0,0 +1,114 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Store counts failed attempts per key and blocks keys until a given time.
+// Failures older than the window passed to Fail are forgotten, so a key
+// starts over after a quiet period.
+type Store interface {
+	Fail(key string, window time.Duration) int
+	Block(key string, until time.Time)
+	BlockedUntil(key string) time.Time
+	Reset(key string)
+}
+
+type entry struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+	expiresAt    time.Time
+}
+
+// Memory keeps attempts in process memory, so they only cover a single server
+// instance and are lost on restart.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+func (m *Memory) Fail(key string, window time.Duration) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	e, ok := m.entries[key]
+	if !ok {
+		e = &entry{}
+		m.entries[key] = e
+	}
+
+	if now.Sub(e.lastFailure) > window {
+		e.failures = 0
+	}
+	e.failures++
+	e.lastFailure = now
+	e.expiresAt = later(e.expiresAt, now.Add(window))
+	return e.failures
+}
+
+func (m *Memory) Block(key string, until time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(time.Now())
+
+	e, ok := m.entries[key]
+	if !ok {
+		e = &entry{}
+		m.entries[key] = e
+	}
+	e.blockedUntil = later(e.blockedUntil, until)
+	e.expiresAt = later(e.expiresAt, until)
+}
+
+func (m *Memory) BlockedUntil(key string) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		return e.blockedUntil
+	}
+	return time.Time{}
+}
+
+func (m *Memory) Reset(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+}
+
+// sweep drops entries whose failures and block have both run out, they
+// behave exactly like a missing entry.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+
+	for key, e := range m.entries {
+		if !now.Before(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries:   make(map[string]*entry),
+		lastSweep: time.Now(),
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditAccountLocked   AuditAction = "account_locked"
+	AuditIPLocked        AuditAction = "ip_locked"
+	AuditUserSuspended   AuditAction = "user_suspended"
+	AuditUserUnsuspended AuditAction = "user_unsuspended"
+	AuditUserLoggedOut   AuditAction = "user_logged_out"
+	AuditRoleChanged     AuditAction = "role_changed"
+)
+
+// AuditLog records security relevant events. UserId is nil when the event
+// isn't tied to an existing account, e.g. a lockout for an unknown email.
+// Moderation actions are logged on the user they were taken against, Detail
+// names the moderator.
+type AuditLog struct {
+	Id        uint        `gorm:"primaryKey"`
+	UserId    *uint       `gorm:"index:idx_audit_logs_user_id"`
+	Action    AuditAction `gorm:"not null;index:idx_audit_logs_action"`
+	IP        string      `gorm:"not null;default:''"`
+	Detail    string      `gorm:"not null;default:''"`
+	CreatedAt time.Time
+
+	User *User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:SET NULL"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Block stops BlockedId from starting conversations with, messaging or seeing
+// the presence of BlockerId, and the other way around.
+type Block struct {
+	Id        uint `gorm:"primaryKey"`
+	BlockerId uint `gorm:"not null;uniqueIndex:idx_blocks_blocker_blocked"`
+	BlockedId uint `gorm:"not null;uniqueIndex:idx_blocks_blocker_blocked;index:idx_blocks_blocked_id"`
+	CreatedAt time.Time
+
+	Blocker User `gorm:"foreignKey:BlockerId;references:Id;constraint:OnDelete:CASCADE"`
+	Blocked User `gorm:"foreignKey:BlockedId;references:Id;constraint:OnDelete:CASCADE"`
+}
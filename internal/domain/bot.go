@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Bot holds the credentials of a bot account. The bot itself is a User of type
+// UserTypeBot, so it takes part in privates and messages like anyone else.
+type Bot struct {
+	Id            uint   `gorm:"primaryKey"`
+	UserId        uint   `gorm:"not null;uniqueIndex:idx_bots_user_id"`
+	OwnerId       uint   `gorm:"not null;index:idx_bots_owner_id"`
+	Username      string `gorm:"not null;uniqueIndex:idx_bots_username"`
+	TokenHash     string `gorm:"not null"`
+	WebhookURL    *string
+	WebhookSecret *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Version       int `gorm:"not null;default:1"`
+
+	User  User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+	Owner User `gorm:"foreignKey:OwnerId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+// BotUpdate is a pending Bot API update. Its Id doubles as the update_id handed
+// to the bot, and rows are removed once the bot confirms them.
+type BotUpdate struct {
+	Id        uint   `gorm:"primaryKey"`
+	BotId     uint   `gorm:"not null;index:idx_bot_updates_bot_id"`
+	Payload   string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+
+	Bot Bot `gorm:"foreignKey:BotId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+// BotCallbackQuery records a tap on a callback button, so that the bot's answer
+// can be routed back to the user who tapped it.
+type BotCallbackQuery struct {
+	Id        uint   `gorm:"primaryKey"`
+	BotId     uint   `gorm:"not null;index:idx_bot_callback_queries_bot_id"`
+	UserId    uint   `gorm:"not null"`
+	MessageId uint   `gorm:"not null"`
+	Data      string `gorm:"not null"`
+	Answered  bool   `gorm:"not null;default:false"`
+	CreatedAt time.Time
+
+	Bot     Bot     `gorm:"foreignKey:BotId;references:Id;constraint:OnDelete:CASCADE"`
+	User    User    `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+	Message Message `gorm:"foreignKey:MessageId;references:Id;constraint:OnDelete:CASCADE"`
+}
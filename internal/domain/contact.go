@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Contact is an entry in OwnerId's address book. Alias is the name the owner
+// sees for the contact instead of their own name, empty when not set.
+type Contact struct {
+	Id        uint   `gorm:"primaryKey"`
+	OwnerId   uint   `gorm:"not null;uniqueIndex:idx_contacts_owner_contact"`
+	ContactId uint   `gorm:"not null;uniqueIndex:idx_contacts_owner_contact;index:idx_contacts_contact_id"`
+	Alias     string `gorm:"not null;default:''"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int `gorm:"not null;default:1"`
+
+	Owner   User `gorm:"foreignKey:OwnerId;references:Id;constraint:OnDelete:CASCADE"`
+	Contact User `gorm:"foreignKey:ContactId;references:Id;constraint:OnDelete:CASCADE"`
+}
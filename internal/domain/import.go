@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type ImportSource string
+
+const (
+	ImportSourceTelegram ImportSource = "telegram"
+)
+
+type ImportStatus string
+
+const (
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+type ImportJob struct {
+	Id               uint         `gorm:"primaryKey"`
+	Source           ImportSource `gorm:"not null"`
+	ExportPath       string       `gorm:"not null"`
+	RequestedById    *uint        `gorm:"index:idx_import_jobs_requested_by_id"`
+	Status           ImportStatus `gorm:"not null"`
+	TotalMessages    int          `gorm:"not null;default:0"`
+	ImportedMessages int          `gorm:"not null;default:0"`
+	SkippedMessages  int          `gorm:"not null;default:0"`
+	Error            *string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Version          int `gorm:"not null;default:1"`
+
+	RequestedBy *User `gorm:"foreignKey:RequestedById;references:Id;constraint:OnDelete:SET NULL"`
+}
+
+// ImportedMessage remembers which message of an external export produced which
+// local message, so that an interrupted import can be re-run without duplicates.
+type ImportedMessage struct {
+	Id                uint         `gorm:"primaryKey"`
+	Source            ImportSource `gorm:"not null;uniqueIndex:idx_imported_messages_external"`
+	ExternalChatId    string       `gorm:"not null;uniqueIndex:idx_imported_messages_external"`
+	ExternalMessageId int64        `gorm:"not null;uniqueIndex:idx_imported_messages_external"`
+	Part              int          `gorm:"not null;default:0;uniqueIndex:idx_imported_messages_external"`
+	MessageId         uint         `gorm:"not null;index:idx_imported_messages_message_id"`
+	CreatedAt         time.Time
+
+	Message Message `gorm:"foreignKey:MessageId;references:Id;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// IncomingWebhook lets an external system post into a private conversation.
+// Messages are sent on behalf of CreatorId but shown under Name.
+type IncomingWebhook struct {
+	Id        uint   `gorm:"primaryKey"`
+	PrivateId uint   `gorm:"not null;index:idx_incoming_webhooks_private_id"`
+	CreatorId uint   `gorm:"not null"`
+	Name      string `gorm:"not null"`
+	TokenHash string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Version   int `gorm:"not null;default:1"`
+
+	Private Private `gorm:"foreignKey:PrivateId;references:Id;constraint:OnDelete:CASCADE"`
+	Creator User    `gorm:"foreignKey:CreatorId;references:Id;constraint:OnDelete:CASCADE"`
+}
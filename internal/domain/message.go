@@ -10,17 +10,54 @@ const (
 	MessageTypeFile  MessageType = "file"
 )
 
+// MessageFormat tells clients how to render a text message. Markdown is limited
+// to bold, italic, inline code, code blocks and links.
+type MessageFormat string
+
+const (
+	MessageFormatPlain    MessageFormat = "plain"
+	MessageFormatMarkdown MessageFormat = "markdown"
+)
+
 type Message struct {
-	Id          uint        `gorm:"primaryKey"`
-	FromId      uint        `gorm:"not null;index:idx_messages_from_id"`
-	PrivateId   *uint       `gorm:"index:idx_messages_private_id"`
-	MessageType MessageType `gorm:"not null"`
-	Content     string      `gorm:"not null"`
-	Delivered   bool        `gorm:"not null;default:false"`
-	Read        bool        `gorm:"not null;default:false"`
-	CreatedAt   time.Time
-	Version     int `gorm:"not null;default:1"`
+	Id          uint                  `gorm:"primaryKey"`
+	FromId      uint                  `gorm:"not null;index:idx_messages_from_id"`
+	PrivateId   *uint                 `gorm:"index:idx_messages_private_id"`
+	MessageType MessageType           `gorm:"not null"`
+	Content     string                `gorm:"not null"`
+	Format      MessageFormat         `gorm:"not null;default:plain"`
+	Delivered   bool                  `gorm:"not null;default:false"`
+	Read        bool                  `gorm:"not null;default:false"`
+	ReplyMarkup *InlineKeyboardMarkup `gorm:"type:jsonb;serializer:json"`
+	// IntegrationName is set on messages posted through an incoming webhook,
+	// which clients show instead of the sender's name.
+	IntegrationName *string
+	CreatedAt       time.Time
+	Version         int `gorm:"not null;default:1"`
 
 	From    User     `gorm:"foreignKey:FromId;references:Id;constraint:OnDelete:CASCADE"`
 	Private *Private `gorm:"foreignKey:PrivateId;references:Id;constraint:OnDelete:CASCADE"`
 }
+
+// InlineKeyboardMarkup is a grid of buttons a bot attaches to its message. A
+// button either opens URL or, when tapped, sends CallbackData back to the bot.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+func (k *InlineKeyboardMarkup) HasCallbackData(data string) bool {
+	for _, row := range k.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != "" && button.CallbackData == data {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// PasswordReset is a token emailed to reset a forgotten password. Only its
+// hash is stored and it can be used once.
+type PasswordReset struct {
+	Id        uint   `gorm:"primaryKey"`
+	UserId    uint   `gorm:"not null;index:idx_password_resets_user_id"`
+	TokenHash string `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
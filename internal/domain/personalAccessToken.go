@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type TokenScope string
+
+const (
+	TokenScopeReadMessages        TokenScope = "messages:read"
+	TokenScopeSendMessages        TokenScope = "messages:send"
+	TokenScopeManageConversations TokenScope = "conversations:manage"
+)
+
+var TokenScopes = []TokenScope{TokenScopeReadMessages, TokenScopeSendMessages, TokenScopeManageConversations}
+
+// PersonalAccessTokenPrefix starts every personal access token, telling them
+// apart from JWTs in the Authorization header.
+const PersonalAccessTokenPrefix = "tgp_"
+
+// PersonalAccessToken lets scripts use the API as its user without logging
+// in, limited to the routes its Scopes allow. Only the token's hash is
+// stored. Revoking it deletes it.
+type PersonalAccessToken struct {
+	Id         uint         `gorm:"primaryKey"`
+	UserId     uint         `gorm:"not null;index:idx_personal_access_tokens_user_id"`
+	Name       string       `gorm:"not null"`
+	TokenHash  string       `gorm:"uniqueIndex;not null"`
+	Scopes     []TokenScope `gorm:"type:jsonb;serializer:json;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+func (p *PersonalAccessToken) IsExpired(now time.Time) bool {
+	return p.ExpiresAt != nil && !now.Before(*p.ExpiresAt)
+}
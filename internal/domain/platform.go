@@ -1,8 +1,30 @@
 package domain
 
+import (
+	"slices"
+)
+
 type Platform string
 
 const (
-	PlatformWeb    Platform = "web"
-	PlatformMobile Platform = "mobile"
+	PlatformWeb       Platform = "web"
+	PlatformMobile    Platform = "mobile"
+	PlatformDesktop   Platform = "desktop"
+	PlatformTablet    Platform = "tablet"
+	PlatformAPIClient Platform = "api"
 )
+
+// Platforms is the registry of platforms users can log in from, clients name
+// theirs in the X-Platform header. Token lifetimes and session limits per
+// platform are set through config.Platforms.
+var Platforms = []Platform{
+	PlatformWeb,
+	PlatformMobile,
+	PlatformDesktop,
+	PlatformTablet,
+	PlatformAPIClient,
+}
+
+func IsValidPlatform(name string) bool {
+	return slices.Contains(Platforms, Platform(name))
+}
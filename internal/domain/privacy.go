@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type PrivacyKey string
+
+const (
+	PrivacyKeyLastSeen     PrivacyKey = "last_seen"
+	PrivacyKeyAvatar       PrivacyKey = "avatar"
+	PrivacyKeyPrivateChats PrivacyKey = "private_chats"
+)
+
+var PrivacyKeys = []PrivacyKey{PrivacyKeyLastSeen, PrivacyKeyAvatar, PrivacyKeyPrivateChats}
+
+type PrivacyLevel string
+
+const (
+	PrivacyLevelEverybody PrivacyLevel = "everybody"
+	PrivacyLevelContacts  PrivacyLevel = "contacts"
+	PrivacyLevelNobody    PrivacyLevel = "nobody"
+)
+
+// PrivacyRule decides who may see or do what Key stands for. Users listed in
+// DenyUserIds or AllowUserIds are always refused or allowed, whatever the
+// level. Without a stored rule everybody is allowed.
+type PrivacyRule struct {
+	Id           uint         `gorm:"primaryKey"`
+	UserId       uint         `gorm:"not null;uniqueIndex:idx_privacy_rules_user_key"`
+	Key          PrivacyKey   `gorm:"not null;uniqueIndex:idx_privacy_rules_user_key"`
+	Level        PrivacyLevel `gorm:"not null;default:everybody"`
+	AllowUserIds []uint       `gorm:"type:jsonb;serializer:json;not null"`
+	DenyUserIds  []uint       `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Version      int `gorm:"not null;default:1"`
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+// DefaultPrivacyRule is the rule used for keys a user never changed.
+func DefaultPrivacyRule(userId uint, key PrivacyKey) *PrivacyRule {
+	return &PrivacyRule{
+		UserId:       userId,
+		Key:          key,
+		Level:        PrivacyLevelEverybody,
+		AllowUserIds: []uint{},
+		DenyUserIds:  []uint{},
+	}
+}
+
+// Allows reports whether viewerId passes the rule; isContact tells whether the
+// rule's owner has viewerId in their contacts. Owners always pass their own
+// rules.
+func (p *PrivacyRule) Allows(viewerId uint, isContact bool) bool {
+	switch {
+	case viewerId == p.UserId:
+		return true
+	case slices.Contains(p.DenyUserIds, viewerId):
+		return false
+	case slices.Contains(p.AllowUserIds, viewerId):
+		return true
+	}
+
+	switch p.Level {
+	case PrivacyLevelContacts:
+		return isContact
+	case PrivacyLevelNobody:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+// ServerStats are the counts behind the admin dashboard.
+type ServerStats struct {
+	Users           int64
+	Bots            int64
+	SuspendedUsers  int64
+	DeletedUsers    int64
+	Sessions        int64
+	Privates        int64
+	Messages        int64
+	MessagesLastDay int64
+}
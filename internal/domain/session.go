@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Session is one logged-in device. Every session has its own refresh token, so
+// a user can stay logged in on several devices at once.
+type Session struct {
+	Id         uint   `gorm:"primaryKey"`
+	UserId     uint   `gorm:"not null;index:idx_sessions_user_id"`
+	Platform   string `gorm:"not null"`
+	DeviceName string `gorm:"not null;default:''"`
+	UserAgent  string `gorm:"not null;default:''"`
+	IP         string `gorm:"not null;default:''"`
+	Version    uint   `gorm:"default:1;not null"`
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	UpdatedAt  time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+// RefreshToken is one token of a session's family. Only the current token has
+// no RotatedAt; the rotated ones are kept so that using one again can be
+// recognised as theft.
+type RefreshToken struct {
+	Id        uint   `gorm:"primaryKey"`
+	SessionId uint   `gorm:"not null;index:idx_refresh_tokens_session_id"`
+	TokenHash string `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+	CreatedAt time.Time
+
+	Session Session `gorm:"foreignKey:SessionId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+func (r *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// TwoFactor holds a user's TOTP secret. It only protects logins once
+// confirmed with a first valid code.
+type TwoFactor struct {
+	Id          uint   `gorm:"primaryKey"`
+	UserId      uint   `gorm:"uniqueIndex;not null"`
+	Secret      string `gorm:"not null"`
+	ConfirmedAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, no code of the
+	// same or an earlier step is accepted again.
+	LastUsedStep int64 `gorm:"not null;default:0"`
+	Version      uint  `gorm:"default:1;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
+
+// RecoveryCode replaces a TOTP code once, for users who lost their
+// authenticator. Only its hash is stored.
+type RecoveryCode struct {
+	Id        uint   `gorm:"primaryKey"`
+	UserId    uint   `gorm:"not null;index:idx_recovery_codes_user_id"`
+	CodeHash  string `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
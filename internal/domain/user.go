@@ -1,27 +1,165 @@
 package domain
 
 import (
+	"slices"
 	"time"
 )
 
+type UserType string
+
+const (
+	UserTypeUser UserType = "user"
+	UserTypeBot  UserType = "bot"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Roles lists the roles from least to most privileged, every role can do
+// what the ones before it can.
+var Roles = []Role{
+	RoleUser,
+	RoleModerator,
+	RoleAdmin,
+}
+
+func IsValidRole(name string) bool {
+	return slices.Contains(Roles, Role(name))
+}
+
+// AtLeast tells whether r is role or a more privileged one.
+func (r Role) AtLeast(role Role) bool {
+	return slices.Index(Roles, r) >= slices.Index(Roles, role)
+}
+
+type AccountStatus string
+
+const (
+	AccountActive    AccountStatus = "active"
+	AccountSuspended AccountStatus = "suspended"
+	AccountDeleted   AccountStatus = "deleted"
+)
+
 type User struct {
-	Id                   uint   `gorm:"primaryKey"`
-	Name                 string `gorm:"not null"`
-	Email                string `gorm:"uniqueIndex;not null"`
-	Password             string `gorm:"not null"`
-	RefreshTokenWeb      *string
-	RefreshTokenWebAt    *time.Time
-	RefreshTokenMobile   *string
-	RefreshTokenMobileAt *time.Time
-	Version              uint `gorm:"default:1;not null"`
-	CreatedAt            time.Time
-	UpdatedAt            time.Time
+	Id                 uint     `gorm:"primaryKey"`
+	Type               UserType `gorm:"not null;default:user"`
+	Role               Role     `gorm:"not null;default:user"`
+	Name               string   `gorm:"not null"`
+	Username           *string  `gorm:"uniqueIndex:idx_users_username_lower,expression:lower(username);index:idx_users_username_trgm,type:gin,expression:lower(username) gin_trgm_ops"`
+	Bio                string   `gorm:"not null;default:''"`
+	AvatarURL          *string
+	LastSeenAt         *time.Time
+	Email              string `gorm:"uniqueIndex;not null"`
+	Password           string `gorm:"not null"`
+	EmailVerifiedAt    *time.Time
+	VerificationSentAt *time.Time
+	DeletionDueAt      *time.Time `gorm:"index:idx_users_deletion_due_at"`
+	AnonymizedAt       *time.Time
+	SuspendedAt        *time.Time
+	SuspendedUntil     *time.Time
+	SuspensionReason   string `gorm:"not null;default:''"`
+	Version            uint   `gorm:"default:1;not null"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (u *User) IsBot() bool {
+	return u.Type == UserTypeBot
 }
 
+// DeletedAccountName replaces the name of anonymized accounts.
+const DeletedAccountName = "Deleted Account"
+
+// IsDeleted tells whether the account was deleted and anonymized. The row is
+// kept so the other side of a conversation still has a sender to show.
+func (u *User) IsDeleted() bool {
+	return u.AnonymizedAt != nil
+}
+
+// IsSuspended tells whether a moderator has locked the user out. A suspension
+// without an end is a ban.
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
+}
+
+func (u *User) Status(now time.Time) AccountStatus {
+	switch {
+	case u.IsDeleted():
+		return AccountDeleted
+	case u.IsSuspended(now):
+		return AccountSuspended
+	default:
+		return AccountActive
+	}
+}
+
+// IsEmailVerified is always true for bots, they have no real email address.
+func (u *User) IsEmailVerified() bool {
+	return u.IsBot() || u.EmailVerifiedAt != nil
+}
+
+// Capability is something accounts with an unverified email can be allowed
+// to do through config.Verification.
+type Capability string
+
+const (
+	CapabilityLogin     Capability = "login"
+	CapabilityMessaging Capability = "messaging"
+	CapabilityBots      Capability = "bots"
+)
+
 func (u *User) ToMap() map[string]any {
 	return map[string]any{
-		"id":    u.Id,
-		"name":  u.Name,
-		"email": u.Email,
+		"id":           u.Id,
+		"name":         u.Name,
+		"username":     u.Username,
+		"email":        u.Email,
+		"bio":          u.Bio,
+		"avatar_url":   u.AvatarURL,
+		"last_seen_at": u.LastSeenAt,
+	}
+}
+
+// UsernameChange records every set, change or removal of a username.
+type UsernameChange struct {
+	Id          uint `gorm:"primaryKey"`
+	UserId      uint `gorm:"not null;index:idx_username_changes_user_id"`
+	OldUsername *string
+	NewUsername *string
+	CreatedAt   time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+type LastSeenStatus string
+
+const (
+	LastSeenRecently    LastSeenStatus = "recently"
+	LastSeenWithinWeek  LastSeenStatus = "within_week"
+	LastSeenWithinMonth LastSeenStatus = "within_month"
+	LastSeenLongAgo     LastSeenStatus = "long_ago"
+)
+
+// CoarseLastSeen is what users who may not see lastSeenAt get to know
+// instead.
+func CoarseLastSeen(lastSeenAt *time.Time, now time.Time) LastSeenStatus {
+	if lastSeenAt == nil {
+		return LastSeenLongAgo
+	}
+
+	switch since := now.Sub(*lastSeenAt); {
+	case since <= 3*24*time.Hour:
+		return LastSeenRecently
+	case since <= 7*24*time.Hour:
+		return LastSeenWithinWeek
+	case since <= 30*24*time.Hour:
+		return LastSeenWithinMonth
+	default:
+		return LastSeenLongAgo
 	}
 }
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// UserIdentity links a user to their account at a single sign-on provider.
+// Subject is the provider's id for the account, which unlike the email never
+// changes.
+type UserIdentity struct {
+	Id        uint   `gorm:"primaryKey"`
+	UserId    uint   `gorm:"not null;index:idx_user_identities_user_id"`
+	Provider  string `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string `gorm:"not null"`
+	CreatedAt time.Time
+
+	User User `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type WebhookEvent string
+
+const (
+	WebhookEventMessageCreated   WebhookEvent = "message.created"
+	WebhookEventMessageDelivered WebhookEvent = "message.delivered"
+	WebhookEventMessageRead      WebhookEvent = "message.read"
+	WebhookEventMessageEdited    WebhookEvent = "message.edited"
+)
+
+// Webhook is an endpoint a user registered to receive events of their
+// conversations. When PrivateId is set only that conversation is reported.
+// Deliveries are signed with Secret; the webhook is switched off once
+// FailureCount reaches the configured limit.
+type Webhook struct {
+	Id           uint           `gorm:"primaryKey"`
+	UserId       uint           `gorm:"not null;index:idx_webhooks_user_id"`
+	PrivateId    *uint          `gorm:"index:idx_webhooks_private_id"`
+	URL          string         `gorm:"not null"`
+	Secret       string         `gorm:"not null"`
+	Events       []WebhookEvent `gorm:"type:jsonb;serializer:json;not null"`
+	Active       bool           `gorm:"not null;default:true"`
+	FailureCount int            `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Version      int `gorm:"not null;default:1"`
+
+	User    User     `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE"`
+	Private *Private `gorm:"foreignKey:PrivateId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+// WebhookDelivery logs a single delivery attempt. StatusCode is nil when the
+// endpoint could not be reached at all.
+type WebhookDelivery struct {
+	Id         uint         `gorm:"primaryKey"`
+	WebhookId  uint         `gorm:"not null;index:idx_webhook_deliveries_webhook_id"`
+	EventId    string       `gorm:"not null"`
+	Event      WebhookEvent `gorm:"not null"`
+	Attempt    int          `gorm:"not null"`
+	StatusCode *int
+	Error      *string
+	Success    bool  `gorm:"not null;default:false"`
+	DurationMs int64 `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+
+	Webhook Webhook `gorm:"foreignKey:WebhookId;references:Id;constraint:OnDelete:CASCADE"`
+}
+
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// AdminUserResponse is what moderators see of an account, including what's
+// hidden from other users.
+type AdminUserResponse struct {
+	Id               uint       `json:"id"`
+	Type             string     `json:"type"`
+	Role             string     `json:"role"`
+	Status           string     `json:"status"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	Username         string     `json:"username,omitempty"`
+	EmailVerified    bool       `json:"email_verified"`
+	Online           bool       `json:"online"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	DeletionDueAt    *time.Time `json:"deletion_due_at,omitempty"`
+	LastSeenAt       *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason"`
+	// Until is optional, a suspension without an end is a ban.
+	Until *time.Time `json:"until"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type ForceLogoutResponse struct {
+	Sessions int `json:"sessions"`
+}
+
+type ServerStatsResponse struct {
+	Users           int64 `json:"users"`
+	Bots            int64 `json:"bots"`
+	SuspendedUsers  int64 `json:"suspended_users"`
+	DeletedUsers    int64 `json:"deleted_users"`
+	Sessions        int64 `json:"sessions"`
+	Privates        int64 `json:"privates"`
+	Messages        int64 `json:"messages"`
+	MessagesLastDay int64 `json:"messages_last_day"`
+	OnlineUsers     int   `json:"online_users"`
+	Connections     int   `json:"connections"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+type BlockRequest struct {
+	UserId uint `json:"user_id"`
+}
+
+type BlockResponse struct {
+	User      PublicUserResponse `json:"user"`
+	CreatedAt time.Time          `json:"created_at"`
+}
@@ -0,0 +1,107 @@
+package dto
+
+import "time"
+
+type BotRequest struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+type BotResponse struct {
+	Id         uint      `json:"id"`
+	UserId     uint      `json:"user_id"`
+	Name       string    `json:"name"`
+	Username   string    `json:"username"`
+	Token      string    `json:"token,omitempty"`
+	WebhookURL string    `json:"webhook_url,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// The Bot API types below mirror the JSON shape of the Telegram Bot API so that
+// existing bot libraries can talk to TeleGopher with little or no change.
+
+type BotApiUser struct {
+	Id        uint   `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username,omitempty"`
+}
+
+type BotApiChat struct {
+	Id        uint   `json:"id"`
+	Type      string `json:"type"`
+	FirstName string `json:"first_name,omitempty"`
+}
+
+type BotApiDocument struct {
+	FileName string `json:"file_name"`
+	FileUrl  string `json:"file_url"`
+}
+
+type BotApiMessage struct {
+	MessageId   uint                  `json:"message_id"`
+	From        *BotApiUser           `json:"from,omitempty"`
+	Chat        BotApiChat            `json:"chat"`
+	Date        int64                 `json:"date"`
+	Text        string                `json:"text,omitempty"`
+	Document    *BotApiDocument       `json:"document,omitempty"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type BotApiCallbackQuery struct {
+	Id           string         `json:"id"`
+	From         BotApiUser     `json:"from"`
+	Message      *BotApiMessage `json:"message,omitempty"`
+	ChatInstance string         `json:"chat_instance"`
+	Data         string         `json:"data"`
+}
+
+type BotApiUpdate struct {
+	UpdateId      uint                 `json:"update_id"`
+	Message       *BotApiMessage       `json:"message,omitempty"`
+	CallbackQuery *BotApiCallbackQuery `json:"callback_query,omitempty"`
+}
+
+type BotSendMessageRequest struct {
+	ChatId      uint
+	Text        string
+	ReplyMarkup *InlineKeyboardMarkup
+}
+
+type BotAnswerCallbackQueryRequest struct {
+	CallbackQueryId uint
+	Text            string
+	ShowAlert       bool
+}
+
+type BotEditMessageReplyMarkupRequest struct {
+	ChatId      uint
+	MessageId   uint
+	ReplyMarkup *InlineKeyboardMarkup
+}
+
+type CallbackQueryRequest struct {
+	MessageId uint
+	Data      string
+}
+
+// CallbackAnswerResponse is the toast sent over the WebSocket to the user who
+// tapped the button.
+type CallbackAnswerResponse struct {
+	CallbackQueryId string `json:"callback_query_id"`
+	UserId          uint   `json:"-"`
+	MessageId       uint   `json:"message_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert"`
+}
+
+type BotGetUpdatesRequest struct {
+	Offset  uint
+	Limit   int
+	Timeout time.Duration
+}
+
+type BotSetWebhookRequest struct {
+	Url         string
+	SecretToken string
+}
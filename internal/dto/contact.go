@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type ContactRequest struct {
+	UserId uint   `json:"user_id"`
+	Alias  string `json:"alias"`
+}
+
+type UpdateContactRequest struct {
+	Alias string `json:"alias"`
+}
+
+type ImportContactsRequest struct {
+	Emails []string `json:"emails"`
+}
+
+type ContactResponse struct {
+	User      PublicUserResponse `json:"user"`
+	Alias     string             `json:"alias,omitempty"`
+	Online    bool               `json:"online"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// ImportContactsResponse lists the contacts found for the imported emails and
+// the emails that don't belong to any registered user.
+type ImportContactsResponse struct {
+	Contacts     []ContactResponse `json:"contacts"`
+	Unregistered []string          `json:"unregistered"`
+}
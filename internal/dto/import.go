@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+type TelegramImportRequest struct {
+	ExportPath  string            `json:"export_path"`
+	UserMapping map[string]string `json:"user_mapping"`
+	// Timezone is the IANA name of the zone the export was made in, UTC when
+	// empty. Only messages without a Unix timestamp need it.
+	Timezone string `json:"timezone"`
+}
+
+type ImportJobResponse struct {
+	Id               uint      `json:"id"`
+	Source           string    `json:"source"`
+	ExportPath       string    `json:"export_path"`
+	Status           string    `json:"status"`
+	TotalMessages    int       `json:"total_messages"`
+	ImportedMessages int       `json:"imported_messages"`
+	SkippedMessages  int       `json:"skipped_messages"`
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TelegramExport is the subset of Telegram Desktop's result.json we understand.
+// A full account export nests chats under "chats.list", a single chat export is
+// the chat object itself.
+type TelegramExport struct {
+	Chats *struct {
+		List []TelegramChat `json:"list"`
+	} `json:"chats"`
+	TelegramChat
+}
+
+type TelegramChat struct {
+	Id       int64             `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Messages []TelegramMessage `json:"messages"`
+}
+
+type TelegramMessage struct {
+	Id           int64           `json:"id"`
+	Type         string          `json:"type"`
+	Date         string          `json:"date"`
+	DateUnixtime string          `json:"date_unixtime"`
+	FromId       string          `json:"from_id"`
+	Text         json.RawMessage `json:"text"`
+	Photo        string          `json:"photo"`
+	File         string          `json:"file"`
+}
+
+func (t *TelegramExport) ChatList() []TelegramChat {
+	if t.Chats != nil {
+		return t.Chats.List
+	}
+	if t.Messages != nil {
+		return []TelegramChat{t.TelegramChat}
+	}
+	return nil
+}
+
+// PlainText flattens the text field, which is either a plain string or an array
+// mixing strings and formatted entities such as {"type":"bold","text":"hi"}.
+func (t *TelegramMessage) PlainText() string {
+	var text string
+	if err := json.Unmarshal(t.Text, &text); err == nil {
+		return text
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(t.Text, &parts); err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, part := range parts {
+		var s string
+		if err := json.Unmarshal(part, &s); err == nil {
+			sb.WriteString(s)
+			continue
+		}
+
+		var entity struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(part, &entity); err == nil {
+			sb.WriteString(entity.Text)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type IncomingWebhookRequest struct {
+	Name string `json:"name"`
+}
+
+type IncomingWebhookResponse struct {
+	Id        uint      `json:"id"`
+	PrivateId uint      `json:"private_id"`
+	CreatorId uint      `json:"creator_id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IncomingWebhookMessageRequest struct {
+	Text   string `json:"text"`
+	Format string `json:"format"`
+}
@@ -5,20 +5,37 @@ import (
 )
 
 type MessageRequest struct {
-	PrivateId   uint   `json:"private_id"`
-	MessageType string `json:"message_type"`
-	Content     string `json:"content"`
+	PrivateId   uint                  `json:"private_id"`
+	MessageType string                `json:"message_type"`
+	Content     string                `json:"content"`
+	Format      string                `json:"format,omitempty"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	// Integration is only set by incoming webhooks, never read from clients.
+	Integration string `json:"-"`
 }
 
 type MessageResponse struct {
-	Id          uint      `json:"id"`
-	FromId      uint      `json:"from_id"`
-	PrivateId   uint      `json:"private_id"`
-	MessageType string    `json:"message_type"`
-	Content     string    `json:"content"`
-	Delivered   bool      `json:"delivered"`
-	Read        bool      `json:"read"`
-	CreatedAt   time.Time `json:"created_at"`
+	Id              uint                  `json:"id"`
+	FromId          uint                  `json:"from_id"`
+	PrivateId       uint                  `json:"private_id"`
+	MessageType     string                `json:"message_type"`
+	Content         string                `json:"content"`
+	Format          string                `json:"format"`
+	IntegrationName string                `json:"integration_name,omitempty"`
+	Delivered       bool                  `json:"delivered"`
+	Read            bool                  `json:"read"`
+	ReplyMarkup     *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
 }
 
 type MessageListResponse struct {
@@ -0,0 +1,18 @@
+package dto
+
+type OIDCStartRequest struct {
+	DeviceName string `json:"device_name"`
+}
+
+// OIDCStartResponse sends the user to the provider. The provider redirects
+// back with code and state, both go to the callback endpoint.
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
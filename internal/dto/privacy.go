@@ -0,0 +1,14 @@
+package dto
+
+type PrivacyRuleRequest struct {
+	Level        string `json:"level"`
+	AllowUserIds []uint `json:"allow_user_ids"`
+	DenyUserIds  []uint `json:"deny_user_ids"`
+}
+
+type PrivacyRuleResponse struct {
+	Key          string `json:"key"`
+	Level        string `json:"level"`
+	AllowUserIds []uint `json:"allow_user_ids"`
+	DenyUserIds  []uint `json:"deny_user_ids"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// QRLoginTokenResponse is sent to the device waiting to be logged in, which
+// shows Token as a QR code for a logged-in device to scan.
+type QRLoginTokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+type QRLoginApproveRequest struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type SessionResponse struct {
+	Id         uint      `json:"id"`
+	Platform   string    `json:"platform"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
@@ -18,8 +18,17 @@ type RegisterResponse struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"`
+}
+
+// DeviceInfo describes the device a session is used from, as seen by the
+// server.
+type DeviceInfo struct {
+	Platform  string
+	UserAgent string
+	IP        string
 }
 
 type LoginResponse struct {
@@ -38,8 +47,69 @@ type RefreshTokenResponse struct {
 }
 
 type UserResponse struct {
-	Id        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	Id        uint   `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username,omitempty"`
+	Bio       string `json:"bio"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	// LastSeenAt is only set for viewers allowed to see it, the others get
+	// the coarse LastSeen instead. Users in a block with the viewer get
+	// neither.
+	LastSeenAt    *time.Time `json:"last_seen_at,omitempty"`
+	LastSeen      string     `json:"last_seen,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// UpdateProfileRequest only changes the fields that are present.
+type UpdateProfileRequest struct {
+	Name *string `json:"name"`
+	Bio  *string `json:"bio"`
+}
+
+type UsernameRequest struct {
+	Username string `json:"username"`
+}
+
+type UsernameChangeResponse struct {
+	OldUsername string    `json:"old_username,omitempty"`
+	NewUsername string    `json:"new_username,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PublicUserResponse is what other users get to see, it never includes the
+// email address.
+type PublicUserResponse struct {
+	Id        uint   `json:"id"`
+	Name      string `json:"name"`
+	Username  string `json:"username,omitempty"`
+	Bio       string `json:"bio"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type AccountDeletionResponse struct {
+	DeletionDueAt time.Time `json:"deletion_due_at"`
 }
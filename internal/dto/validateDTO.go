@@ -3,6 +3,9 @@ package dto
 import (
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"slices"
+	"strings"
+	"time"
 )
 
 func validateName(v *helper.Validator, name string) {
@@ -28,9 +31,129 @@ func ValidateRegisterRequest(v *helper.Validator, req *RegisterRequest) {
 	validatePassword(v, req.Password)
 }
 
+func ValidateUpdateProfileRequest(v *helper.Validator, req *UpdateProfileRequest) {
+	v.Check(req.Name != nil || req.Bio != nil, "profile", "at least one of name or bio must be provided")
+	if req.Name != nil {
+		validateName(v, *req.Name)
+	}
+	if req.Bio != nil {
+		v.Check(helper.MaxChars(*req.Bio, 200), "bio", "Bio must be less than 200 characters")
+	}
+}
+
+// ValidateUsernameRequest accepts an empty username, which removes it. Names
+// ending in "bot" are reserved for bot accounts.
+func ValidateUsernameRequest(v *helper.Validator, req *UsernameRequest) {
+	if req.Username == "" {
+		return
+	}
+	v.Check(helper.Matches(req.Username, helper.UsernameRX), "username", "username must be 5-32 letters, digits or underscores, start with a letter and not end with an underscore")
+	v.Check(!strings.HasSuffix(strings.ToLower(req.Username), "bot"), "username", "usernames ending in 'bot' are reserved for bots")
+}
+
+func ValidateUserSearchQuery(v *helper.Validator, query string) {
+	v.Check(helper.NotBlank(query), "q", "q must be provided")
+	v.Check(helper.Matches(query, helper.UsernameQueryRX), "q", "q must be up to 32 letters, digits or underscores")
+}
+
+func ValidateDeleteAccountRequest(v *helper.Validator, req *DeleteAccountRequest) {
+	v.Check(helper.NotBlank(req.Password), "password", "password must be provided")
+}
+
 func ValidateLoginRequest(v *helper.Validator, req *LoginRequest) {
 	validateEmail(v, req.Email)
 	validatePassword(v, req.Password)
+	v.Check(len(req.DeviceName) <= 64, "device_name", "device_name must be at most 64 bytes")
+}
+
+func ValidateForgotPasswordRequest(v *helper.Validator, req *ForgotPasswordRequest) {
+	validateEmail(v, req.Email)
+}
+
+func ValidateResetPasswordRequest(v *helper.Validator, req *ResetPasswordRequest) {
+	v.Check(helper.NotBlank(req.Token), "token", "token must be provided")
+	validatePassword(v, req.Password)
+}
+
+func ValidateChangePasswordRequest(v *helper.Validator, req *ChangePasswordRequest) {
+	v.Check(helper.NotBlank(req.CurrentPassword), "current_password", "current_password must be provided")
+	validatePassword(v, req.NewPassword)
+	v.Check(req.NewPassword != req.CurrentPassword, "new_password", "new_password must differ from the current password")
+}
+
+func ValidateTwoFactorCodeRequest(v *helper.Validator, req *TwoFactorCodeRequest) {
+	validateTwoFactorCode(v, req.Code)
+}
+
+func ValidateDisableTwoFactorRequest(v *helper.Validator, req *DisableTwoFactorRequest) {
+	v.Check(helper.NotBlank(req.Password), "password", "password must be provided")
+	validateTwoFactorCode(v, req.Code)
+}
+
+func ValidateLoginTwoFactorRequest(v *helper.Validator, req *LoginTwoFactorRequest) {
+	v.Check(helper.NotBlank(req.ChallengeToken), "challenge_token", "challenge_token must be provided")
+	validateTwoFactorCode(v, req.Code)
+}
+
+func ValidateOIDCStartRequest(v *helper.Validator, req *OIDCStartRequest) {
+	v.Check(len(req.DeviceName) <= 64, "device_name", "device_name must be at most 64 bytes")
+}
+
+func ValidateOIDCCallbackRequest(v *helper.Validator, req *OIDCCallbackRequest) {
+	v.Check(helper.NotBlank(req.Code), "code", "code must be provided")
+	v.Check(helper.NotBlank(req.State), "state", "state must be provided")
+}
+
+func ValidatePersonalAccessTokenRequest(v *helper.Validator, req *PersonalAccessTokenRequest) {
+	v.Check(helper.NotBlank(req.Name), "name", "name must be provided")
+	v.Check(helper.MaxChars(req.Name, 64), "name", "name must be at most 64 characters")
+	v.Check(len(req.Scopes) > 0, "scopes", "at least one scope must be provided")
+	v.Check(helper.Unique(req.Scopes), "scopes", "scopes must not contain duplicates")
+	for _, scope := range req.Scopes {
+		v.Check(helper.PermittedValue(domain.TokenScope(scope), domain.TokenScopes...), "scopes", "scopes must be messages:read, messages:send or conversations:manage")
+	}
+	if req.ExpiresAt != nil {
+		v.Check(req.ExpiresAt.After(time.Now()), "expires_at", "expires_at must be in the future")
+	}
+}
+
+func ValidateAdminUserQuery(v *helper.Validator, query, role, status string) {
+	v.Check(helper.MaxChars(query, 64), "q", "q must be at most 64 characters")
+	if role != "" {
+		v.Check(domain.IsValidRole(role), "role", "role must be user, moderator or admin")
+	}
+	if status != "" {
+		v.Check(helper.PermittedValue(domain.AccountStatus(status), domain.AccountActive, domain.AccountSuspended, domain.AccountDeleted), "status", "status must be active, suspended or deleted")
+	}
+}
+
+func ValidateSuspendUserRequest(v *helper.Validator, req *SuspendUserRequest) {
+	v.Check(helper.NotBlank(req.Reason), "reason", "reason must be provided")
+	v.Check(helper.MaxChars(req.Reason, 500), "reason", "reason must be at most 500 characters")
+	if req.Until != nil {
+		v.Check(req.Until.After(time.Now()), "until", "until must be in the future")
+	}
+}
+
+func ValidateSetRoleRequest(v *helper.Validator, req *SetRoleRequest) {
+	v.Check(domain.IsValidRole(req.Role), "role", "role must be user, moderator or admin")
+}
+
+func ValidateQRLoginDeviceName(v *helper.Validator, deviceName string) {
+	v.Check(len(deviceName) <= 64, "device_name", "device_name must be at most 64 bytes")
+}
+
+func ValidateQRLoginApproveRequest(v *helper.Validator, req *QRLoginApproveRequest) {
+	v.Check(helper.NotBlank(req.Token), "token", "token must be provided")
+}
+
+func validateTwoFactorCode(v *helper.Validator, code string) {
+	v.Check(helper.NotBlank(code), "code", "code must be provided")
+	v.Check(helper.MaxChars(code, 16), "code", "code must be at most 16 characters")
+}
+
+func ValidateVerifyEmailRequest(v *helper.Validator, req *VerifyEmailRequest) {
+	v.Check(helper.NotBlank(req.Token), "token", "token must be provided")
 }
 
 func ValidateRefreshToken(v *helper.Validator, req *RefreshTokenRequest) {
@@ -55,4 +178,147 @@ func ValidateMessageRequest(v *helper.Validator, req *MessageRequest) {
 	validatePrivateId(v, req.PrivateId)
 	validateMessageType(v, req.MessageType)
 	validateContent(v, req.Content)
+	validateFormat(v, req.Format)
+	validateReplyMarkup(v, req.ReplyMarkup)
+}
+
+func validateFormat(v *helper.Validator, format string) {
+	v.Check(format == "" || helper.PermittedValue(domain.MessageFormat(format), domain.MessageFormatPlain, domain.MessageFormatMarkdown), "format", "format must be plain or markdown")
+}
+
+func ValidateTelegramImportRequest(v *helper.Validator, req *TelegramImportRequest) {
+	v.Check(helper.NotBlank(req.ExportPath), "export_path", "export_path must be provided")
+	v.Check(len(req.UserMapping) > 0, "user_mapping", "user_mapping must map at least one export user to an email")
+	for fromId, email := range req.UserMapping {
+		v.Check(helper.NotBlank(fromId), "user_mapping", "user_mapping keys must be export user ids")
+		v.Check(helper.Matches(email, helper.EmailRX), "user_mapping", "user_mapping values must be valid emails")
+	}
+	if req.Timezone != "" {
+		_, err := time.LoadLocation(req.Timezone)
+		v.Check(err == nil, "timezone", "timezone must be an IANA time zone name")
+	}
+}
+
+func ValidateBotRequest(v *helper.Validator, req *BotRequest) {
+	validateName(v, req.Name)
+	v.Check(helper.NotBlank(req.Username), "username", "username must be provided")
+	v.Check(helper.Matches(req.Username, helper.BotUsernameRX), "username", "username must be 5-32 letters, digits or underscores and end in 'bot'")
+}
+
+func ValidateBotSendMessageRequest(v *helper.Validator, req *BotSendMessageRequest) {
+	v.Check(req.ChatId > 0, "chat_id", "chat_id must be provided")
+	v.Check(helper.NotBlank(req.Text), "text", "text must be provided")
+	v.Check(helper.MaxChars(req.Text, 4096), "text", "text must be less than 4096 characters")
+	validateReplyMarkup(v, req.ReplyMarkup)
+}
+
+func ValidateBotGetUpdatesRequest(v *helper.Validator, req *BotGetUpdatesRequest) {
+	v.Check(req.Limit >= 1 && req.Limit <= 100, "limit", "limit must be between 1 and 100")
+	v.Check(req.Timeout >= 0 && req.Timeout <= 50*time.Second, "timeout", "timeout must be between 0 and 50 seconds")
+}
+
+func ValidateBotSetWebhookRequest(v *helper.Validator, req *BotSetWebhookRequest) {
+	v.Check(req.Url == "" || helper.IsHTTPURL(req.Url), "url", "url must be a valid http or https URL")
+	v.Check(helper.MaxChars(req.SecretToken, 256), "secret_token", "secret_token must be less than 256 characters")
+}
+
+func validateReplyMarkup(v *helper.Validator, markup *InlineKeyboardMarkup) {
+	if markup == nil {
+		return
+	}
+
+	buttons := 0
+	for _, row := range markup.InlineKeyboard {
+		v.Check(len(row) > 0, "reply_markup", "keyboard rows must not be empty")
+		for _, button := range row {
+			buttons++
+			v.Check(helper.NotBlank(button.Text), "reply_markup", "every button needs a text")
+			v.Check((button.URL == "") != (button.CallbackData == ""), "reply_markup", "a button needs exactly one of url or callback_data")
+			v.Check(button.URL == "" || helper.IsURL(button.URL), "reply_markup", "button url must be a valid URL")
+			v.Check(len(button.CallbackData) <= 64, "reply_markup", "callback_data must be at most 64 bytes")
+		}
+	}
+	v.Check(buttons > 0, "reply_markup", "inline_keyboard must contain at least one button")
+	v.Check(buttons <= 100, "reply_markup", "inline_keyboard must contain at most 100 buttons")
+}
+
+func ValidateBotAnswerCallbackQueryRequest(v *helper.Validator, req *BotAnswerCallbackQueryRequest) {
+	v.Check(req.CallbackQueryId > 0, "callback_query_id", "callback_query_id must be provided")
+	v.Check(helper.MaxChars(req.Text, 200), "text", "text must be less than 200 characters")
+}
+
+func ValidateBotEditMessageReplyMarkupRequest(v *helper.Validator, req *BotEditMessageReplyMarkupRequest) {
+	v.Check(req.ChatId > 0, "chat_id", "chat_id must be provided")
+	v.Check(req.MessageId > 0, "message_id", "message_id must be provided")
+	validateReplyMarkup(v, req.ReplyMarkup)
+}
+
+func ValidateCallbackQueryRequest(v *helper.Validator, req *CallbackQueryRequest) {
+	v.Check(req.MessageId > 0, "message_id", "message_id must be provided")
+	v.Check(helper.NotBlank(req.Data), "data", "data must be provided")
+	v.Check(len(req.Data) <= 64, "data", "data must be at most 64 bytes")
+}
+
+func ValidateWebhookRequest(v *helper.Validator, req *WebhookRequest) {
+	v.Check(helper.NotBlank(req.URL), "url", "url must be provided")
+	v.Check(helper.IsHTTPURL(req.URL), "url", "url must be a valid http or https URL")
+	v.Check(helper.MaxChars(req.URL, 2048), "url", "url must be less than 2048 characters")
+	v.Check(len(req.Events) > 0, "events", "at least one event must be provided")
+	v.Check(helper.Unique(req.Events), "events", "events must not contain duplicates")
+	for _, event := range req.Events {
+		v.Check(helper.PermittedValue(domain.WebhookEvent(event),
+			domain.WebhookEventMessageCreated,
+			domain.WebhookEventMessageDelivered,
+			domain.WebhookEventMessageRead,
+			domain.WebhookEventMessageEdited,
+		), "events", "events contains an unknown event")
+	}
+	v.Check(req.PrivateId == nil || *req.PrivateId > 0, "private_id", "private_id must be a positive number")
+}
+
+func ValidateIncomingWebhookRequest(v *helper.Validator, req *IncomingWebhookRequest) {
+	validateName(v, req.Name)
+}
+
+func ValidateIncomingWebhookMessageRequest(v *helper.Validator, req *IncomingWebhookMessageRequest) {
+	v.Check(helper.NotBlank(req.Text), "text", "text must be provided")
+	v.Check(helper.MaxChars(req.Text, 4096), "text", "text must be less than 4096 characters")
+	validateFormat(v, req.Format)
+}
+
+func validateAlias(v *helper.Validator, alias string) {
+	v.Check(helper.MaxChars(alias, 100), "alias", "alias must be less than 100 characters")
+}
+
+func ValidateContactRequest(v *helper.Validator, req *ContactRequest) {
+	v.Check(req.UserId > 0, "user_id", "user_id must be provided")
+	validateAlias(v, req.Alias)
+}
+
+func ValidateUpdateContactRequest(v *helper.Validator, req *UpdateContactRequest) {
+	validateAlias(v, req.Alias)
+}
+
+func ValidateImportContactsRequest(v *helper.Validator, req *ImportContactsRequest) {
+	v.Check(len(req.Emails) > 0, "emails", "emails must contain at least one email")
+	v.Check(len(req.Emails) <= 500, "emails", "emails must contain at most 500 emails")
+	for _, email := range req.Emails {
+		v.Check(helper.Matches(email, helper.EmailRX), "emails", "emails must only contain valid emails")
+	}
+}
+
+func ValidateBlockRequest(v *helper.Validator, req *BlockRequest) {
+	v.Check(req.UserId > 0, "user_id", "user_id must be provided")
+}
+
+func ValidatePrivacyRuleRequest(v *helper.Validator, key string, req *PrivacyRuleRequest) {
+	v.Check(helper.PermittedValue(domain.PrivacyKey(key), domain.PrivacyKeys...), "key", "key must be last_seen, avatar or private_chats")
+	v.Check(helper.PermittedValue(domain.PrivacyLevel(req.Level), domain.PrivacyLevelEverybody, domain.PrivacyLevelContacts, domain.PrivacyLevelNobody), "level", "level must be everybody, contacts or nobody")
+	v.Check(len(req.AllowUserIds) <= 1000, "allow_user_ids", "allow_user_ids must contain at most 1000 users")
+	v.Check(len(req.DenyUserIds) <= 1000, "deny_user_ids", "deny_user_ids must contain at most 1000 users")
+	v.Check(helper.Unique(req.AllowUserIds), "allow_user_ids", "allow_user_ids must not contain duplicates")
+	v.Check(helper.Unique(req.DenyUserIds), "deny_user_ids", "deny_user_ids must not contain duplicates")
+	for _, id := range req.AllowUserIds {
+		v.Check(!slices.Contains(req.DenyUserIds, id), "allow_user_ids", "a user can't be both allowed and denied")
+	}
 }
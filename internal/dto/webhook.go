@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+type WebhookRequest struct {
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	PrivateId *uint    `json:"private_id,omitempty"`
+}
+
+type WebhookResponse struct {
+	Id           uint      `json:"id"`
+	URL          string    `json:"url"`
+	Events       []string  `json:"events"`
+	PrivateId    *uint     `json:"private_id,omitempty"`
+	Active       bool      `json:"active"`
+	FailureCount int       `json:"failure_count"`
+	Secret       string    `json:"secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id         uint      `json:"id"`
+	EventId    string    `json:"event_id"`
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Success    bool      `json:"success"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries  []WebhookDeliveryResponse `json:"deliveries"`
+	Page        int                       `json:"page"`
+	Limit       int                       `json:"limit"`
+	HasNextPage bool                      `json:"has_next_page"`
+}
+
+// WebhookPayload is the body posted to a webhook. Id is shared by all attempts
+// of the same delivery, so receivers can drop duplicates.
+type WebhookPayload struct {
+	Id        string         `json:"id"`
+	Event     string         `json:"event"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      map[string]any `json:"data"`
+}
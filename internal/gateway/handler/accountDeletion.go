@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type AccountDeletionHandler struct {
+	accountDeletionService service.AccountDeletionService
+}
+
+// ScheduleDeletion godoc
+// @Summary      Delete my account
+// @Description  Schedule the authenticated user's account for deletion. Once the grace period is over the account is anonymized as "Deleted Account": sessions are ended, uploads removed and messages kept or purged depending on the server configuration. Until then it works as usual and the deletion can be cancelled.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.DeleteAccountRequest true "Current password"
+// @Success      200 {object} helper.Response{data=dto.AccountDeletionResponse} "Account deletion scheduled"
+// @Failure      400 {object} helper.Response "Incorrect password"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      409 {object} helper.Response "Account deletion is already scheduled"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/deletion [post]
+func (a *AccountDeletionHandler) ScheduleDeletion(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.DeleteAccountRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateDeleteAccountRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	deletion, err := a.accountDeletionService.ScheduleDeletion(r.Context(), userId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrIncorrectPassword):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrDeletionAlreadyScheduled):
+			helper.EditConflictResponse(w, err.Error(), err)
+		default:
+			helper.InternalServerError(w, "Failed to schedule account deletion", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Account deletion scheduled", deletion)
+}
+
+// CancelDeletion godoc
+// @Summary      Cancel account deletion
+// @Description  Keep the authenticated user's account, cancelling a deletion scheduled during the grace period
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response "Account deletion cancelled"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "No account deletion is scheduled"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/deletion [delete]
+func (a *AccountDeletionHandler) CancelDeletion(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	if err := a.accountDeletionService.CancelDeletion(r.Context(), userId); err != nil {
+		if errors.Is(err, repository.ErrDeletionNotScheduled) {
+			helper.NotFoundResponse(w, err.Error())
+			return
+		}
+		helper.InternalServerError(w, "Failed to cancel account deletion", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Account deletion cancelled", nil)
+}
+
+func NewAccountDeletionHandler(accountDeletionService service.AccountDeletionService) *AccountDeletionHandler {
+	return &AccountDeletionHandler{
+		accountDeletionService: accountDeletionService,
+	}
+}
@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+	"strings"
+)
+
+type AdminHandler struct {
+	adminService service.AdminService
+	hub          *ws.Hub
+}
+
+// GetUsers godoc
+// @Summary      List users
+// @Description  List accounts newest first, bots included, optionally searching by ID, name, username or email and filtering by role or status
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        q query string false "ID, or part of a name, username or email"
+// @Param        role query string false "Role" Enums(user, moderator, admin)
+// @Param        status query string false "Account status" Enums(active, suspended, deleted)
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(20)
+// @Success      200 {object} helper.PaginatedResponse{Response=helper.Response{data=[]dto.AdminUserResponse}} "Users successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The moderator role is required"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/users [get]
+func (a *AdminHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	role := r.URL.Query().Get("role")
+	status := r.URL.Query().Get("status")
+
+	v := helper.NewValidator()
+	dto.ValidateAdminUserQuery(v, query, role, status)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	page, limit := helper.ParsePagination(r)
+
+	users, total, err := a.adminService.GetUsers(r.Context(), strings.TrimPrefix(query, "@"), domain.Role(role), domain.AccountStatus(status), page, limit)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get users", err)
+		return
+	}
+
+	for i := range users {
+		users[i].Online = a.hub.IsOnline(users[i].Id)
+	}
+
+	helper.PaginatedSuccessResponse(w, "Users successfully retrieved", users, helper.PaginatedMeta{
+		Page:      int64(page),
+		Limit:     int64(limit),
+		Total:     total,
+		TotalPage: (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
+// GetUser godoc
+// @Summary      Get a user
+// @Description  Get an account as moderators see it, with its role, status and suspension
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "User ID"
+// @Success      200 {object} helper.Response{data=dto.AdminUserResponse} "User successfully retrieved"
+// @Failure      400 {object} helper.Response "Invalid user ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The moderator role is required"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/users/{id} [get]
+func (a *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid user ID", err)
+		return
+	}
+
+	user, err := a.adminService.GetUser(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "User not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to get user", err)
+		return
+	}
+
+	user.Online = a.hub.IsOnline(user.Id)
+
+	helper.SuccessResponse(w, "User successfully retrieved", user)
+}
+
+// SetRole godoc
+// @Summary      Set a user's role
+// @Description  Make a user a moderator or admin, or take the role away. Admins can't change the role of other admins, only the admins from the server configuration can.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "User ID"
+// @Param        request body dto.SetRoleRequest true "New role"
+// @Success      200 {object} helper.Response "Role successfully changed"
+// @Failure      400 {object} helper.Response "Invalid user ID or request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The admin role is required, or the user's role isn't below yours"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/users/{id}/role [put]
+func (a *AdminHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	adminId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid user ID", err)
+		return
+	}
+
+	var payload dto.SetRoleRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateSetRoleRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if err := a.adminService.SetRole(r.Context(), adminId, id, &payload); err != nil {
+		a.moderationError(w, err, "Failed to change role")
+		return
+	}
+
+	helper.SuccessResponse(w, "Role successfully changed", nil)
+}
+
+// SuspendUser godoc
+// @Summary      Suspend a user
+// @Description  Lock a user out until the given time, or for good when there's none. All of their sessions end, their WebSocket connections are closed with an account_suspended event, and they can't log in or use their personal access tokens while suspended. Suspending again replaces the earlier suspension.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "User ID"
+// @Param        request body dto.SuspendUserRequest true "Reason and optional end"
+// @Success      200 {object} helper.Response "User successfully suspended"
+// @Failure      400 {object} helper.Response "Invalid user ID or request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The moderator role is required, or the user's role isn't below yours"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/users/{id}/suspension [post]
+func (a *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	moderatorId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid user ID", err)
+		return
+	}
+
+	var payload dto.SuspendUserRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateSuspendUserRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if _, err := a.adminService.SuspendUser(r.Context(), moderatorId, id, &payload); err != nil {
+		a.moderationError(w, err, "Failed to suspend user")
+		return
+	}
+
+	a.hub.CloseSuspended(id, payload.Reason)
+
+	helper.SuccessResponse(w, "User successfully suspended", nil)
+}
+
+// UnsuspendUser godoc
+// @Summary      Lift a suspension
+// @Description  Let a suspended user log in again
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "User ID"
+// @Success      200 {object} helper.Response "Suspension successfully lifted"
+// @Failure      400 {object} helper.Response "Invalid user ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The moderator role is required, or the user's role isn't below yours"
+// @Failure      404 {object} helper.Response "User not found or not suspended"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/users/{id}/suspension [delete]
+func (a *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	moderatorId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid user ID", err)
+		return
+	}
+
+	if err := a.adminService.UnsuspendUser(r.Context(), moderatorId, id); err != nil {
+		if errors.Is(err, repository.ErrNotSuspended) {
+			helper.NotFoundResponse(w, err.Error())
+			return
+		}
+		a.moderationError(w, err, "Failed to lift suspension")
+		return
+	}
+
+	helper.SuccessResponse(w, "Suspension successfully lifted", nil)
+}
+
+// ForceLogout godoc
+// @Summary      Log a user out everywhere
+// @Description  End all of a user's sessions, delete their personal access tokens and close their WebSocket connections. The user can log in again right away.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "User ID"
+// @Success      200 {object} helper.Response{data=dto.ForceLogoutResponse} "User successfully logged out"
+// @Failure      400 {object} helper.Response "Invalid user ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The moderator role is required, or the user's role isn't below yours"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/users/{id}/logout [post]
+func (a *AdminHandler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	moderatorId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid user ID", err)
+		return
+	}
+
+	sessionIds, err := a.adminService.ForceLogout(r.Context(), moderatorId, id)
+	if err != nil {
+		a.moderationError(w, err, "Failed to log user out")
+		return
+	}
+
+	a.hub.CloseSessions(id, sessionIds...)
+
+	helper.SuccessResponse(w, "User successfully logged out", &dto.ForceLogoutResponse{
+		Sessions: len(sessionIds),
+	})
+}
+
+// GetStats godoc
+// @Summary      Get server stats
+// @Description  Count accounts, sessions, conversations and messages, and the users connected right now
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=dto.ServerStatsResponse} "Server stats successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The admin role is required"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/stats [get]
+func (a *AdminHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := a.adminService.GetStats(r.Context())
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get server stats", err)
+		return
+	}
+
+	onlineUsers, connections := a.hub.Stats()
+
+	helper.SuccessResponse(w, "Server stats successfully retrieved", &dto.ServerStatsResponse{
+		Users:           stats.Users,
+		Bots:            stats.Bots,
+		SuspendedUsers:  stats.SuspendedUsers,
+		DeletedUsers:    stats.DeletedUsers,
+		Sessions:        stats.Sessions,
+		Privates:        stats.Privates,
+		Messages:        stats.Messages,
+		MessagesLastDay: stats.MessagesLastDay,
+		OnlineUsers:     onlineUsers,
+		Connections:     connections,
+	})
+}
+
+func (a *AdminHandler) moderationError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrRecordNotFound):
+		helper.NotFoundResponse(w, "User not found")
+	case errors.Is(err, repository.ErrModerationDenied):
+		helper.ForbiddenResponse(w, err.Error())
+	default:
+		helper.InternalServerError(w, message, err)
+	}
+}
+
+func NewAdminHandler(adminService service.AdminService, hub *ws.Hub) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		hub:          hub,
+	}
+}
@@ -1,16 +1,22 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
 	"net/http"
+	"strings"
 )
 
 type AuthHandler struct {
 	authService service.AuthService
+	hub         *ws.Hub
 }
 
 // Signup godoc
@@ -22,6 +28,7 @@ type AuthHandler struct {
 // @Param        request body dto.RegisterRequest true "User registration data"
 // @Success      201 {object} helper.Response{data=dto.RegisterResponse} "User successfully created"
 // @Failure      400 {object} helper.Response "Invalid request data or user already exists"
+// @Failure      429 {object} helper.Response "Too many signups from this IP"
 // @Failure      500 {object} helper.Response "Internal server error"
 // @Router       /auth/signup [post]
 func (a *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
@@ -38,8 +45,12 @@ func (a *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := a.authService.Register(r.Context(), &payload)
+	user, err := a.authService.Register(r.Context(), &payload, helper.ClientIP(r))
 	if err != nil {
+		if errors.Is(err, repository.ErrSignupRateLimited) {
+			helper.RateLimitExceededResponse(w, err.Error())
+			return
+		}
 		helper.InternalServerError(w, "failed to signup a user", err)
 		return
 	}
@@ -49,21 +60,24 @@ func (a *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user with email and password
+// @Description  Authenticate user with email and password. Users with two-factor authentication get a challenge token instead, to finish the login at /auth/login/2fa.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
 // @Param        request body dto.LoginRequest true "User login credentials"
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Success      200 {object} helper.Response{data=dto.LoginResponse} "Login successfully"
+// @Success      202 {object} helper.Response{data=dto.TwoFactorChallengeResponse} "Two-factor code required"
 // @Failure      400 {object} helper.Response "Invalid platform or request data"
 // @Failure      401 {object} helper.Response "Invalid credentials"
+// @Failure      403 {object} helper.Response "Email address is not verified or account suspended"
+// @Failure      429 {object} helper.Response "Too many failed attempts"
 // @Failure      500 {object} helper.Response "Internal server error"
 // @Router       /auth/login [post]
 func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	platform := r.Header.Get("X-Platform")
-	if platform != "web" && platform != "mobile" {
-		helper.BadRequestResponse(w, "Invalid platform", fmt.Errorf("platform must be web or mobile"))
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
 		return
 	}
 
@@ -80,9 +94,81 @@ func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	login, err := a.authService.Login(r.Context(), &payload, platform)
+	login, challenge, err := a.authService.Login(r.Context(), &payload, deviceInfo(r, platform))
 	if err != nil {
-		helper.InternalServerError(w, "failed to login", err)
+		switch {
+		case errors.Is(err, repository.ErrInvalidCredentials):
+			helper.UnauthorizedResponse(w, "Invalid credentials")
+		case errors.Is(err, repository.ErrEmailNotVerified):
+			helper.ForbiddenResponse(w, "Email address must be verified first, a verification email has been sent")
+		case errors.Is(err, repository.ErrAccountSuspended):
+			helper.ForbiddenResponse(w, err.Error())
+		case errors.Is(err, repository.ErrLoginThrottled):
+			helper.RateLimitExceededResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "failed to login", err)
+		}
+		return
+	}
+
+	if challenge != nil {
+		helper.AcceptedResponse(w, "Two-factor code required", challenge)
+		return
+	}
+
+	helper.SuccessResponse(w, "User Successfully logged in", login)
+}
+
+// LoginTwoFactor godoc
+// @Summary      Finish a two-factor login
+// @Description  Exchange the challenge token from /auth/login and a code from the authenticator app, or an unused recovery code, for a new session. The platform must match the one the login started on.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.LoginTwoFactorRequest true "Challenge token and code"
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=dto.LoginResponse} "Login successfully"
+// @Failure      400 {object} helper.Response "Invalid platform, request data or code"
+// @Failure      401 {object} helper.Response "Invalid or expired challenge"
+// @Failure      403 {object} helper.Response "Account suspended"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      429 {object} helper.Response "Too many attempts"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/login/2fa [post]
+func (a *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	platform := r.Header.Get("X-Platform")
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
+		return
+	}
+
+	var payload dto.LoginTwoFactorRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateLoginTwoFactorRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	login, err := a.authService.LoginTwoFactor(r.Context(), &payload, deviceInfo(r, platform))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidChallengeToken), errors.Is(err, repository.ErrTwoFactorNotEnabled):
+			helper.UnauthorizedResponse(w, repository.ErrInvalidChallengeToken.Error())
+		case errors.Is(err, repository.ErrInvalidTwoFactorCode):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrAccountSuspended):
+			helper.ForbiddenResponse(w, err.Error())
+		case errors.Is(err, repository.ErrTwoFactorRateLimited):
+			helper.RateLimitExceededResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "failed to login", err)
+		}
 		return
 	}
 
@@ -91,12 +177,12 @@ func (a *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // Logout godoc
 // @Summary      User logout
-// @Description  Invalidate refresh token and logout user
+// @Description  End the current session, invalidating its refresh token and access tokens and closing its WebSocket connections
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Success      200 {object} helper.Response "Logout successful"
 // @Failure      400 {object} helper.Response "Invalid platform"
 // @Failure      401 {object} helper.Response "Unauthorized"
@@ -109,17 +195,25 @@ func (a *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	platform, ok := utils.PlatformFromContext(r.Context())
+	sessionId, ok := utils.SessionIdFromContext(r.Context())
 	if !ok {
 		helper.UnauthorizedResponse(w, "Unauthorized")
 		return
 	}
 
-	if err := a.authService.Logout(r.Context(), userId, platform); err != nil {
+	tokenId, _ := utils.TokenIdFromContext(r.Context())
+
+	if err := a.authService.Logout(r.Context(), userId, sessionId, tokenId); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.UnauthorizedResponse(w, "Session has already ended")
+			return
+		}
 		helper.InternalServerError(w, "failed to logout", err)
 		return
 	}
 
+	a.hub.CloseSessions(userId, sessionId)
+
 	helper.SuccessResponse(w, "User Successfully logged out", nil)
 }
 
@@ -129,17 +223,18 @@ func (a *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        request body dto.RefreshTokenRequest true "Refresh token"
 // @Success      200 {object} helper.Response{data=dto.RefreshTokenResponse} "Token refreshed successfully"
 // @Failure      400 {object} helper.Response "Invalid request data"
-// @Failure      401 {object} helper.Response "Invalid refresh token"
+// @Failure      401 {object} helper.Response "Invalid, expired or reused refresh token"
+// @Failure      403 {object} helper.Response "Account suspended"
 // @Failure      500 {object} helper.Response "Internal server error"
 // @Router       /auth/refresh-token [post]
 func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	platform := r.Header.Get("X-Platform")
-	if platform != "web" && platform != "mobile" {
-		helper.BadRequestResponse(w, "Invalid platform", fmt.Errorf("platform must be web or mobile"))
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
 		return
 	}
 
@@ -156,8 +251,19 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	refreshToken, err := a.authService.RefreshToken(r.Context(), &payload, platform)
+	refreshToken, err := a.authService.RefreshToken(r.Context(), &payload, deviceInfo(r, platform))
 	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidRefreshToken):
+			helper.UnauthorizedResponse(w, "Invalid refresh token")
+			return
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			helper.UnauthorizedResponse(w, err.Error())
+			return
+		case errors.Is(err, repository.ErrAccountSuspended):
+			helper.ForbiddenResponse(w, err.Error())
+			return
+		}
 		helper.InternalServerError(w, "failed to refresh token", err)
 		return
 	}
@@ -172,7 +278,7 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Success      200 {object} helper.Response{data=dto.UserResponse} "User successfully fetched"
 // @Failure      400 {object} helper.Response "Invalid platform"
 // @Failure      401 {object} helper.Response "Unauthorized - Invalid or missing token"
@@ -181,8 +287,8 @@ func (a *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 // @Router       /auth/me [get]
 func (a *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	platform := r.Header.Get("X-Platform")
-	if platform != "web" && platform != "mobile" {
-		helper.BadRequestResponse(w, "Invalid platform", fmt.Errorf("platform must be web or mobile"))
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
 		return
 	}
 
@@ -201,6 +307,14 @@ func (a *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 
 	user, err := a.authService.GetUserByRefreshToken(r.Context(), &payload, platform)
 	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidRefreshToken):
+			helper.UnauthorizedResponse(w, "Invalid refresh token")
+			return
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			helper.UnauthorizedResponse(w, err.Error())
+			return
+		}
 		helper.InternalServerError(w, "failed to fetch user", err)
 		return
 	}
@@ -208,8 +322,347 @@ func (a *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
 	helper.SuccessResponse(w, "User Successfully fetched", user)
 }
 
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
+// GetSessions godoc
+// @Summary      List sessions
+// @Description  List the authenticated user's logged-in devices, most recently used first. The session making the request is marked as current.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.SessionResponse} "Sessions successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/sessions [get]
+func (a *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	sessionId, _ := utils.SessionIdFromContext(r.Context())
+
+	sessions, err := a.authService.GetSessions(r.Context(), userId, sessionId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get sessions", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Sessions successfully retrieved", sessions)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a session
+// @Description  Log one of the authenticated user's devices out, closing its WebSocket connections
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Session ID"
+// @Success      200 {object} helper.Response "Session successfully revoked"
+// @Failure      400 {object} helper.Response "Invalid session ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Session not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/sessions/{id} [delete]
+func (a *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid session ID", err)
+		return
+	}
+
+	if err := a.authService.RevokeSession(r.Context(), userId, id); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Session not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to revoke session", err)
+		return
+	}
+
+	a.hub.CloseSessions(userId, id)
+
+	helper.SuccessResponse(w, "Session successfully revoked", nil)
+}
+
+// RevokeOtherSessions godoc
+// @Summary      Revoke all other sessions
+// @Description  Log every device of the authenticated user out except the one making the request
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response "Other sessions successfully revoked"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/sessions [delete]
+func (a *AuthHandler) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	sessionId, ok := utils.SessionIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	sessionIds, err := a.authService.RevokeOtherSessions(r.Context(), userId, sessionId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to revoke sessions", err)
+		return
+	}
+
+	a.hub.CloseSessions(userId, sessionIds...)
+
+	helper.SuccessResponse(w, "Other sessions successfully revoked", nil)
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email address
+// @Description  Confirm the email address with the token from the verification email. Access tokens issued before only pick the change up once refreshed.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.VerifyEmailRequest true "Verification token"
+// @Success      200 {object} helper.Response "Email address successfully verified"
+// @Failure      400 {object} helper.Response "Invalid or expired token"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/verify-email [post]
+func (a *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var payload dto.VerifyEmailRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateVerifyEmailRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if err := a.authService.VerifyEmail(r.Context(), &payload); err != nil {
+		if errors.Is(err, repository.ErrInvalidVerificationToken) {
+			helper.BadRequestResponse(w, err.Error(), err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to verify email address", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Email address successfully verified", nil)
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send another verification email to the authenticated user, at most once per cooldown period
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response "Verification email successfully sent"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      409 {object} helper.Response "Email address is already verified"
+// @Failure      429 {object} helper.Response "Verification email was sent recently"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/verify-email/resend [post]
+func (a *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	if err := a.authService.ResendVerification(r.Context(), userId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEmailAlreadyVerified):
+			helper.EditConflictResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrVerificationCooldown):
+			helper.RateLimitExceededResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to send verification email", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Verification email successfully sent", nil)
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Email a single-use password reset link if an account with the address exists. The response doesn't tell whether it does.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.ForgotPasswordRequest true "Account email"
+// @Success      202 {object} helper.Response "Reset email sent if the account exists"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      429 {object} helper.Response "Too many reset requests"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/forgot-password [post]
+func (a *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var payload dto.ForgotPasswordRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateForgotPasswordRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if err := a.authService.ForgotPassword(r.Context(), &payload, helper.ClientIP(r)); err != nil {
+		if errors.Is(err, repository.ErrPasswordResetRateLimited) {
+			helper.RateLimitExceededResponse(w, err.Error())
+			return
+		}
+		helper.InternalServerError(w, "Failed to request password reset", err)
+		return
+	}
+
+	helper.AcceptedResponse(w, "If an account with this email exists, a password reset email has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with the token from the password reset email. Every session of the account is logged out and its personal access tokens are deleted.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success      200 {object} helper.Response "Password successfully reset"
+// @Failure      400 {object} helper.Response "Invalid, expired or used token"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/reset-password [post]
+func (a *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var payload dto.ResetPasswordRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateResetPasswordRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	userId, sessionIds, err := a.authService.ResetPassword(r.Context(), &payload)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPasswordResetToken) {
+			helper.BadRequestResponse(w, err.Error(), err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to reset password", err)
+		return
+	}
+
+	a.hub.CloseSessions(userId, sessionIds...)
+
+	helper.SuccessResponse(w, "Password successfully reset", nil)
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Change the authenticated user's password. Every other session of the account is logged out and its personal access tokens are deleted.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.ChangePasswordRequest true "Current and new password"
+// @Success      200 {object} helper.Response "Password successfully changed"
+// @Failure      400 {object} helper.Response "Invalid request data or incorrect current password"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/change-password [post]
+func (a *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	sessionId, ok := utils.SessionIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.ChangePasswordRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateChangePasswordRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	sessionIds, err := a.authService.ChangePassword(r.Context(), userId, sessionId, &payload)
+	if err != nil {
+		if errors.Is(err, repository.ErrIncorrectPassword) {
+			helper.BadRequestResponse(w, err.Error(), err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to change password", err)
+		return
+	}
+
+	a.hub.CloseSessions(userId, sessionIds...)
+
+	helper.SuccessResponse(w, "Password successfully changed", nil)
+}
+
+// errInvalidPlatform answers X-Platform headers naming no registered
+// platform.
+var errInvalidPlatform = fmt.Errorf("platform must be one of %v", domain.Platforms)
+
+// deviceInfo caps the user agent so clients can't fill the sessions table
+// with arbitrarily long headers.
+func deviceInfo(r *http.Request, platform string) *dto.DeviceInfo {
+	userAgent := r.UserAgent()
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	userAgent = strings.ToValidUTF8(userAgent, "")
+
+	return &dto.DeviceInfo{
+		Platform:  platform,
+		UserAgent: userAgent,
+		IP:        helper.ClientIP(r),
+	}
+}
+
+func NewAuthHandler(authService service.AuthService, hub *ws.Hub) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		hub:         hub,
 	}
 }
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type BlockHandler struct {
+	blockService service.BlockService
+}
+
+// BlockUser godoc
+// @Summary      Block a user
+// @Description  Block a user. Neither side can start a conversation with or message the other, and they stop seeing each other's typing and online status.
+// @Tags         Blocks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.BlockRequest true "User to block"
+// @Success      201 {object} helper.Response{data=dto.BlockResponse} "User successfully blocked"
+// @Failure      400 {object} helper.Response "Invalid request data or blocking yourself"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      409 {object} helper.Response "User is already blocked"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /blocks [post]
+func (b *BlockHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.BlockRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBlockRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	block, err := b.blockService.BlockUser(r.Context(), userId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrBlockSelf):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "User not found")
+		case errors.Is(err, repository.ErrAlreadyBlocked):
+			helper.EditConflictResponse(w, "User is already blocked", err)
+		default:
+			helper.InternalServerError(w, "Failed to block user", err)
+		}
+		return
+	}
+
+	helper.CreatedResponse(w, "User successfully blocked", block)
+}
+
+// GetBlockedUsers godoc
+// @Summary      List blocked users
+// @Description  List the users blocked by the authenticated user, most recently blocked first
+// @Tags         Blocks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.BlockResponse} "Blocked users successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /blocks [get]
+func (b *BlockHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	blocks, err := b.blockService.GetBlockedUsers(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get blocked users", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Blocked users successfully retrieved", blocks)
+}
+
+// UnblockUser godoc
+// @Summary      Unblock a user
+// @Description  Unblock a user previously blocked by the authenticated user
+// @Tags         Blocks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Blocked user's ID"
+// @Success      200 {object} helper.Response "User successfully unblocked"
+// @Failure      400 {object} helper.Response "Invalid user ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "User is not blocked"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /blocks/{id} [delete]
+func (b *BlockHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid user ID", err)
+		return
+	}
+
+	if err := b.blockService.UnblockUser(r.Context(), userId, id); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "User is not blocked")
+			return
+		}
+		helper.InternalServerError(w, "Failed to unblock user", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "User successfully unblocked", nil)
+}
+
+func NewBlockHandler(blockService service.BlockService) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
@@ -0,0 +1,512 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BotHandler struct {
+	botService service.BotService
+	hub        *ws.Hub
+}
+
+// CreateBot godoc
+// @Summary      Create a bot
+// @Description  Create a bot account owned by the authenticated user. The Bot API token is only returned once.
+// @Tags         Bots
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.BotRequest true "Bot name and username"
+// @Success      201 {object} helper.Response{data=dto.BotResponse} "Bot successfully created"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      409 {object} helper.Response "Bot username already exists"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /bots [post]
+func (b *BotHandler) CreateBot(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.BotRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBotRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	bot, err := b.botService.CreateBot(r.Context(), &payload, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrBotUsernameExists) {
+			helper.EditConflictResponse(w, "Bot username already exists", err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to create bot", err)
+		return
+	}
+
+	helper.CreatedResponse(w, "Bot successfully created", bot)
+}
+
+// GetBots godoc
+// @Summary      List my bots
+// @Description  List the bots owned by the authenticated user
+// @Tags         Bots
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.BotResponse} "Bots successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /bots [get]
+func (b *BotHandler) GetBots(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	bots, err := b.botService.GetBotsForOwner(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get bots", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Bots successfully retrieved", bots)
+}
+
+// RegenerateToken godoc
+// @Summary      Regenerate a bot token
+// @Description  Issue a new Bot API token for one of the authenticated user's bots, invalidating the previous one
+// @Tags         Bots
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Bot ID"
+// @Success      200 {object} helper.Response{data=dto.BotResponse} "Bot token successfully regenerated"
+// @Failure      400 {object} helper.Response "Invalid bot ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Bot not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /bots/{id}/token [post]
+func (b *BotHandler) RegenerateToken(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid bot ID", err)
+		return
+	}
+
+	bot, err := b.botService.RegenerateToken(r.Context(), id, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Bot not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to regenerate bot token", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Bot token successfully regenerated", bot)
+}
+
+// ServeBotApi dispatches /bot{token}/{method} requests. Like the Telegram Bot
+// API, methods accept GET or POST with query, form or JSON parameters.
+func (b *BotHandler) ServeBotApi(w http.ResponseWriter, r *http.Request) {
+	token, method, ok := b.parseBotApiPath(r.URL.Path)
+	if !ok {
+		helper.HTTPRouterNotFoundResponse(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		helper.BotApiErrorResponse(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	bot, err := b.botService.Authenticate(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidBotToken) {
+			helper.BotApiErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		helper.BotApiErrorResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	params, err := b.readBotApiParams(w, r)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	switch method {
+	case "getMe":
+		helper.BotApiSuccessResponse(w, b.botService.GetMe(r.Context(), bot))
+	case "sendMessage":
+		b.sendMessage(w, r, bot, params)
+	case "getUpdates":
+		b.getUpdates(w, r, bot, params)
+	case "setWebhook":
+		b.setWebhook(w, r, bot, params)
+	case "answerCallbackQuery":
+		b.answerCallbackQuery(w, r, bot, params)
+	case "editMessageReplyMarkup":
+		b.editMessageReplyMarkup(w, r, bot, params)
+	default:
+		helper.BotApiErrorResponse(w, http.StatusNotFound, "Not Found: method not found")
+	}
+}
+
+func (b *BotHandler) sendMessage(w http.ResponseWriter, r *http.Request, bot *domain.Bot, params url.Values) {
+	chatId, err := b.parseUintParam(params, "chat_id", 0)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	replyMarkup, err := b.parseReplyMarkupParam(params)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	input := &dto.BotSendMessageRequest{
+		ChatId:      chatId,
+		Text:        params.Get("text"),
+		ReplyMarkup: replyMarkup,
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBotSendMessageRequest(v, input)
+	if !v.Valid() {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+b.firstError(v))
+		return
+	}
+
+	message, apiMessage, err := b.botService.SendMessage(r.Context(), bot, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrBotChatNotFound) {
+			helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: chat not found")
+			return
+		}
+		if errors.Is(err, repository.ErrUserBlocked) {
+			helper.BotApiErrorResponse(w, http.StatusForbidden, "Forbidden: bot was blocked by the user")
+			return
+		}
+		helper.BotApiErrorResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	b.hub.SendEventToUserIds([]uint{input.ChatId}, bot.UserId, ws.EventMessage, map[string]any{
+		"message": message,
+	})
+
+	helper.BotApiSuccessResponse(w, apiMessage)
+}
+
+func (b *BotHandler) getUpdates(w http.ResponseWriter, r *http.Request, bot *domain.Bot, params url.Values) {
+	offset, err := b.parseUintParam(params, "offset", 0)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	limit, err := b.parseUintParam(params, "limit", 100)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	timeout, err := b.parseUintParam(params, "timeout", 0)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	input := &dto.BotGetUpdatesRequest{
+		Offset:  offset,
+		Limit:   int(limit),
+		Timeout: time.Duration(timeout) * time.Second,
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBotGetUpdatesRequest(v, input)
+	if !v.Valid() {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+b.firstError(v))
+		return
+	}
+
+	// Long polling outlives the server's write timeout otherwise
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(input.Timeout + 10*time.Second))
+
+	updates, err := b.botService.GetUpdates(r.Context(), bot, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrBotWebhookActive) {
+			helper.BotApiErrorResponse(w, http.StatusConflict, "Conflict: "+err.Error())
+			return
+		}
+		helper.BotApiErrorResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	helper.BotApiSuccessResponse(w, updates)
+}
+
+func (b *BotHandler) setWebhook(w http.ResponseWriter, r *http.Request, bot *domain.Bot, params url.Values) {
+	input := &dto.BotSetWebhookRequest{
+		Url:         params.Get("url"),
+		SecretToken: params.Get("secret_token"),
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBotSetWebhookRequest(v, input)
+	if !v.Valid() {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+b.firstError(v))
+		return
+	}
+
+	if err := b.botService.SetWebhook(r.Context(), bot, input); err != nil {
+		if errors.Is(err, repository.ErrWebhookURLNotAllowed) {
+			helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+			return
+		}
+		helper.BotApiErrorResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	helper.BotApiSuccessResponse(w, true)
+}
+
+func (b *BotHandler) answerCallbackQuery(w http.ResponseWriter, r *http.Request, bot *domain.Bot, params url.Values) {
+	callbackQueryId, err := b.parseUintParam(params, "callback_query_id", 0)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	showAlert, err := b.parseBoolParam(params, "show_alert")
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	input := &dto.BotAnswerCallbackQueryRequest{
+		CallbackQueryId: callbackQueryId,
+		Text:            params.Get("text"),
+		ShowAlert:       showAlert,
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBotAnswerCallbackQueryRequest(v, input)
+	if !v.Valid() {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+b.firstError(v))
+		return
+	}
+
+	answer, err := b.botService.AnswerCallbackQuery(r.Context(), bot, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrCallbackQueryNotFound) {
+			helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+			return
+		}
+		helper.BotApiErrorResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	b.hub.SendEventToUserIds([]uint{answer.UserId}, bot.UserId, ws.EventCallbackAnswer, map[string]any{
+		"callback_query_id": answer.CallbackQueryId,
+		"message_id":        answer.MessageId,
+		"text":              answer.Text,
+		"show_alert":        answer.ShowAlert,
+	})
+
+	helper.BotApiSuccessResponse(w, true)
+}
+
+func (b *BotHandler) editMessageReplyMarkup(w http.ResponseWriter, r *http.Request, bot *domain.Bot, params url.Values) {
+	chatId, err := b.parseUintParam(params, "chat_id", 0)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	messageId, err := b.parseUintParam(params, "message_id", 0)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	replyMarkup, err := b.parseReplyMarkupParam(params)
+	if err != nil {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	input := &dto.BotEditMessageReplyMarkupRequest{
+		ChatId:      chatId,
+		MessageId:   messageId,
+		ReplyMarkup: replyMarkup,
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateBotEditMessageReplyMarkupRequest(v, input)
+	if !v.Valid() {
+		helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+b.firstError(v))
+		return
+	}
+
+	message, apiMessage, err := b.botService.EditMessageReplyMarkup(r.Context(), bot, input)
+	if err != nil {
+		if errors.Is(err, repository.ErrBotMessageNotFound) {
+			helper.BotApiErrorResponse(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+			return
+		}
+		helper.BotApiErrorResponse(w, http.StatusInternalServerError, "Internal Server Error")
+		return
+	}
+
+	b.hub.SendEventToUserIds([]uint{input.ChatId}, bot.UserId, ws.EventMessageEdited, map[string]any{
+		"message": message,
+	})
+
+	helper.BotApiSuccessResponse(w, apiMessage)
+}
+
+func (b *BotHandler) parseBotApiPath(path string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(path, "/bot")
+	if !ok {
+		return "", "", false
+	}
+
+	token, method, ok := strings.Cut(rest, "/")
+	if !ok || token == "" || method == "" || strings.Contains(method, "/") {
+		return "", "", false
+	}
+	return token, method, true
+}
+
+func (b *BotHandler) readBotApiParams(w http.ResponseWriter, r *http.Request) (url.Values, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if r.Method == http.MethodPost && mediaType == "application/json" {
+		var body map[string]any
+		if err := helper.ReadJSON(w, r, &body); err != nil {
+			return nil, err
+		}
+
+		params := r.URL.Query()
+		for key, value := range body {
+			switch v := value.(type) {
+			case string:
+				params.Set(key, v)
+			default:
+				raw, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				params.Set(key, string(raw))
+			}
+		}
+		return params, nil
+	}
+
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			return nil, err
+		}
+		return r.Form, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	return r.Form, nil
+}
+
+func (b *BotHandler) parseUintParam(params url.Values, key string, fallback uint) (uint, error) {
+	value := params.Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return uint(parsed), nil
+}
+
+func (b *BotHandler) parseBoolParam(params url.Values, key string) (bool, error) {
+	value := params.Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return parsed, nil
+}
+
+// parseReplyMarkupParam decodes reply_markup, which the Bot API always passes
+// as a JSON-serialized object.
+func (b *BotHandler) parseReplyMarkupParam(params url.Values) (*dto.InlineKeyboardMarkup, error) {
+	value := params.Get("reply_markup")
+	if value == "" {
+		return nil, nil
+	}
+
+	var replyMarkup dto.InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(value), &replyMarkup); err != nil {
+		return nil, fmt.Errorf("can't parse reply keyboard markup JSON object")
+	}
+	return &replyMarkup, nil
+}
+
+func (b *BotHandler) firstError(v *helper.Validator) string {
+	for _, message := range v.Errors {
+		return message
+	}
+	return "invalid parameters"
+}
+
+func NewBotHandler(botService service.BotService, hub *ws.Hub) *BotHandler {
+	return &BotHandler{
+		botService: botService,
+		hub:        hub,
+	}
+}
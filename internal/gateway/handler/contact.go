@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type ContactHandler struct {
+	contactService service.ContactService
+	hub            *ws.Hub
+}
+
+// AddContact godoc
+// @Summary      Add a contact
+// @Description  Add a user to the authenticated user's contacts, optionally under a local alias
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.ContactRequest true "User to add and optional alias"
+// @Success      201 {object} helper.Response{data=dto.ContactResponse} "Contact successfully added"
+// @Failure      400 {object} helper.Response "Invalid request data or adding yourself"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "One of the users has blocked the other"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      409 {object} helper.Response "User is already a contact"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /contacts [post]
+func (c *ContactHandler) AddContact(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.ContactRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateContactRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	contact, err := c.contactService.AddContact(r.Context(), userId, &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrContactSelf):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "User not found")
+		case errors.Is(err, repository.ErrContactExists):
+			helper.EditConflictResponse(w, "User is already a contact", err)
+		case errors.Is(err, repository.ErrUserBlocked):
+			helper.ForbiddenResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to add contact", err)
+		}
+		return
+	}
+
+	contacts := []dto.ContactResponse{*contact}
+	if err := c.setOnline(r.Context(), userId, contacts); err != nil {
+		helper.InternalServerError(w, "Failed to get online status", err)
+		return
+	}
+
+	helper.CreatedResponse(w, "Contact successfully added", contacts[0])
+}
+
+// GetContacts godoc
+// @Summary      List my contacts
+// @Description  List the authenticated user's contacts with their current online status, as far as their last seen and avatar privacy rules allow
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.ContactResponse} "Contacts successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /contacts [get]
+func (c *ContactHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	contacts, err := c.contactService.GetContacts(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get contacts", err)
+		return
+	}
+
+	if err := c.setOnline(r.Context(), userId, contacts); err != nil {
+		helper.InternalServerError(w, "Failed to get online status", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Contacts successfully retrieved", contacts)
+}
+
+// UpdateContact godoc
+// @Summary      Rename a contact
+// @Description  Change the local alias of a contact, an empty alias removes it
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Contact's user ID"
+// @Param        request body dto.UpdateContactRequest true "New alias"
+// @Success      200 {object} helper.Response{data=dto.ContactResponse} "Contact successfully updated"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Contact not found"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /contacts/{id} [patch]
+func (c *ContactHandler) UpdateContact(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid contact ID", err)
+		return
+	}
+
+	var payload dto.UpdateContactRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateContactRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	contact, err := c.contactService.UpdateContact(r.Context(), userId, id, &payload)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Contact not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to update contact", err)
+		return
+	}
+
+	contacts := []dto.ContactResponse{*contact}
+	if err := c.setOnline(r.Context(), userId, contacts); err != nil {
+		helper.InternalServerError(w, "Failed to get online status", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Contact successfully updated", contacts[0])
+}
+
+// DeleteContact godoc
+// @Summary      Remove a contact
+// @Description  Remove a user from the authenticated user's contacts
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Contact's user ID"
+// @Success      200 {object} helper.Response "Contact successfully removed"
+// @Failure      400 {object} helper.Response "Invalid contact ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Contact not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /contacts/{id} [delete]
+func (c *ContactHandler) DeleteContact(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid contact ID", err)
+		return
+	}
+
+	if err := c.contactService.DeleteContact(r.Context(), userId, id); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Contact not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to remove contact", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Contact successfully removed", nil)
+}
+
+// ImportContacts godoc
+// @Summary      Import contacts
+// @Description  Add the registered users among a batch of up to 500 emails to the authenticated user's contacts. Emails that don't belong to any user are returned as unregistered, users in a block with you are left out.
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.ImportContactsRequest true "Emails to look up"
+// @Success      200 {object} helper.Response{data=dto.ImportContactsResponse} "Contacts successfully imported"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /contacts/import [post]
+func (c *ContactHandler) ImportContacts(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.ImportContactsRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateImportContactsRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	result, err := c.contactService.ImportContacts(r.Context(), userId, &payload)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to import contacts", err)
+		return
+	}
+
+	if err := c.setOnline(r.Context(), userId, result.Contacts); err != nil {
+		helper.InternalServerError(w, "Failed to get online status", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Contacts successfully imported", result)
+}
+
+// setOnline fills in the online status of the contacts ownerId may see it of,
+// the others always show as offline.
+func (c *ContactHandler) setOnline(ctx context.Context, ownerId uint, contacts []dto.ContactResponse) error {
+	contactIds := make([]uint, len(contacts))
+	for i, contact := range contacts {
+		contactIds[i] = contact.User.Id
+	}
+
+	visible, err := c.contactService.PresenceVisible(ctx, ownerId, contactIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range contacts {
+		id := contacts[i].User.Id
+		contacts[i].Online = visible[id] && c.hub.IsOnline(id)
+	}
+	return nil
+}
+
+func NewContactHandler(contactService service.ContactService, hub *ws.Hub) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+		hub:            hub,
+	}
+}
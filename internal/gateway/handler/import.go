@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+// ImportTelegram godoc
+// @Summary      Import a Telegram Desktop export
+// @Description  Start importing a Telegram Desktop result.json export located under the server's import root. Export users are mapped to existing accounts by email. Re-running the same export only imports messages that are still missing.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.TelegramImportRequest true "Export path relative to the import root, export user id to email mapping and optional timezone"
+// @Success      202 {object} helper.Response{data=dto.ImportJobResponse} "Import successfully started"
+// @Failure      400 {object} helper.Response "Invalid request data or unreadable export"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The admin role is required or path outside the import root"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/imports/telegram [post]
+func (i *ImportHandler) ImportTelegram(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.TelegramImportRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateTelegramImportRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	job, err := i.importService.StartTelegramImport(r.Context(), &payload, userId)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrImportDisabled), errors.Is(err, repository.ErrImportPathNotAllowed):
+			helper.ForbiddenResponse(w, err.Error())
+		default:
+			helper.BadRequestResponse(w, "Failed to start import", err)
+		}
+		return
+	}
+
+	helper.AcceptedResponse(w, "Import successfully started", job)
+}
+
+// GetImportJob godoc
+// @Summary      Get import job
+// @Description  Get the progress of an import job
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Import job ID"
+// @Success      200 {object} helper.Response{data=dto.ImportJobResponse} "Import job successfully retrieved"
+// @Failure      400 {object} helper.Response "Invalid import job ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Forbidden - The admin role is required"
+// @Failure      404 {object} helper.Response "Import job not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /admin/imports/{id} [get]
+func (i *ImportHandler) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid import job ID", err)
+		return
+	}
+
+	job, err := i.importService.GetImportJob(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Import job not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to get import job", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Import job successfully retrieved", job)
+}
+
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
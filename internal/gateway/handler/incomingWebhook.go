@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type IncomingWebhookHandler struct {
+	incomingWebhookService service.IncomingWebhookService
+	hub                    *ws.Hub
+}
+
+// CreateIncomingWebhook godoc
+// @Summary      Create an incoming webhook
+// @Description  Create a URL external systems can post messages to in a private conversation. Messages are sent on behalf of the authenticated user and shown under the given integration name. The URL contains a secret token and is only returned once.
+// @Tags         Incoming Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Private conversation ID"
+// @Param        request body dto.IncomingWebhookRequest true "Integration name"
+// @Success      201 {object} helper.Response{data=dto.IncomingWebhookResponse} "Incoming webhook successfully created"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Private conversation not found"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /conversations/privates/{id}/incoming-webhooks [post]
+func (i *IncomingWebhookHandler) CreateIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	privateId, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid conversation ID", err)
+		return
+	}
+
+	var payload dto.IncomingWebhookRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateIncomingWebhookRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	webhook, err := i.incomingWebhookService.CreateIncomingWebhook(r.Context(), privateId, userId, &payload)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Private conversation not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to create incoming webhook", err)
+		return
+	}
+
+	helper.CreatedResponse(w, "Incoming webhook successfully created", webhook)
+}
+
+// GetIncomingWebhooks godoc
+// @Summary      List incoming webhooks
+// @Description  List the incoming webhooks of a private conversation
+// @Tags         Incoming Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Private conversation ID"
+// @Success      200 {object} helper.Response{data=[]dto.IncomingWebhookResponse} "Incoming webhooks successfully retrieved"
+// @Failure      400 {object} helper.Response "Invalid conversation ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Private conversation not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /conversations/privates/{id}/incoming-webhooks [get]
+func (i *IncomingWebhookHandler) GetIncomingWebhooks(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	privateId, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid conversation ID", err)
+		return
+	}
+
+	webhooks, err := i.incomingWebhookService.GetIncomingWebhooks(r.Context(), privateId, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Private conversation not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to get incoming webhooks", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Incoming webhooks successfully retrieved", webhooks)
+}
+
+// DeleteIncomingWebhook godoc
+// @Summary      Delete an incoming webhook
+// @Description  Delete an incoming webhook of one of the authenticated user's conversations, invalidating its URL
+// @Tags         Incoming Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Incoming webhook ID"
+// @Success      200 {object} helper.Response "Incoming webhook successfully deleted"
+// @Failure      400 {object} helper.Response "Invalid incoming webhook ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Incoming webhook not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /incoming-webhooks/{id} [delete]
+func (i *IncomingWebhookHandler) DeleteIncomingWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid incoming webhook ID", err)
+		return
+	}
+
+	if err := i.incomingWebhookService.DeleteIncomingWebhook(r.Context(), id, userId); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Incoming webhook not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to delete incoming webhook", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Incoming webhook successfully deleted", nil)
+}
+
+// PostMessage godoc
+// @Summary      Post through an incoming webhook
+// @Description  Post a text message into the webhook's conversation. The URL itself authenticates the caller; requests are rate limited per webhook.
+// @Tags         Incoming Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Incoming webhook ID"
+// @Param        token path string true "Incoming webhook token"
+// @Param        request body dto.IncomingWebhookMessageRequest true "Message text and optional format (plain or markdown)"
+// @Success      201 {object} helper.Response{data=dto.MessageResponse} "Message successfully created"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Invalid incoming webhook URL"
+// @Failure      403 {object} helper.Response "One of the users has blocked the other"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      429 {object} helper.Response "Rate limit exceeded"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /hooks/{id}/{token} [post]
+func (i *IncomingWebhookHandler) PostMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.UnauthorizedResponse(w, "Invalid incoming webhook URL")
+		return
+	}
+
+	var payload dto.IncomingWebhookMessageRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateIncomingWebhookMessageRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	message, participantIds, err := i.incomingWebhookService.PostMessage(r.Context(), id, r.PathValue("token"), &payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidIncomingWebhookToken):
+			helper.UnauthorizedResponse(w, "Invalid incoming webhook URL")
+		case errors.Is(err, repository.ErrIncomingWebhookRateLimited):
+			helper.RateLimitExceededResponse(w, "Rate limit exceeded, slow down")
+		case errors.Is(err, repository.ErrUserBlocked):
+			helper.ForbiddenResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to post message", err)
+		}
+		return
+	}
+
+	i.hub.SendEventToUserIds(participantIds, message.FromId, ws.EventMessage, map[string]any{
+		"message": message,
+	})
+
+	helper.CreatedResponse(w, "Message successfully created", message)
+}
+
+func NewIncomingWebhookHandler(incomingWebhookService service.IncomingWebhookService, hub *ws.Hub) *IncomingWebhookHandler {
+	return &IncomingWebhookHandler{
+		incomingWebhookService: incomingWebhookService,
+		hub:                    hub,
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/keyring"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keyring *keyring.Keyring
+}
+
+// JWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys access tokens are signed with, by kid, for other services to verify them. Includes the next key ahead of its use. Empty while tokens are signed with HS256.
+// @Tags         Authentication
+// @Produce      json
+// @Success      200 {object} keyring.JWKS "Key set"
+// @Router       /.well-known/jwks.json [get]
+func (j *JWKSHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	helper.JSONResponse(w, j.keyring.JWKS())
+}
+
+func NewJWKSHandler(keyring *keyring.Keyring) *JWKSHandler {
+	return &JWKSHandler{
+		keyring: keyring,
+	}
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
 	"net/http"
@@ -19,11 +21,12 @@ type MessageHandler struct {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        request body dto.MessageRequest true "Message details"
 // @Success      201 {object} helper.Response{data=dto.MessageResponse} "Message successfully created"
 // @Failure      400 {object} helper.Response "Invalid request data"
 // @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Only bots can attach inline keyboards, or one of the users has blocked the other, or the recipient deleted their account"
 // @Failure      404 {object} helper.Response "Private conversation not found"
 // @Failure      500 {object} helper.Response "Internal server error"
 // @Router       /messages [post]
@@ -49,6 +52,10 @@ func (m *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 
 	message, err := m.messageService.SendMessage(r.Context(), &payload, userId)
 	if err != nil {
+		if errors.Is(err, repository.ErrReplyMarkupNotAllowed) || errors.Is(err, repository.ErrUserBlocked) || errors.Is(err, repository.ErrAccountDeleted) {
+			helper.ForbiddenResponse(w, err.Error())
+			return
+		}
 		helper.InternalServerError(w, "failed to send message", err)
 		return
 	}
@@ -63,7 +70,7 @@ func (m *MessageHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Message ID"
 // @Success      200 {object} helper.Response{data=dto.MessageResponse} "Message successfully retrieved"
 // @Failure      400 {object} helper.Response "Invalid message ID"
@@ -101,7 +108,7 @@ func (m *MessageHandler) GetMessage(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Private conversation ID"
 // @Param        page query int false "Page number" default(1)
 // @Param        limit query int false "Items per page" default(20) maximum(100)
@@ -143,7 +150,7 @@ func (m *MessageHandler) GetPrivateMessages(w http.ResponseWriter, r *http.Reque
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Private conversation ID"
 // @Success      200 {object} helper.Response{data=[]dto.MessageResponse} "Undelivered messages successfully fetched"
 // @Failure      400 {object} helper.Response "Invalid conversation ID"
@@ -180,7 +187,7 @@ func (m *MessageHandler) GetUndeliveredMessages(w http.ResponseWriter, r *http.R
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Message ID"
 // @Success      200 {object} helper.Response "Message successfully marked as read"
 // @Failure      400 {object} helper.Response "Invalid message ID"
@@ -217,7 +224,7 @@ func (m *MessageHandler) MarkMessageAsRead(w http.ResponseWriter, r *http.Reques
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Message ID"
 // @Success      200 {object} helper.Response "Message successfully marked as delivered"
 // @Failure      400 {object} helper.Response "Invalid message ID"
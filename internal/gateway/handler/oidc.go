@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"net/http"
+)
+
+type OIDCHandler struct {
+	oidcService service.OIDCService
+}
+
+// Start godoc
+// @Summary      Start single sign-on
+// @Description  Start an OpenID Connect login. Send the user to authorization_url; the identity provider redirects back with code and state, to be passed to /auth/oidc/callback from the same platform.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.OIDCStartRequest false "Device name for the new session"
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=dto.OIDCStartResponse} "Single sign-on started"
+// @Failure      400 {object} helper.Response "Invalid platform or request data"
+// @Failure      404 {object} helper.Response "Single sign-on is not configured"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      502 {object} helper.Response "Identity provider unavailable"
+// @Router       /auth/oidc/start [post]
+func (o *OIDCHandler) Start(w http.ResponseWriter, r *http.Request) {
+	platform := r.Header.Get("X-Platform")
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
+		return
+	}
+
+	var payload dto.OIDCStartRequest
+	if r.ContentLength != 0 {
+		if err := helper.ReadJSON(w, r, &payload); err != nil {
+			helper.BadRequestResponse(w, "Invalid given payload", err)
+			return
+		}
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateOIDCStartRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	start, err := o.oidcService.Start(r.Context(), &payload, platform)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOIDCNotConfigured):
+			helper.NotFoundResponse(w, err.Error())
+		case errors.Is(err, repository.ErrOIDCFailed):
+			helper.ErrorResponse(w, http.StatusBadGateway, err.Error(), err)
+		default:
+			helper.InternalServerError(w, "Failed to start single sign-on", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Single sign-on started", start)
+}
+
+// Callback godoc
+// @Summary      Finish single sign-on
+// @Description  Exchange the code and state the identity provider redirected back with for a session. The account is matched by the provider's subject, or the first time by verified email, linking an existing user whose email is verified or creating a new one. Users with two-factor authentication get a challenge token instead, to finish at /auth/login/2fa.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.OIDCCallbackRequest true "Code and state from the redirect"
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=dto.LoginResponse} "Login successfully"
+// @Success      202 {object} helper.Response{data=dto.TwoFactorChallengeResponse} "Two-factor code required"
+// @Failure      400 {object} helper.Response "Invalid platform, request data or state"
+// @Failure      401 {object} helper.Response "Single sign-on failed"
+// @Failure      403 {object} helper.Response "Email address not verified by the identity provider or the existing account, or account suspended"
+// @Failure      404 {object} helper.Response "Single sign-on is not configured"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/oidc/callback [post]
+func (o *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	platform := r.Header.Get("X-Platform")
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
+		return
+	}
+
+	var payload dto.OIDCCallbackRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateOIDCCallbackRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	login, challenge, err := o.oidcService.Callback(r.Context(), &payload, deviceInfo(r, platform))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOIDCNotConfigured):
+			helper.NotFoundResponse(w, err.Error())
+		case errors.Is(err, repository.ErrInvalidOIDCState):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrOIDCFailed):
+			helper.UnauthorizedResponse(w, err.Error())
+		case errors.Is(err, repository.ErrOIDCEmailNotVerified), errors.Is(err, repository.ErrOIDCAccountNotVerified), errors.Is(err, repository.ErrAccountSuspended):
+			helper.ForbiddenResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "failed to login", err)
+		}
+		return
+	}
+
+	if challenge != nil {
+		helper.AcceptedResponse(w, "Two-factor code required", challenge)
+		return
+	}
+
+	helper.SuccessResponse(w, "User Successfully logged in", login)
+}
+
+func NewOIDCHandler(oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type PersonalAccessTokenHandler struct {
+	personalAccessTokenService service.PersonalAccessTokenService
+}
+
+// CreateToken godoc
+// @Summary      Create a personal access token
+// @Description  Create a token for scripts to call the API with as the authenticated user, sent as "Authorization: Bearer <token>" without an X-Platform header. It only works on routes covered by its scopes: messages:read, messages:send and conversations:manage. The token is only shown in this response.
+// @Tags         Personal Access Tokens
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.PersonalAccessTokenRequest true "Name, scopes and optional expiry"
+// @Success      201 {object} helper.Response{data=dto.CreatedPersonalAccessTokenResponse} "Personal access token successfully created"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/tokens [post]
+func (p *PersonalAccessTokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.PersonalAccessTokenRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidatePersonalAccessTokenRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	token, err := p.personalAccessTokenService.CreateToken(r.Context(), userId, &payload)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to create personal access token", err)
+		return
+	}
+
+	helper.CreatedResponse(w, "Personal access token successfully created", token)
+}
+
+// GetTokens godoc
+// @Summary      List personal access tokens
+// @Description  List the authenticated user's personal access tokens, newest first, with when each was last used
+// @Tags         Personal Access Tokens
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.PersonalAccessTokenResponse} "Personal access tokens successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/tokens [get]
+func (p *PersonalAccessTokenHandler) GetTokens(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	tokens, err := p.personalAccessTokenService.GetTokens(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get personal access tokens", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Personal access tokens successfully retrieved", tokens)
+}
+
+// RevokeToken godoc
+// @Summary      Revoke a personal access token
+// @Description  Delete one of the authenticated user's personal access tokens, it stops working right away
+// @Tags         Personal Access Tokens
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Token ID"
+// @Success      200 {object} helper.Response "Personal access token successfully revoked"
+// @Failure      400 {object} helper.Response "Invalid token ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Personal access token not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/tokens/{id} [delete]
+func (p *PersonalAccessTokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid token ID", err)
+		return
+	}
+
+	if err := p.personalAccessTokenService.RevokeToken(r.Context(), userId, id); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Personal access token not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to revoke personal access token", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Personal access token successfully revoked", nil)
+}
+
+func NewPersonalAccessTokenHandler(personalAccessTokenService service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		personalAccessTokenService: personalAccessTokenService,
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type PrivacyHandler struct {
+	privacyService service.PrivacyService
+}
+
+// GetPrivacyRules godoc
+// @Summary      Get my privacy settings
+// @Description  Get the authenticated user's privacy rules for last seen, avatar and who can start a private conversation. Rules never changed are reported at their default, everybody.
+// @Tags         Privacy
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.PrivacyRuleResponse} "Privacy settings successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/privacy [get]
+func (p *PrivacyHandler) GetPrivacyRules(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	rules, err := p.privacyService.GetPrivacyRules(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get privacy settings", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Privacy settings successfully retrieved", rules)
+}
+
+// UpdatePrivacyRule godoc
+// @Summary      Update a privacy rule
+// @Description  Set who passes one of the authenticated user's privacy rules: everybody, contacts or nobody. Users in allow_user_ids and deny_user_ids are always allowed or refused, whatever the level.
+// @Tags         Privacy
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        key path string true "Rule to change" Enums(last_seen, avatar, private_chats)
+// @Param        request body dto.PrivacyRuleRequest true "Level and exceptions"
+// @Success      200 {object} helper.Response{data=dto.PrivacyRuleResponse} "Privacy rule successfully updated"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/privacy/{key} [put]
+func (p *PrivacyHandler) UpdatePrivacyRule(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.PrivacyRuleRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	key := r.PathValue("key")
+
+	v := helper.NewValidator()
+	dto.ValidatePrivacyRuleRequest(v, key, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	rule, err := p.privacyService.UpdatePrivacyRule(r.Context(), userId, key, &payload)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to update privacy rule", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Privacy rule successfully updated", rule)
+}
+
+func NewPrivacyHandler(privacyService service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
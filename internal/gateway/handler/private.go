@@ -21,11 +21,12 @@ type PrivateHandler struct {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        request body dto.PrivateRequest true "Receiver user ID"
 // @Success      201 {object} helper.Response{data=dto.PrivateResponse} "Private conversation successfully created"
 // @Failure      400 {object} helper.Response "Invalid request data or trying to create conversation with yourself"
 // @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "One of the users has blocked the other, or the receiver's privacy settings don't allow it, or the receiver deleted their account"
 // @Failure      409 {object} helper.Response "Private conversation already exists"
 // @Failure      500 {object} helper.Response "Internal server error"
 // @Router       /conversations/privates [post]
@@ -50,6 +51,8 @@ func (p *PrivateHandler) CreatePrivate(w http.ResponseWriter, r *http.Request) {
 			helper.BadRequestResponse(w, "Cannot create conversation with yourself", err)
 		case errors.Is(err, repository.ErrPrivateAlreadyExists):
 			helper.EditConflictResponse(w, "Private conversation already exists", err)
+		case errors.Is(err, repository.ErrUserBlocked), errors.Is(err, repository.ErrPrivacyRestricted), errors.Is(err, repository.ErrAccountDeleted):
+			helper.ForbiddenResponse(w, err.Error())
 		default:
 			helper.InternalServerError(w, "Failed to create private", err)
 		}
@@ -66,7 +69,7 @@ func (p *PrivateHandler) CreatePrivate(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Private conversation ID"
 // @Success      200 {object} helper.Response{data=dto.PrivateResponse} "Private conversation successfully retrieved"
 // @Failure      400 {object} helper.Response "Invalid private conversation ID"
@@ -111,7 +114,7 @@ func (p *PrivateHandler) GetPrivateById(w http.ResponseWriter, r *http.Request)
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Success      200 {object} helper.Response{data=[]dto.PrivateResponse} "Conversations successfully retrieved"
 // @Failure      401 {object} helper.Response "Unauthorized"
 // @Failure      404 {object} helper.Response "User not found"
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/coder/websocket"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+	"time"
+)
+
+type QRLoginHandler struct {
+	qrLoginService service.QRLoginService
+	logger         utils.LoggerStrategy
+}
+
+// Connect godoc
+// @Summary      Wait for a QR code login
+// @Description  Unauthenticated WebSocket for a device to be logged in by another one. The server sends a qr_login_token event with the token to show as a QR code; once a logged-in device approves it at /auth/qr/approve, a qr_login_success event carries the new session's tokens. A qr_login_expired event ends the wait, connect again for a new code. Browsers can't set headers on WebSockets, so the platform may also be passed as a query parameter.
+// @Tags         Authentication
+// @Param        X-Platform header string false "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        platform query string false "Platform type, when X-Platform can't be sent" Enums(web, mobile, desktop, tablet, api)
+// @Param        device_name query string false "Device name for the new session"
+// @Success      101 "Switching protocols"
+// @Failure      400 {object} helper.Response "Invalid platform"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      429 {object} helper.Response "Too many QR logins"
+// @Router       /auth/qr/ws [get]
+func (q *QRLoginHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	platform := r.Header.Get("X-Platform")
+	if platform == "" {
+		platform = r.URL.Query().Get("platform")
+	}
+	if !domain.IsValidPlatform(platform) {
+		helper.BadRequestResponse(w, "Invalid platform", errInvalidPlatform)
+		return
+	}
+
+	deviceName := r.URL.Query().Get("device_name")
+
+	v := helper.NewValidator()
+	dto.ValidateQRLoginDeviceName(v, deviceName)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	token, result, err := q.qrLoginService.Start(deviceName, deviceInfo(r, platform))
+	if err != nil {
+		if errors.Is(err, repository.ErrQRLoginRateLimited) {
+			helper.RateLimitExceededResponse(w, err.Error())
+			return
+		}
+		helper.InternalServerError(w, "Failed to start QR login", err)
+		return
+	}
+	defer q.qrLoginService.Cancel(token.Token)
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		OriginPatterns: []string{"*"},
+	})
+	if err != nil {
+		q.logger.Warn("failed to accept QR login websocket", "error", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	// Nothing is read, but reading notices the client going away
+	ctx := conn.CloseRead(r.Context())
+
+	if err := q.write(ctx, conn, ws.EventQRLoginToken, token); err != nil {
+		return
+	}
+
+	expired := time.NewTimer(time.Duration(token.ExpiresIn) * time.Second)
+	defer expired.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case login := <-result:
+		if err := q.write(ctx, conn, ws.EventQRLoginSuccess, login); err != nil {
+			q.logger.Warn("failed to deliver QR login", "error", err)
+			return
+		}
+	case <-expired.C:
+		if err := q.write(ctx, conn, ws.EventQRLoginExpired, nil); err != nil {
+			return
+		}
+	}
+
+	conn.Close(websocket.StatusNormalClosure, "")
+}
+
+// Approve godoc
+// @Summary      Approve a QR code login
+// @Description  Log the device showing the scanned QR code into the authenticated user's account. The new session needs no password or two-factor code. Each code works once and only while the device showing it is still waiting.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.QRLoginApproveRequest true "Token from the QR code"
+// @Success      200 {object} helper.Response "Device successfully logged in"
+// @Failure      400 {object} helper.Response "Invalid or expired QR code"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      403 {object} helper.Response "Account suspended"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/qr/approve [post]
+func (q *QRLoginHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.QRLoginApproveRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateQRLoginApproveRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if err := q.qrLoginService.Approve(r.Context(), userId, &payload); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInvalidQRLoginToken):
+			helper.BadRequestResponse(w, err.Error(), err)
+			return
+		case errors.Is(err, repository.ErrAccountDeleted):
+			helper.UnauthorizedResponse(w, "Unauthorized")
+			return
+		case errors.Is(err, repository.ErrAccountSuspended):
+			helper.ForbiddenResponse(w, err.Error())
+			return
+		}
+		helper.InternalServerError(w, "Failed to approve QR login", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Device successfully logged in", nil)
+}
+
+func (q *QRLoginHandler) write(ctx context.Context, conn *websocket.Conn, eventType ws.EventType, payload any) error {
+	data, err := json.Marshal(ws.Event{
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	writeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return conn.Write(writeCtx, websocket.MessageText, data)
+}
+
+func NewQRLoginHandler(qrLoginService service.QRLoginService, logger utils.LoggerStrategy) *QRLoginHandler {
+	return &QRLoginHandler{
+		qrLoginService: qrLoginService,
+		logger:         logger,
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+// Enroll godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generate a TOTP secret and recovery codes for the authenticated user. Add the otpauth URI to an authenticator app and confirm with a code; until then logging in doesn't ask for one. Enrolling again replaces an unconfirmed enrollment.
+// @Tags         Two-Factor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=dto.TwoFactorEnrollResponse} "Two-factor enrollment started"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      409 {object} helper.Response "Two-factor authentication is already enabled"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/2fa/enroll [post]
+func (t *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	enrollment, err := t.twoFactorService.Enroll(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorAlreadyEnabled) {
+			helper.EditConflictResponse(w, err.Error(), err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to start two-factor enrollment", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Two-factor enrollment started", enrollment)
+}
+
+// Confirm godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Enable two-factor authentication with a code from the authenticator app
+// @Tags         Two-Factor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.TwoFactorCodeRequest true "Authenticator code"
+// @Success      200 {object} helper.Response "Two-factor authentication successfully enabled"
+// @Failure      400 {object} helper.Response "Invalid code or no enrollment started"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      409 {object} helper.Response "Two-factor authentication is already enabled"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      429 {object} helper.Response "Too many attempts"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/2fa/confirm [post]
+func (t *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.TwoFactorCodeRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateTwoFactorCodeRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if err := t.twoFactorService.Confirm(r.Context(), userId, &payload); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTwoFactorNotEnrolled), errors.Is(err, repository.ErrInvalidTwoFactorCode):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrTwoFactorAlreadyEnabled):
+			helper.EditConflictResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrTwoFactorRateLimited):
+			helper.RateLimitExceededResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to confirm two-factor authentication", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Two-factor authentication successfully enabled", nil)
+}
+
+// Disable godoc
+// @Summary      Disable two-factor authentication
+// @Description  Turn two-factor authentication off, removing the secret and recovery codes. Requires the current password and an authenticator or recovery code.
+// @Tags         Two-Factor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.DisableTwoFactorRequest true "Current password and code"
+// @Success      200 {object} helper.Response "Two-factor authentication successfully disabled"
+// @Failure      400 {object} helper.Response "Incorrect password, invalid code or two-factor authentication not enabled"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      429 {object} helper.Response "Too many attempts"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /auth/2fa/disable [post]
+func (t *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.DisableTwoFactorRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateDisableTwoFactorRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	if err := t.twoFactorService.Disable(r.Context(), userId, &payload); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrIncorrectPassword), errors.Is(err, repository.ErrInvalidTwoFactorCode), errors.Is(err, repository.ErrTwoFactorNotEnabled):
+			helper.BadRequestResponse(w, err.Error(), err)
+		case errors.Is(err, repository.ErrTwoFactorRateLimited):
+			helper.RateLimitExceededResponse(w, err.Error())
+		default:
+			helper.InternalServerError(w, "Failed to disable two-factor authentication", err)
+		}
+		return
+	}
+
+	helper.SuccessResponse(w, "Two-factor authentication successfully disabled", nil)
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
@@ -1,17 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/filestore"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
-	"io"
 	"net/http"
-	"net/url"
-	"os"
-	"path/filepath"
 )
 
-type UploadFileHandler struct{}
+type UploadFileHandler struct {
+	fileStore filestore.FileStore
+}
 
 // UploadFile godoc
 // @Summary      Upload a file
@@ -20,7 +20,7 @@ type UploadFileHandler struct{}
 // @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "Chat conversation ID (private conversation ID)"
 // @Param        file formData file true "File to upload (max 50MB)"
 // @Success      200 {object} helper.Response{data=string} "File URL returned in data field"
@@ -66,34 +66,18 @@ func (u *UploadFileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dirPath := filepath.Join("files", "chats", fmt.Sprintf("%d", id), fmt.Sprintf("%d", userId))
-
-	if err := os.MkdirAll(dirPath, os.ModePerm); err != nil {
-		helper.InternalServerError(w, "Failed to create dir", err)
-		return
-	}
+	key := fmt.Sprintf("chats/%d/%d/%s", id, userId, header.Filename)
 
-	filePath := filepath.Join(dirPath, header.Filename)
-	dst, err := os.Create(filePath)
+	fileUrl, err := u.fileStore.Save(key, file)
 	if err != nil {
-		helper.InternalServerError(w, "Failed to create file", err)
-		return
-	}
-
-	defer func() {
-		if err := dst.Close(); err != nil {
-			helper.InternalServerError(w, "Failed to close file", err)
+		if errors.Is(err, filestore.ErrInvalidKey) {
+			helper.BadRequestResponse(w, "Invalid file name", err)
 			return
 		}
-	}()
-
-	if _, err := io.Copy(dst, file); err != nil {
-		helper.InternalServerError(w, "Failed to copy file", err)
+		helper.InternalServerError(w, "Failed to store file", err)
 		return
 	}
 
-	fileUrl := fmt.Sprintf("/v1/files/chats/%d/%d/%s", id, userId, url.PathEscape(header.Filename))
-
 	helper.SuccessResponse(w, "File successfully uploaded", fileUrl)
 }
 
@@ -103,7 +87,7 @@ func (u *UploadFileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 // @Tags         Files
 // @Produce      application/octet-stream
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Success      200 {file} binary "File content"
 // @Failure      400 {object} helper.Response "Invalid parameters"
 // @Failure      401 {object} helper.Response "Unauthorized"
@@ -115,6 +99,8 @@ func (u *UploadFileHandler) GetFile() http.Handler {
 	return http.StripPrefix("/v1/files", fs)
 }
 
-func NewUploadFileHandler() *UploadFileHandler {
-	return &UploadFileHandler{}
+func NewUploadFileHandler(fileStore filestore.FileStore) *UploadFileHandler {
+	return &UploadFileHandler{
+		fileStore: fileStore,
+	}
 }
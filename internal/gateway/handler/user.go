@@ -1,23 +1,33 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"log"
 	"net/http"
+	"strings"
 )
 
 type UserHandler struct {
-	userService service.UserService
+	userService    service.UserService
+	privateService service.PrivateService
+	hub            *ws.Hub
 }
 
 // GetUserById godoc
 // @Summary      Get user by ID
-// @Description  Retrieve a user's information by their ID. User must be authenticated.
+// @Description  Retrieve a user's information by their ID. User must be authenticated. The avatar is left out and last_seen_at is replaced by a coarse last_seen (recently, within_week, within_month, long_ago) when the user's privacy settings hide them.
 // @Tags         Users
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        X-Platform header string true "Platform type (web or mobile)" Enums(web, mobile)
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
 // @Param        id path int true "User ID"
 // @Success      200 {object} helper.Response{data=dto.UserResponse} "User successfully retrieved"
 // @Failure      400 {object} helper.Response "Invalid ID format"
@@ -26,13 +36,19 @@ type UserHandler struct {
 // @Failure      500 {object} helper.Response "Internal server error"
 // @Router       /users/{id} [get]
 func (u *UserHandler) GetUserById(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
 	id, err := helper.ReadParams(r)
 	if err != nil {
 		helper.BadRequestResponse(w, "invalid id format", err)
 		return
 	}
 
-	user, err := u.userService.GetUserById(r.Context(), id)
+	user, err := u.userService.GetUserById(r.Context(), id, userId)
 	if err != nil {
 		helper.NotFoundResponse(w, "User not found")
 		return
@@ -41,8 +57,319 @@ func (u *UserHandler) GetUserById(w http.ResponseWriter, r *http.Request) {
 	helper.SuccessResponse(w, "User successfully retrieved", user)
 }
 
-func NewUserHandler(userService service.UserService) *UserHandler {
+// UpdateProfile godoc
+// @Summary      Update my profile
+// @Description  Update the authenticated user's name and/or bio. Users sharing a conversation are notified with a profile_updated WebSocket event.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.UpdateProfileRequest true "Fields to change"
+// @Success      200 {object} helper.Response{data=dto.UserResponse} "Profile successfully updated"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me [patch]
+func (u *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.UpdateProfileRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUpdateProfileRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	user, err := u.userService.UpdateProfile(r.Context(), userId, &payload)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to update profile", err)
+		return
+	}
+
+	u.broadcastProfile(r.Context(), user)
+
+	helper.SuccessResponse(w, "Profile successfully updated", user)
+}
+
+// UploadAvatar godoc
+// @Summary      Upload my avatar
+// @Description  Replace the authenticated user's avatar. The image is cropped to a square and stored resized to at most 512x512. Users sharing a conversation are notified with a profile_updated WebSocket event.
+// @Tags         Users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        avatar formData file true "JPEG, PNG or GIF image (max 10MB)"
+// @Success      200 {object} helper.Response{data=dto.UserResponse} "Avatar successfully updated"
+// @Failure      400 {object} helper.Response "Missing, too large or invalid image"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/avatar [post]
+func (u *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 10<<20)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		helper.BadRequestResponse(w, "File too large or invalid form data", err)
+		return
+	}
+
+	file, _, err := r.FormFile("avatar")
+	if err != nil {
+		helper.BadRequestResponse(w, "avatar file must be provided", err)
+		return
+	}
+	defer file.Close()
+
+	user, err := u.userService.UpdateAvatar(r.Context(), userId, file)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidImage) {
+			helper.BadRequestResponse(w, err.Error(), err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to update avatar", err)
+		return
+	}
+
+	u.broadcastProfile(r.Context(), user)
+
+	helper.SuccessResponse(w, "Avatar successfully updated", user)
+}
+
+// DeleteAvatar godoc
+// @Summary      Remove my avatar
+// @Description  Remove the authenticated user's avatar
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=dto.UserResponse} "Avatar successfully removed"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/avatar [delete]
+func (u *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	user, err := u.userService.DeleteAvatar(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to remove avatar", err)
+		return
+	}
+
+	u.broadcastProfile(r.Context(), user)
+
+	helper.SuccessResponse(w, "Avatar successfully removed", user)
+}
+
+// SetUsername godoc
+// @Summary      Set my username
+// @Description  Set, change or (with an empty username) remove the authenticated user's username. Usernames are unique regardless of case. Users sharing a conversation are notified with a profile_updated WebSocket event.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.UsernameRequest true "New username"
+// @Success      200 {object} helper.Response{data=dto.UserResponse} "Username successfully updated"
+// @Failure      400 {object} helper.Response "Invalid request data"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      409 {object} helper.Response "Username is already taken"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/username [put]
+func (u *UserHandler) SetUsername(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.UsernameRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateUsernameRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	user, err := u.userService.SetUsername(r.Context(), userId, &payload)
+	if err != nil {
+		if errors.Is(err, repository.ErrUsernameExists) {
+			helper.EditConflictResponse(w, "Username is already taken", err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to update username", err)
+		return
+	}
+
+	u.broadcastProfile(r.Context(), user)
+
+	helper.SuccessResponse(w, "Username successfully updated", user)
+}
+
+// GetUsernameHistory godoc
+// @Summary      Get my username history
+// @Description  List the authenticated user's username changes, newest first
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.UsernameChangeResponse} "Username history successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/me/username/history [get]
+func (u *UserHandler) GetUsernameHistory(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	changes, err := u.userService.GetUsernameHistory(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get username history", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Username history successfully retrieved", changes)
+}
+
+// GetUserByUsername godoc
+// @Summary      Get user by username
+// @Description  Look up a user by username, ignoring case and a leading @
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        username path string true "Username"
+// @Success      200 {object} helper.Response{data=dto.PublicUserResponse} "User successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "User not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/by-username/{username} [get]
+func (u *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	user, err := u.userService.GetUserByUsername(r.Context(), r.PathValue("username"), userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "User not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to get user", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "User successfully retrieved", user)
+}
+
+// SearchUsers godoc
+// @Summary      Search users
+// @Description  Find users by username prefix or similar usernames. Only users who set a username can be found.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        q query string true "Username or part of it, a leading @ is ignored"
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(20)
+// @Success      200 {object} helper.PaginatedResponse{Response=helper.Response{data=[]dto.PublicUserResponse}} "Users successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /users/search [get]
+func (u *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	query := strings.TrimPrefix(r.URL.Query().Get("q"), "@")
+
+	v := helper.NewValidator()
+	dto.ValidateUserSearchQuery(v, query)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	page, limit := helper.ParsePagination(r)
+
+	users, total, err := u.userService.SearchUsers(r.Context(), query, userId, page, limit)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to search users", err)
+		return
+	}
+
+	helper.PaginatedSuccessResponse(w, "Users successfully retrieved", users, helper.PaginatedMeta{
+		Page:      int64(page),
+		Limit:     int64(limit),
+		Total:     total,
+		TotalPage: (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
+// broadcastProfile sends the new profile to everyone sharing a conversation
+// with the user, and to the user's own other connections.
+func (u *UserHandler) broadcastProfile(ctx context.Context, user *dto.UserResponse) {
+	privates, err := u.privateService.GetPrivatesForUser(ctx, user.Id)
+	if err != nil {
+		log.Printf("Failed to get privates for profile update: %v", err)
+		return
+	}
+
+	userIds := []uint{user.Id}
+	for _, private := range privates {
+		if private.User1Id == user.Id {
+			userIds = append(userIds, private.User2Id)
+		} else {
+			userIds = append(userIds, private.User1Id)
+		}
+	}
+
+	u.hub.SendEventToUserIds(userIds, user.Id, ws.EventProfileUpdated, map[string]any{
+		"user": user,
+	})
+}
+
+func NewUserHandler(userService service.UserService, privateService service.PrivateService, hub *ws.Hub) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:    userService,
+		privateService: privateService,
+		hub:            hub,
 	}
 }
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// CreateWebhook godoc
+// @Summary      Register a webhook
+// @Description  Register an endpoint that receives events of the authenticated user's conversations, optionally limited to one private conversation. Deliveries are signed with HMAC-SHA256 over "<X-Telegopher-Timestamp>.<body>" using the returned secret, which is only shown once.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        request body dto.WebhookRequest true "Endpoint URL, events (message.created, message.delivered, message.read, message.edited) and optional private conversation"
+// @Success      201 {object} helper.Response{data=dto.WebhookResponse} "Webhook successfully created"
+// @Failure      400 {object} helper.Response "Invalid request data or URL not publicly reachable"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Private conversation not found"
+// @Failure      422 {object} helper.Response "Validation failed"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /webhooks [post]
+func (wh *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	var payload dto.WebhookRequest
+	if err := helper.ReadJSON(w, r, &payload); err != nil {
+		helper.BadRequestResponse(w, "Invalid given payload", err)
+		return
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateWebhookRequest(v, &payload)
+	if !v.Valid() {
+		helper.FailedValidationResponse(w, "input's not valid")
+		return
+	}
+
+	webhook, err := wh.webhookService.CreateWebhook(r.Context(), &payload, userId)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			helper.NotFoundResponse(w, "Private conversation not found")
+			return
+		case errors.Is(err, repository.ErrWebhookURLNotAllowed):
+			helper.BadRequestResponse(w, err.Error(), err)
+			return
+		}
+		helper.InternalServerError(w, "Failed to create webhook", err)
+		return
+	}
+
+	helper.CreatedResponse(w, "Webhook successfully created", webhook)
+}
+
+// GetWebhooks godoc
+// @Summary      List my webhooks
+// @Description  List the webhooks registered by the authenticated user
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Success      200 {object} helper.Response{data=[]dto.WebhookResponse} "Webhooks successfully retrieved"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /webhooks [get]
+func (wh *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	webhooks, err := wh.webhookService.GetWebhooks(r.Context(), userId)
+	if err != nil {
+		helper.InternalServerError(w, "Failed to get webhooks", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Webhooks successfully retrieved", webhooks)
+}
+
+// EnableWebhook godoc
+// @Summary      Re-enable a webhook
+// @Description  Switch a webhook that was disabled after repeated failed deliveries back on
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Webhook ID"
+// @Success      200 {object} helper.Response{data=dto.WebhookResponse} "Webhook successfully enabled"
+// @Failure      400 {object} helper.Response "Invalid webhook ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Webhook not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /webhooks/{id}/enable [post]
+func (wh *WebhookHandler) EnableWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid webhook ID", err)
+		return
+	}
+
+	webhook, err := wh.webhookService.EnableWebhook(r.Context(), id, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Webhook not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to enable webhook", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Webhook successfully enabled", webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Delete one of the authenticated user's webhooks together with its delivery log
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Webhook ID"
+// @Success      200 {object} helper.Response "Webhook successfully deleted"
+// @Failure      400 {object} helper.Response "Invalid webhook ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Webhook not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /webhooks/{id} [delete]
+func (wh *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid webhook ID", err)
+		return
+	}
+
+	if err := wh.webhookService.DeleteWebhook(r.Context(), id, userId); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Webhook not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to delete webhook", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Webhook successfully deleted", nil)
+}
+
+// GetDeliveries godoc
+// @Summary      Get webhook delivery log
+// @Description  Get the delivery attempts of a webhook, newest first, with the status code each attempt received
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        X-Platform header string true "Platform type" Enums(web, mobile, desktop, tablet, api)
+// @Param        id path int true "Webhook ID"
+// @Param        page query int false "Page number" default(1)
+// @Param        limit query int false "Items per page" default(20) maximum(100)
+// @Success      200 {object} helper.Response{data=dto.WebhookDeliveryListResponse} "Webhook deliveries successfully retrieved"
+// @Failure      400 {object} helper.Response "Invalid webhook ID"
+// @Failure      401 {object} helper.Response "Unauthorized"
+// @Failure      404 {object} helper.Response "Webhook not found"
+// @Failure      500 {object} helper.Response "Internal server error"
+// @Router       /webhooks/{id}/deliveries [get]
+func (wh *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	userId, ok := utils.UserIdFromContext(r.Context())
+	if !ok {
+		helper.UnauthorizedResponse(w, "Unauthorized")
+		return
+	}
+
+	id, err := helper.ReadParams(r)
+	if err != nil {
+		helper.BadRequestResponse(w, "Invalid webhook ID", err)
+		return
+	}
+
+	page, limit := helper.ParsePagination(r)
+
+	deliveries, err := wh.webhookService.GetDeliveries(r.Context(), id, userId, page, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			helper.NotFoundResponse(w, "Webhook not found")
+			return
+		}
+		helper.InternalServerError(w, "Failed to get webhook deliveries", err)
+		return
+	}
+
+	helper.SuccessResponse(w, "Webhook deliveries successfully retrieved", deliveries)
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
@@ -3,27 +3,35 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/coder/websocket"
 	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/revocation"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/ws"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 )
 
 type WebSocketHandler struct {
-	userService    service.UserService
-	messageService service.MessageService
-	logger         utils.LoggerStrategy
-	hub            *ws.Hub
-	cfg            *config.Config
+	userService     service.UserService
+	messageService  service.MessageService
+	botService      service.BotService
+	blockService    service.BlockService
+	revocationStore revocation.Store
+	tokenKeys       utils.TokenKeys
+	logger          utils.LoggerStrategy
+	hub             *ws.Hub
+	cfg             *config.Config
 }
 
 func (wsh *WebSocketHandler) HandleWebsocket(w http.ResponseWriter, r *http.Request) {
@@ -40,18 +48,43 @@ func (wsh *WebSocketHandler) HandleWebsocket(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	claims, err := utils.ValidateToken(tokenParts[1], wsh.cfg.JWT.Secret)
+	claims, err := utils.ValidateToken(tokenParts[1], wsh.tokenKeys)
 	if err != nil {
 		helper.UnauthorizedResponse(w, "Unauthorized")
 		return
 	}
 
-	user, err := wsh.userService.GetUserById(r.Context(), claims.UserId)
+	if wsh.revocationStore.IsRevoked(claims.ID, claims.SessionId) {
+		helper.UnauthorizedResponse(w, "Token has been revoked")
+		return
+	}
+
+	if err := wsh.userService.EnsureActive(r.Context(), claims.UserId); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAccountSuspended):
+			helper.ForbiddenResponse(w, err.Error())
+		case errors.Is(err, repository.ErrAccountDeleted), errors.Is(err, repository.ErrRecordNotFound):
+			helper.UnauthorizedResponse(w, "Unauthorized")
+		default:
+			helper.InternalServerError(w, "failed to get user", err)
+		}
+		return
+	}
+
+	user, err := wsh.userService.GetUserById(r.Context(), claims.UserId, claims.UserId)
 	if err != nil {
 		helper.UnauthorizedResponse(w, "Unauthorized")
 		return
 	}
 
+	var username, avatarURL *string
+	if user.Username != "" {
+		username = &user.Username
+	}
+	if user.AvatarURL != "" {
+		avatarURL = &user.AvatarURL
+	}
+
 	opts := &websocket.AcceptOptions{
 		OriginPatterns: []string{"*"},
 	}
@@ -63,28 +96,35 @@ func (wsh *WebSocketHandler) HandleWebsocket(w http.ResponseWriter, r *http.Requ
 	}
 
 	client := ws.NewClient(&domain.User{
-		Id:    user.Id,
-		Name:  user.Name,
-		Email: user.Email,
-	}, conn)
+		Id:        user.Id,
+		Name:      user.Name,
+		Username:  username,
+		Email:     user.Email,
+		Bio:       user.Bio,
+		AvatarURL: avatarURL,
+	}, claims.SessionId, conn)
+	client.EmailVerified = user.EmailVerified
 
 	wsh.hub.RegisterClient(client)
 	wsh.hub.SendCurrentClients(client)
 
 	defer func() {
 		wsh.hub.UnregisterClient(client)
-		client.Close()
+		client.CloseSend()
+		_ = conn.Close(websocket.StatusNormalClosure, "Closing connection")
 	}()
 
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
-	go wsh.heartbeat(ctx, client)
+	go wsh.heartbeat(ctx, client, claims)
 	go wsh.writePump(ctx, client)
 	wsh.readPump(ctx, cancel, client)
 }
 
-func (wsh *WebSocketHandler) heartbeat(ctx context.Context, client *ws.Client) {
+// heartbeat also drops the connection once its token is revoked. Revocations
+// through the API close it right away, this catches the rest.
+func (wsh *WebSocketHandler) heartbeat(ctx context.Context, client *ws.Client, claims *utils.Claims) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
@@ -93,6 +133,11 @@ func (wsh *WebSocketHandler) heartbeat(ctx context.Context, client *ws.Client) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if wsh.revocationStore.IsRevoked(claims.ID, claims.SessionId) {
+				client.Close()
+				return
+			}
+
 			pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 			err := client.Conn.Ping(pingCtx)
 			if err != nil {
@@ -117,16 +162,21 @@ func (wsh *WebSocketHandler) writePump(ctx context.Context, client *ws.Client) {
 		case <-ctx.Done():
 			return
 
+		case <-client.Done():
+			// Flush what was queued before the client was told to go away,
+			// such as the reason for closing it
+			wsh.flush(ctx, client)
+			if err := client.Conn.Close(websocket.StatusNormalClosure, "Closing connection"); err != nil {
+				wsh.logger.Warn("failed to close connection", "client", client.User.Id, "err", err)
+			}
+			return
+
 		case event, ok := <-client.Send:
 			if !ok {
 				return
 			}
 
-			writeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-			err := client.Conn.Write(writeCtx, websocket.MessageText, wsh.eventToJSON(event))
-			cancel()
-
-			if err != nil {
+			if err := wsh.writeEvent(ctx, client, event); err != nil {
 				wsh.logger.Error("failed to write to client", "client", client.User.Id, "err", err)
 				return
 			}
@@ -134,6 +184,26 @@ func (wsh *WebSocketHandler) writePump(ctx context.Context, client *ws.Client) {
 	}
 }
 
+func (wsh *WebSocketHandler) flush(ctx context.Context, client *ws.Client) {
+	for {
+		select {
+		case event, ok := <-client.Send:
+			if !ok || wsh.writeEvent(ctx, client, event) != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (wsh *WebSocketHandler) writeEvent(ctx context.Context, client *ws.Client, event ws.Event) error {
+	writeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return client.Conn.Write(writeCtx, websocket.MessageText, wsh.eventToJSON(event))
+}
+
 func (wsh *WebSocketHandler) readPump(ctx context.Context, cancel context.CancelFunc, client *ws.Client) {
 	defer cancel()
 	defer func() {
@@ -180,12 +250,19 @@ func (wsh *WebSocketHandler) handleIncomingEvent(client *ws.Client, event ws.Eve
 		wsh.handleReadEvent(client, payload)
 	case ws.EventTyping:
 		wsh.handleTypingEvent(client, payload)
+	case ws.EventCallbackQuery:
+		wsh.handleCallbackQueryEvent(client, payload)
 	default:
 		wsh.hub.SendError(client.User.Id, "unknown event type: "+string(event.EventType))
 	}
 }
 
 func (wsh *WebSocketHandler) handleMessageEvent(client *ws.Client, payload map[string]any) {
+	if !client.EmailVerified && !slices.Contains(wsh.cfg.Verification.UnverifiedCapabilities, string(domain.CapabilityMessaging)) {
+		wsh.hub.SendError(client.User.Id, "email address must be verified first")
+		return
+	}
+
 	privateId, ok := wsh.extractUint(payload, "private_id")
 	if !ok {
 		wsh.hub.SendError(client.User.Id, "private_id is required and must be a number")
@@ -302,6 +379,12 @@ func (wsh *WebSocketHandler) handleTypingEvent(client *ws.Client, payload map[st
 		return
 	}
 
+	// Typing from or to a blocked user is dropped without telling the sender
+	blocked, err := wsh.blockService.IsBlocked(context.Background(), client.User.Id, receiverId)
+	if err != nil || blocked {
+		return
+	}
+
 	wsh.hub.SendEventToUserIds([]uint{receiverId}, client.User.Id, ws.EventTyping, map[string]any{
 		"private_id": privateId,
 		"user_id":    client.User.Id,
@@ -309,6 +392,39 @@ func (wsh *WebSocketHandler) handleTypingEvent(client *ws.Client, payload map[st
 	})
 }
 
+func (wsh *WebSocketHandler) handleCallbackQueryEvent(client *ws.Client, payload map[string]any) {
+	messageId, ok := wsh.extractUint(payload, "message_id")
+	if !ok {
+		wsh.hub.SendError(client.User.Id, "message_id is required and must be a number")
+		return
+	}
+
+	data, ok := payload["data"].(string)
+	if !ok {
+		wsh.hub.SendError(client.User.Id, "data is required")
+		return
+	}
+
+	req := &dto.CallbackQueryRequest{
+		MessageId: messageId,
+		Data:      data,
+	}
+
+	v := helper.NewValidator()
+	dto.ValidateCallbackQueryRequest(v, req)
+	if !v.Valid() {
+		wsh.hub.SendError(client.User.Id, "invalid callback query")
+		return
+	}
+
+	// The bot answers asynchronously with answerCallbackQuery, which reaches
+	// the client as a callback_answer event.
+	if err := wsh.botService.CallbackQuery(context.Background(), client.User.Id, req); err != nil {
+		wsh.hub.SendError(client.User.Id, fmt.Sprintf("failed to send callback query: %v", err))
+		return
+	}
+}
+
 func (wsh *WebSocketHandler) extractUint(payload map[string]any, key string) (uint, bool) {
 	value, ok := payload[key]
 	if !ok {
@@ -336,12 +452,16 @@ func (wsh *WebSocketHandler) eventToJSON(event ws.Event) []byte {
 	return jsonData
 }
 
-func NewWebSocketHandler(userService service.UserService, messageService service.MessageService, logger utils.LoggerStrategy, hub *ws.Hub, cfg *config.Config) *WebSocketHandler {
+func NewWebSocketHandler(userService service.UserService, messageService service.MessageService, botService service.BotService, blockService service.BlockService, revocationStore revocation.Store, tokenKeys utils.TokenKeys, logger utils.LoggerStrategy, hub *ws.Hub, cfg *config.Config) *WebSocketHandler {
 	return &WebSocketHandler{
-		userService:    userService,
-		messageService: messageService,
-		logger:         logger,
-		hub:            hub,
-		cfg:            cfg,
+		userService:     userService,
+		messageService:  messageService,
+		botService:      botService,
+		blockService:    blockService,
+		revocationStore: revocationStore,
+		tokenKeys:       tokenKeys,
+		logger:          logger,
+		hub:             hub,
+		cfg:             cfg,
 	}
 }
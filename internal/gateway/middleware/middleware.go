@@ -4,24 +4,53 @@ import (
 	"errors"
 	"fmt"
 	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/revocation"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/helper"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
 	"net/http"
+	"slices"
 	"strings"
 )
 
 type Middleware struct {
-	logger utils.LoggerStrategy
-	cfg    *config.Config
+	logger                     utils.LoggerStrategy
+	revocationStore            revocation.Store
+	tokenKeys                  utils.TokenKeys
+	personalAccessTokenService service.PersonalAccessTokenService
+	adminService               service.AdminService
+	cfg                        *config.Config
 }
 
 func (m *Middleware) Logging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		m.logger.Info("Incoming request: ", "method", r.Method, "path", r.URL.Path, "protocol", r.Proto, "remote_addr", r.RemoteAddr)
+		m.logger.Info("Incoming request: ", "method", r.Method, "path", m.redactPath(r.URL.Path), "protocol", r.Proto, "remote_addr", r.RemoteAddr)
 		next.ServeHTTP(w, r)
 	})
 }
 
+// redactPath hides the secret part of Bot API tokens and incoming webhook URLs,
+// which travel in the path.
+func (m *Middleware) redactPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "/v1/hooks/"); ok {
+		id, _, _ := strings.Cut(rest, "/")
+		return "/v1/hooks/" + id + "/<redacted>"
+	}
+
+	rest, ok := strings.CutPrefix(path, "/bot")
+	if !ok {
+		return path
+	}
+
+	token, method, _ := strings.Cut(rest, "/")
+	if botId, _, ok := strings.Cut(token, ":"); ok {
+		return "/bot" + botId + ":<redacted>/" + method
+	}
+	return path
+}
+
 func (m *Middleware) CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -62,13 +91,18 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
+		if strings.HasPrefix(tokenParts[1], domain.PersonalAccessTokenPrefix) {
+			m.authenticatePersonalAccessToken(w, r, tokenParts[1], next)
+			return
+		}
+
 		platform := r.Header.Get("X-Platform")
-		if platform != "web" && platform != "mobile" {
+		if !domain.IsValidPlatform(platform) {
 			helper.BadRequestResponse(w, "invalid platform", errors.New("invalid platform"))
 			return
 		}
 
-		claims, err := utils.ValidateToken(tokenParts[1], m.cfg.JWT.Secret)
+		claims, err := utils.ValidateToken(tokenParts[1], m.tokenKeys)
 		if err != nil {
 			helper.UnauthorizedResponse(w, "Unauthorized")
 			return
@@ -76,10 +110,19 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 
 		if claims.Platform != platform {
 			helper.UnauthorizedResponse(w, "Unauthorized")
+			return
+		}
+
+		if m.revocationStore.IsRevoked(claims.ID, claims.SessionId) {
+			helper.UnauthorizedResponse(w, "Token has been revoked")
+			return
 		}
 
 		ctx := r.Context()
 		ctx = utils.WithUserId(ctx, claims.UserId)
+		ctx = utils.WithSessionId(ctx, claims.SessionId)
+		ctx = utils.WithTokenId(ctx, claims.ID)
+		ctx = utils.WithEmailVerified(ctx, claims.EmailVerified)
 		ctx = utils.WithName(ctx, claims.Name)
 		ctx = utils.WithPlatform(ctx, claims.Platform)
 		r = r.WithContext(ctx)
@@ -88,13 +131,134 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 	})
 }
 
+// authenticatePersonalAccessToken needs no X-Platform header, requests with
+// a personal access token always come from an API client.
+func (m *Middleware) authenticatePersonalAccessToken(w http.ResponseWriter, r *http.Request, token string, next http.Handler) {
+	personalAccessToken, err := m.personalAccessTokenService.Authenticate(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPersonalAccessToken) {
+			helper.UnauthorizedResponse(w, "Unauthorized")
+			return
+		}
+		helper.InternalServerError(w, "failed to authenticate", err)
+		return
+	}
+
+	scopes := make([]string, len(personalAccessToken.Scopes))
+	for i, scope := range personalAccessToken.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	ctx := r.Context()
+	ctx = utils.WithUserId(ctx, personalAccessToken.UserId)
+	ctx = utils.WithEmailVerified(ctx, personalAccessToken.User.IsEmailVerified())
+	ctx = utils.WithName(ctx, personalAccessToken.User.Name)
+	ctx = utils.WithPlatform(ctx, string(domain.PlatformAPIClient))
+	ctx = utils.WithTokenScopes(ctx, scopes)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireScope only lets personal access tokens through that were granted
+// scope. Sessions have every scope.
+func (m *Middleware) RequireScope(scope domain.TokenScope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if scopes, ok := utils.TokenScopesFromContext(r.Context()); ok && !slices.Contains(scopes, string(scope)) {
+			helper.ForbiddenResponse(w, fmt.Sprintf("Personal access token lacks the %s scope", scope))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireSession turns personal access tokens away, for routes no scope
+// covers.
+func (m *Middleware) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := utils.TokenScopesFromContext(r.Context()); ok {
+			helper.ForbiddenResponse(w, "Personal access tokens can't be used here")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole only lets users through whose role is role or a more
+// privileged one. The role is looked up on every request, so a demotion takes
+// effect right away.
+func (m *Middleware) RequireRole(role domain.Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := utils.UserIdFromContext(r.Context())
+		if !ok {
+			helper.UnauthorizedResponse(w, "Unauthorized")
+			return
+		}
+
+		userRole, err := m.adminService.GetRole(r.Context(), userId)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				helper.UnauthorizedResponse(w, "Unauthorized")
+				return
+			}
+			helper.InternalServerError(w, "failed to check role", err)
+			return
+		}
+
+		if !userRole.AtLeast(role) {
+			helper.ForbiddenResponse(w, fmt.Sprintf("The %s role is required", role))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireVerified only lets users with an unverified email through when the
+// configuration grants unverified accounts capability.
+func (m *Middleware) RequireVerified(capability domain.Capability, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !utils.EmailVerifiedFromContext(r.Context()) && !slices.Contains(m.cfg.Verification.UnverifiedCapabilities, string(capability)) {
+			helper.ForbiddenResponse(w, "Email address must be verified first")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (m *Middleware) WrapAuth(handlerFunc http.HandlerFunc) http.Handler {
-	return m.Authenticate(handlerFunc)
+	return m.Authenticate(m.RequireSession(handlerFunc))
+}
+
+func (m *Middleware) WrapRole(role domain.Role, handlerFunc http.HandlerFunc) http.Handler {
+	return m.Authenticate(m.RequireSession(m.RequireRole(role, handlerFunc)))
+}
+
+func (m *Middleware) WrapAdmin(handlerFunc http.HandlerFunc) http.Handler {
+	return m.WrapRole(domain.RoleAdmin, handlerFunc)
+}
+
+func (m *Middleware) WrapVerified(capability domain.Capability, handlerFunc http.HandlerFunc) http.Handler {
+	return m.Authenticate(m.RequireSession(m.RequireVerified(capability, handlerFunc)))
+}
+
+// WrapScoped also accepts personal access tokens with scope.
+func (m *Middleware) WrapScoped(scope domain.TokenScope, handlerFunc http.HandlerFunc) http.Handler {
+	return m.Authenticate(m.RequireScope(scope, handlerFunc))
+}
+
+func (m *Middleware) WrapVerifiedScoped(capability domain.Capability, scope domain.TokenScope, handlerFunc http.HandlerFunc) http.Handler {
+	return m.Authenticate(m.RequireScope(scope, m.RequireVerified(capability, handlerFunc)))
 }
 
-func NewMiddleware(logger utils.LoggerStrategy, cfg *config.Config) *Middleware {
+func NewMiddleware(logger utils.LoggerStrategy, revocationStore revocation.Store, tokenKeys utils.TokenKeys, personalAccessTokenService service.PersonalAccessTokenService, adminService service.AdminService, cfg *config.Config) *Middleware {
 	return &Middleware{
-		logger: logger,
-		cfg:    cfg,
+		logger:                     logger,
+		revocationStore:            revocationStore,
+		tokenKeys:                  tokenKeys,
+		personalAccessTokenService: personalAccessTokenService,
+		adminService:               adminService,
+		cfg:                        cfg,
 	}
 }
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type AccountDeletionRoute struct {
+	middleware             *middleware.Middleware
+	accountDeletionHandler *handler.AccountDeletionHandler
+}
+
+func (a *AccountDeletionRoute) AccountDeletionRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/users/me/deletion", a.middleware.WrapAuth(a.accountDeletionHandler.ScheduleDeletion))
+	mux.Handle("DELETE /v1/users/me/deletion", a.middleware.WrapAuth(a.accountDeletionHandler.CancelDeletion))
+}
+
+func NewAccountDeletionRoute(middleware *middleware.Middleware, accountDeletionHandler *handler.AccountDeletionHandler) *AccountDeletionRoute {
+	return &AccountDeletionRoute{
+		middleware:             middleware,
+		accountDeletionHandler: accountDeletionHandler,
+	}
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type AdminRoute struct {
+	middleware   *middleware.Middleware
+	adminHandler *handler.AdminHandler
+}
+
+func (a *AdminRoute) AdminRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /v1/admin/users", a.middleware.WrapRole(domain.RoleModerator, a.adminHandler.GetUsers))
+	mux.Handle("GET /v1/admin/users/{id}", a.middleware.WrapRole(domain.RoleModerator, a.adminHandler.GetUser))
+	mux.Handle("PUT /v1/admin/users/{id}/role", a.middleware.WrapAdmin(a.adminHandler.SetRole))
+	mux.Handle("POST /v1/admin/users/{id}/suspension", a.middleware.WrapRole(domain.RoleModerator, a.adminHandler.SuspendUser))
+	mux.Handle("DELETE /v1/admin/users/{id}/suspension", a.middleware.WrapRole(domain.RoleModerator, a.adminHandler.UnsuspendUser))
+	mux.Handle("POST /v1/admin/users/{id}/logout", a.middleware.WrapRole(domain.RoleModerator, a.adminHandler.ForceLogout))
+	mux.Handle("GET /v1/admin/stats", a.middleware.WrapAdmin(a.adminHandler.GetStats))
+}
+
+func NewAdminRoute(middleware *middleware.Middleware, adminHandler *handler.AdminHandler) *AdminRoute {
+	return &AdminRoute{
+		middleware:   middleware,
+		adminHandler: adminHandler,
+	}
+}
@@ -15,11 +15,20 @@ func (a *AuthRoute) AuthRoutes(mux *http.ServeMux) {
 	/*----------Public Routes----------*/
 	mux.HandleFunc("POST /v1/auth/signup", a.authHandler.Signup)
 	mux.HandleFunc("POST /v1/auth/login", a.authHandler.Login)
+	mux.HandleFunc("POST /v1/auth/login/2fa", a.authHandler.LoginTwoFactor)
 	mux.HandleFunc("POST /v1/auth/refresh-token", a.authHandler.RefreshToken)
+	mux.HandleFunc("POST /v1/auth/verify-email", a.authHandler.VerifyEmail)
+	mux.HandleFunc("POST /v1/auth/forgot-password", a.authHandler.ForgotPassword)
+	mux.HandleFunc("POST /v1/auth/reset-password", a.authHandler.ResetPassword)
 
 	/*----------Private Routes----------*/
 	mux.Handle("POST /v1/auth/logout", a.middleware.WrapAuth(a.authHandler.Logout))
 	mux.Handle("GET /v1/auth/me", a.middleware.WrapAuth(a.authHandler.Me))
+	mux.Handle("POST /v1/auth/change-password", a.middleware.WrapAuth(a.authHandler.ChangePassword))
+	mux.Handle("POST /v1/auth/verify-email/resend", a.middleware.WrapAuth(a.authHandler.ResendVerification))
+	mux.Handle("GET /v1/auth/sessions", a.middleware.WrapAuth(a.authHandler.GetSessions))
+	mux.Handle("DELETE /v1/auth/sessions", a.middleware.WrapAuth(a.authHandler.RevokeOtherSessions))
+	mux.Handle("DELETE /v1/auth/sessions/{id}", a.middleware.WrapAuth(a.authHandler.RevokeSession))
 }
 
 func NewAuthRoute(middleware *middleware.Middleware, authHandler *handler.AuthHandler) *AuthRoute {
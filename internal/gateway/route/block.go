@@ -0,0 +1,25 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type BlockRoute struct {
+	middleware   *middleware.Middleware
+	blockHandler *handler.BlockHandler
+}
+
+func (b *BlockRoute) BlockRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/blocks", b.middleware.WrapAuth(b.blockHandler.BlockUser))
+	mux.Handle("GET /v1/blocks", b.middleware.WrapAuth(b.blockHandler.GetBlockedUsers))
+	mux.Handle("DELETE /v1/blocks/{id}", b.middleware.WrapAuth(b.blockHandler.UnblockUser))
+}
+
+func NewBlockRoute(middleware *middleware.Middleware, blockHandler *handler.BlockHandler) *BlockRoute {
+	return &BlockRoute{
+		middleware:   middleware,
+		blockHandler: blockHandler,
+	}
+}
@@ -0,0 +1,32 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type BotRoute struct {
+	middleware *middleware.Middleware
+	botHandler *handler.BotHandler
+}
+
+func (b *BotRoute) BotRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/bots", b.middleware.WrapVerified(domain.CapabilityBots, b.botHandler.CreateBot))
+	mux.Handle("GET /v1/bots", b.middleware.WrapAuth(b.botHandler.GetBots))
+	mux.Handle("POST /v1/bots/{id}/token", b.middleware.WrapAuth(b.botHandler.RegenerateToken))
+
+	/*----------Bot API----------*/
+	// "/bot{token}/{method}" can't be expressed as a ServeMux pattern because the
+	// token shares a segment with the "bot" prefix, so the Bot API takes the
+	// catch-all route and answers 404 for anything else.
+	mux.HandleFunc("/", b.botHandler.ServeBotApi)
+}
+
+func NewBotRoute(middleware *middleware.Middleware, botHandler *handler.BotHandler) *BotRoute {
+	return &BotRoute{
+		middleware: middleware,
+		botHandler: botHandler,
+	}
+}
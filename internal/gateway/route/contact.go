@@ -0,0 +1,27 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type ContactRoute struct {
+	middleware     *middleware.Middleware
+	contactHandler *handler.ContactHandler
+}
+
+func (c *ContactRoute) ContactRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/contacts", c.middleware.WrapAuth(c.contactHandler.AddContact))
+	mux.Handle("GET /v1/contacts", c.middleware.WrapAuth(c.contactHandler.GetContacts))
+	mux.Handle("POST /v1/contacts/import", c.middleware.WrapAuth(c.contactHandler.ImportContacts))
+	mux.Handle("PATCH /v1/contacts/{id}", c.middleware.WrapAuth(c.contactHandler.UpdateContact))
+	mux.Handle("DELETE /v1/contacts/{id}", c.middleware.WrapAuth(c.contactHandler.DeleteContact))
+}
+
+func NewContactRoute(middleware *middleware.Middleware, contactHandler *handler.ContactHandler) *ContactRoute {
+	return &ContactRoute{
+		middleware:     middleware,
+		contactHandler: contactHandler,
+	}
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type ImportRoute struct {
+	middleware    *middleware.Middleware
+	importHandler *handler.ImportHandler
+}
+
+func (i *ImportRoute) ImportRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/admin/imports/telegram", i.middleware.WrapAdmin(i.importHandler.ImportTelegram))
+	mux.Handle("GET /v1/admin/imports/{id}", i.middleware.WrapAdmin(i.importHandler.GetImportJob))
+}
+
+func NewImportRoute(middleware *middleware.Middleware, importHandler *handler.ImportHandler) *ImportRoute {
+	return &ImportRoute{
+		middleware:    middleware,
+		importHandler: importHandler,
+	}
+}
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type IncomingWebhookRoute struct {
+	middleware             *middleware.Middleware
+	incomingWebhookHandler *handler.IncomingWebhookHandler
+}
+
+func (i *IncomingWebhookRoute) IncomingWebhookRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/conversations/privates/{id}/incoming-webhooks", i.middleware.WrapVerified(domain.CapabilityMessaging, i.incomingWebhookHandler.CreateIncomingWebhook))
+	mux.Handle("GET /v1/conversations/privates/{id}/incoming-webhooks", i.middleware.WrapAuth(i.incomingWebhookHandler.GetIncomingWebhooks))
+	mux.Handle("DELETE /v1/incoming-webhooks/{id}", i.middleware.WrapAuth(i.incomingWebhookHandler.DeleteIncomingWebhook))
+	mux.HandleFunc("POST /v1/hooks/{id}/{token}", i.incomingWebhookHandler.PostMessage)
+}
+
+func NewIncomingWebhookRoute(middleware *middleware.Middleware, incomingWebhookHandler *handler.IncomingWebhookHandler) *IncomingWebhookRoute {
+	return &IncomingWebhookRoute{
+		middleware:             middleware,
+		incomingWebhookHandler: incomingWebhookHandler,
+	}
+}
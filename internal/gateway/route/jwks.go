@@ -0,0 +1,20 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"net/http"
+)
+
+type JWKSRoute struct {
+	jwksHandler *handler.JWKSHandler
+}
+
+func (j *JWKSRoute) JWKSRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /.well-known/jwks.json", j.jwksHandler.JWKS)
+}
+
+func NewJWKSRoute(jwksHandler *handler.JWKSHandler) *JWKSRoute {
+	return &JWKSRoute{
+		jwksHandler: jwksHandler,
+	}
+}
@@ -1,6 +1,7 @@
 package route
 
 import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
 	"net/http"
@@ -12,11 +13,11 @@ type MessageRoute struct {
 }
 
 func (m *MessageRoute) MessageRoutes(mux *http.ServeMux) {
-	mux.Handle("POST /v1/messages", m.middleware.WrapAuth(m.messageHandler.SendMessage))
-	mux.Handle("GET /v1/messages/{id}", m.middleware.WrapAuth(m.messageHandler.GetMessage))
-	mux.Handle("GET /v1/conversations/privates/{id}/messages", m.middleware.WrapAuth(m.messageHandler.GetPrivateMessages))
-	mux.Handle("PATCH /v1/messages/{id}/read", m.middleware.WrapAuth(m.messageHandler.MarkMessageAsRead))
-	mux.Handle("PATCH /v1/messages/{id}/delivered", m.middleware.WrapAuth(m.messageHandler.MarkMessageAsDelivered))
+	mux.Handle("POST /v1/messages", m.middleware.WrapVerifiedScoped(domain.CapabilityMessaging, domain.TokenScopeSendMessages, m.messageHandler.SendMessage))
+	mux.Handle("GET /v1/messages/{id}", m.middleware.WrapScoped(domain.TokenScopeReadMessages, m.messageHandler.GetMessage))
+	mux.Handle("GET /v1/conversations/privates/{id}/messages", m.middleware.WrapScoped(domain.TokenScopeReadMessages, m.messageHandler.GetPrivateMessages))
+	mux.Handle("PATCH /v1/messages/{id}/read", m.middleware.WrapScoped(domain.TokenScopeReadMessages, m.messageHandler.MarkMessageAsRead))
+	mux.Handle("PATCH /v1/messages/{id}/delivered", m.middleware.WrapScoped(domain.TokenScopeReadMessages, m.messageHandler.MarkMessageAsDelivered))
 }
 
 func NewMessageRoute(middleware *middleware.Middleware, messageHandler *handler.MessageHandler) *MessageRoute {
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"net/http"
+)
+
+type OIDCRoute struct {
+	oidcHandler *handler.OIDCHandler
+}
+
+func (o *OIDCRoute) OIDCRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/auth/oidc/start", o.oidcHandler.Start)
+	mux.HandleFunc("POST /v1/auth/oidc/callback", o.oidcHandler.Callback)
+}
+
+func NewOIDCRoute(oidcHandler *handler.OIDCHandler) *OIDCRoute {
+	return &OIDCRoute{
+		oidcHandler: oidcHandler,
+	}
+}
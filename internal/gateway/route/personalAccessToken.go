@@ -0,0 +1,25 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type PersonalAccessTokenRoute struct {
+	middleware                 *middleware.Middleware
+	personalAccessTokenHandler *handler.PersonalAccessTokenHandler
+}
+
+func (p *PersonalAccessTokenRoute) PersonalAccessTokenRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/auth/tokens", p.middleware.WrapAuth(p.personalAccessTokenHandler.CreateToken))
+	mux.Handle("GET /v1/auth/tokens", p.middleware.WrapAuth(p.personalAccessTokenHandler.GetTokens))
+	mux.Handle("DELETE /v1/auth/tokens/{id}", p.middleware.WrapAuth(p.personalAccessTokenHandler.RevokeToken))
+}
+
+func NewPersonalAccessTokenRoute(middleware *middleware.Middleware, personalAccessTokenHandler *handler.PersonalAccessTokenHandler) *PersonalAccessTokenRoute {
+	return &PersonalAccessTokenRoute{
+		middleware:                 middleware,
+		personalAccessTokenHandler: personalAccessTokenHandler,
+	}
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type PrivacyRoute struct {
+	middleware     *middleware.Middleware
+	privacyHandler *handler.PrivacyHandler
+}
+
+func (p *PrivacyRoute) PrivacyRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /v1/users/me/privacy", p.middleware.WrapAuth(p.privacyHandler.GetPrivacyRules))
+	mux.Handle("PUT /v1/users/me/privacy/{key}", p.middleware.WrapAuth(p.privacyHandler.UpdatePrivacyRule))
+}
+
+func NewPrivacyRoute(middleware *middleware.Middleware, privacyHandler *handler.PrivacyHandler) *PrivacyRoute {
+	return &PrivacyRoute{
+		middleware:     middleware,
+		privacyHandler: privacyHandler,
+	}
+}
@@ -1,6 +1,7 @@
 package route
 
 import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
 	"net/http"
@@ -12,9 +13,9 @@ type PrivateRoute struct {
 }
 
 func (p *PrivateRoute) PrivateRoutes(mux *http.ServeMux) {
-	mux.Handle("POST /v1/conversations/privates", p.middleware.WrapAuth(p.privateHandler.CreatePrivate))
-	mux.Handle("GET /v1/conversations/privates/{id}", p.middleware.WrapAuth(p.privateHandler.GetPrivateById))
-	mux.Handle("GET /v1/conversations", p.middleware.WrapAuth(p.privateHandler.GetConversations))
+	mux.Handle("POST /v1/conversations/privates", p.middleware.WrapVerifiedScoped(domain.CapabilityMessaging, domain.TokenScopeManageConversations, p.privateHandler.CreatePrivate))
+	mux.Handle("GET /v1/conversations/privates/{id}", p.middleware.WrapScoped(domain.TokenScopeReadMessages, p.privateHandler.GetPrivateById))
+	mux.Handle("GET /v1/conversations", p.middleware.WrapScoped(domain.TokenScopeReadMessages, p.privateHandler.GetConversations))
 }
 
 func NewPrivateRoute(middleware *middleware.Middleware, privateHandler *handler.PrivateHandler) *PrivateRoute {
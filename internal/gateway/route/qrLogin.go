@@ -0,0 +1,24 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type QRLoginRoute struct {
+	middleware     *middleware.Middleware
+	qrLoginHandler *handler.QRLoginHandler
+}
+
+func (q *QRLoginRoute) QRLoginRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/auth/qr/ws", q.qrLoginHandler.Connect)
+	mux.Handle("POST /v1/auth/qr/approve", q.middleware.WrapAuth(q.qrLoginHandler.Approve))
+}
+
+func NewQRLoginRoute(middleware *middleware.Middleware, qrLoginHandler *handler.QRLoginHandler) *QRLoginRoute {
+	return &QRLoginRoute{
+		middleware:     middleware,
+		qrLoginHandler: qrLoginHandler,
+	}
+}
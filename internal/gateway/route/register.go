@@ -7,14 +7,28 @@ import (
 )
 
 type RegisterRoute struct {
-	Middleware       *middleware.Middleware
-	HealthCheckRoute *HealthCheckRoute
-	AuthRoute        *AuthRoute
-	UserRoute        *UserRoute
-	PrivateRoute     *PrivateRoute
-	MessageRoute     *MessageRoute
-	UploadFileRoute  *UploadFileRoute
-	WsRoute          *WSRoute
+	Middleware               *middleware.Middleware
+	HealthCheckRoute         *HealthCheckRoute
+	AuthRoute                *AuthRoute
+	UserRoute                *UserRoute
+	PrivateRoute             *PrivateRoute
+	MessageRoute             *MessageRoute
+	UploadFileRoute          *UploadFileRoute
+	WsRoute                  *WSRoute
+	ImportRoute              *ImportRoute
+	BotRoute                 *BotRoute
+	WebhookRoute             *WebhookRoute
+	IncomingWebhookRoute     *IncomingWebhookRoute
+	ContactRoute             *ContactRoute
+	BlockRoute               *BlockRoute
+	PrivacyRoute             *PrivacyRoute
+	TwoFactorRoute           *TwoFactorRoute
+	JWKSRoute                *JWKSRoute
+	OIDCRoute                *OIDCRoute
+	AccountDeletionRoute     *AccountDeletionRoute
+	QRLoginRoute             *QRLoginRoute
+	PersonalAccessTokenRoute *PersonalAccessTokenRoute
+	AdminRoute               *AdminRoute
 }
 
 type Options func(*RegisterRoute)
@@ -67,6 +81,90 @@ func WithWsRoute(route *WSRoute) Options {
 	}
 }
 
+func WithImportRoute(route *ImportRoute) Options {
+	return func(r *RegisterRoute) {
+		r.ImportRoute = route
+	}
+}
+
+func WithBotRoute(route *BotRoute) Options {
+	return func(r *RegisterRoute) {
+		r.BotRoute = route
+	}
+}
+
+func WithWebhookRoute(route *WebhookRoute) Options {
+	return func(r *RegisterRoute) {
+		r.WebhookRoute = route
+	}
+}
+
+func WithIncomingWebhookRoute(route *IncomingWebhookRoute) Options {
+	return func(r *RegisterRoute) {
+		r.IncomingWebhookRoute = route
+	}
+}
+
+func WithContactRoute(route *ContactRoute) Options {
+	return func(r *RegisterRoute) {
+		r.ContactRoute = route
+	}
+}
+
+func WithBlockRoute(route *BlockRoute) Options {
+	return func(r *RegisterRoute) {
+		r.BlockRoute = route
+	}
+}
+
+func WithPrivacyRoute(route *PrivacyRoute) Options {
+	return func(r *RegisterRoute) {
+		r.PrivacyRoute = route
+	}
+}
+
+func WithTwoFactorRoute(route *TwoFactorRoute) Options {
+	return func(r *RegisterRoute) {
+		r.TwoFactorRoute = route
+	}
+}
+
+func WithJWKSRoute(route *JWKSRoute) Options {
+	return func(r *RegisterRoute) {
+		r.JWKSRoute = route
+	}
+}
+
+func WithOIDCRoute(route *OIDCRoute) Options {
+	return func(r *RegisterRoute) {
+		r.OIDCRoute = route
+	}
+}
+
+func WithAccountDeletionRoute(route *AccountDeletionRoute) Options {
+	return func(r *RegisterRoute) {
+		r.AccountDeletionRoute = route
+	}
+}
+
+func WithQRLoginRoute(route *QRLoginRoute) Options {
+	return func(r *RegisterRoute) {
+		r.QRLoginRoute = route
+	}
+}
+
+func WithPersonalAccessTokenRoute(route *PersonalAccessTokenRoute) Options {
+	return func(r *RegisterRoute) {
+		r.PersonalAccessTokenRoute = route
+	}
+}
+
+func WithAdminRoute(route *AdminRoute) Options {
+	return func(r *RegisterRoute) {
+		r.AdminRoute = route
+	}
+}
+
 func (r *RegisterRoute) RegisterRoutes() http.Handler {
 	mux := http.NewServeMux()
 
@@ -87,6 +185,20 @@ func (r *RegisterRoute) RegisterRoutes() http.Handler {
 	r.MessageRoute.MessageRoutes(mux)
 	r.UploadFileRoute.UploadFileRoutes(mux)
 	r.WsRoute.WSRoutes(mux)
+	r.ImportRoute.ImportRoutes(mux)
+	r.BotRoute.BotRoutes(mux)
+	r.WebhookRoute.WebhookRoutes(mux)
+	r.IncomingWebhookRoute.IncomingWebhookRoutes(mux)
+	r.ContactRoute.ContactRoutes(mux)
+	r.BlockRoute.BlockRoutes(mux)
+	r.PrivacyRoute.PrivacyRoutes(mux)
+	r.TwoFactorRoute.TwoFactorRoutes(mux)
+	r.JWKSRoute.JWKSRoutes(mux)
+	r.OIDCRoute.OIDCRoutes(mux)
+	r.AccountDeletionRoute.AccountDeletionRoutes(mux)
+	r.QRLoginRoute.QRLoginRoutes(mux)
+	r.PersonalAccessTokenRoute.PersonalAccessTokenRoutes(mux)
+	r.AdminRoute.AdminRoutes(mux)
 	return r.Middleware.Recover(r.Middleware.Logging(r.Middleware.CORS(mux)))
 }
 
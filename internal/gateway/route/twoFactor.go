@@ -0,0 +1,25 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type TwoFactorRoute struct {
+	middleware       *middleware.Middleware
+	twoFactorHandler *handler.TwoFactorHandler
+}
+
+func (t *TwoFactorRoute) TwoFactorRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/auth/2fa/enroll", t.middleware.WrapAuth(t.twoFactorHandler.Enroll))
+	mux.Handle("POST /v1/auth/2fa/confirm", t.middleware.WrapAuth(t.twoFactorHandler.Confirm))
+	mux.Handle("POST /v1/auth/2fa/disable", t.middleware.WrapAuth(t.twoFactorHandler.Disable))
+}
+
+func NewTwoFactorRoute(middleware *middleware.Middleware, twoFactorHandler *handler.TwoFactorHandler) *TwoFactorRoute {
+	return &TwoFactorRoute{
+		middleware:       middleware,
+		twoFactorHandler: twoFactorHandler,
+	}
+}
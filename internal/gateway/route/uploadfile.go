@@ -1,6 +1,7 @@
 package route
 
 import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
 	"net/http"
@@ -12,8 +13,8 @@ type UploadFileRoute struct {
 }
 
 func (u *UploadFileRoute) UploadFileRoutes(mux *http.ServeMux) {
-	mux.Handle("POST /v1/files/{id}", u.middleware.WrapAuth(u.uploadFileHandler.UploadFile))
-	mux.Handle("GET /v1/files/", u.middleware.WrapAuth(u.uploadFileHandler.GetFile().ServeHTTP))
+	mux.Handle("POST /v1/files/{id}", u.middleware.WrapVerifiedScoped(domain.CapabilityMessaging, domain.TokenScopeSendMessages, u.uploadFileHandler.UploadFile))
+	mux.Handle("GET /v1/files/", u.middleware.WrapScoped(domain.TokenScopeReadMessages, u.uploadFileHandler.GetFile().ServeHTTP))
 }
 
 func NewUploadFileRoute(middleware *middleware.Middleware, uploadFileHandler *handler.UploadFileHandler) *UploadFileRoute {
@@ -13,6 +13,13 @@ type UserRoute struct {
 
 func (u *UserRoute) UserRoutes(mux *http.ServeMux) {
 	mux.Handle("GET /v1/users/{id}", u.middleware.WrapAuth(u.userHandler.GetUserById))
+	mux.Handle("PATCH /v1/users/me", u.middleware.WrapAuth(u.userHandler.UpdateProfile))
+	mux.Handle("POST /v1/users/me/avatar", u.middleware.WrapAuth(u.userHandler.UploadAvatar))
+	mux.Handle("DELETE /v1/users/me/avatar", u.middleware.WrapAuth(u.userHandler.DeleteAvatar))
+	mux.Handle("PUT /v1/users/me/username", u.middleware.WrapAuth(u.userHandler.SetUsername))
+	mux.Handle("GET /v1/users/me/username/history", u.middleware.WrapAuth(u.userHandler.GetUsernameHistory))
+	mux.Handle("GET /v1/users/by-username/{username}", u.middleware.WrapAuth(u.userHandler.GetUserByUsername))
+	mux.Handle("GET /v1/users/search", u.middleware.WrapAuth(u.userHandler.SearchUsers))
 }
 
 func NewUserRoute(middleware *middleware.Middleware, userHandler *handler.UserHandler) *UserRoute {
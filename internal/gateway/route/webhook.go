@@ -0,0 +1,27 @@
+package route
+
+import (
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/handler"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/gateway/middleware"
+	"net/http"
+)
+
+type WebhookRoute struct {
+	middleware     *middleware.Middleware
+	webhookHandler *handler.WebhookHandler
+}
+
+func (wr *WebhookRoute) WebhookRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /v1/webhooks", wr.middleware.WrapAuth(wr.webhookHandler.CreateWebhook))
+	mux.Handle("GET /v1/webhooks", wr.middleware.WrapAuth(wr.webhookHandler.GetWebhooks))
+	mux.Handle("DELETE /v1/webhooks/{id}", wr.middleware.WrapAuth(wr.webhookHandler.DeleteWebhook))
+	mux.Handle("POST /v1/webhooks/{id}/enable", wr.middleware.WrapAuth(wr.webhookHandler.EnableWebhook))
+	mux.Handle("GET /v1/webhooks/{id}/deliveries", wr.middleware.WrapAuth(wr.webhookHandler.GetDeliveries))
+}
+
+func NewWebhookRoute(middleware *middleware.Middleware, webhookHandler *handler.WebhookHandler) *WebhookRoute {
+	return &WebhookRoute{
+		middleware:     middleware,
+		webhookHandler: webhookHandler,
+	}
+}
@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"net"
 	"net/http"
 	"strconv"
 )
@@ -14,3 +15,12 @@ func ReadParams(r *http.Request) (uint, error) {
 
 	return uint(uintId), nil
 }
+
+// ClientIP is the address the request came from, without the port.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
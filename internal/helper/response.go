@@ -9,6 +9,14 @@ type Response struct {
 	Error   string `json:"error"`
 }
 
+// BotApiResponse is the envelope of the Telegram Bot API, used by /bot{token}/ routes.
+type BotApiResponse struct {
+	Ok          bool   `json:"ok"`
+	Result      any    `json:"result,omitempty"`
+	ErrorCode   int    `json:"error_code,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 type PaginatedMeta struct {
 	Page      int64 `json:"page"`
 	Limit     int64 `json:"limit"`
@@ -39,6 +47,15 @@ func CreatedResponse(w http.ResponseWriter, message string, data any) {
 	writeJSON(w, http.StatusCreated, response)
 }
 
+func AcceptedResponse(w http.ResponseWriter, message string, data any) {
+	response := Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	}
+	writeJSON(w, http.StatusAccepted, response)
+}
+
 func ErrorResponse(w http.ResponseWriter, statusCode int, message string, err error) {
 	response := Response{
 		Success: false,
@@ -101,3 +118,24 @@ func PaginatedSuccessResponse(w http.ResponseWriter, message string, data any, m
 	}
 	writeJSON(w, http.StatusOK, paginatedResponse)
 }
+
+// JSONResponse writes data as is, for responses that follow a format of their
+// own rather than Response.
+func JSONResponse(w http.ResponseWriter, data any) {
+	writeJSON(w, http.StatusOK, data)
+}
+
+func BotApiSuccessResponse(w http.ResponseWriter, result any) {
+	writeJSON(w, http.StatusOK, BotApiResponse{
+		Ok:     true,
+		Result: result,
+	})
+}
+
+func BotApiErrorResponse(w http.ResponseWriter, statusCode int, description string) {
+	writeJSON(w, statusCode, BotApiResponse{
+		Ok:          false,
+		ErrorCode:   statusCode,
+		Description: description,
+	})
+}
@@ -8,7 +8,10 @@ import (
 )
 
 var (
-	EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	EmailRX         = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	BotUsernameRX   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{1,28}[bB][oO][tT]$`)
+	UsernameRX      = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{3,30}[a-zA-Z0-9]$`)
+	UsernameQueryRX = regexp.MustCompile(`^[a-zA-Z0-9_]{1,32}$`)
 )
 
 type Validator struct {
@@ -74,6 +77,14 @@ func IsURL(value string) bool {
 	return u.Scheme != "" && u.Host != ""
 }
 
+func IsHTTPURL(value string) bool {
+	u, err := url.ParseRequestURI(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 func NewValidator() *Validator {
 	return &Validator{Errors: make(map[string]string)}
 }
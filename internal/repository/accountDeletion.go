@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type AccountDeletionRepository interface {
+	ScheduleDeletion(ctx context.Context, userId uint, dueAt time.Time) error
+	CancelDeletion(ctx context.Context, userId uint) (bool, error)
+	GetUsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]domain.User, error)
+	AnonymizeUser(ctx context.Context, userId uint, purgeMessages bool) ([]uint, error)
+}
+
+type accountDeletionRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (a *accountDeletionRepository) ScheduleDeletion(ctx context.Context, userId uint, dueAt time.Time) error {
+	return a.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND anonymized_at IS NULL", userId).
+		Updates(map[string]any{
+			"deletion_due_at": dueAt,
+			"version":         gorm.Expr("version + 1"),
+		}).Error
+}
+
+// CancelDeletion reports false when no deletion was scheduled.
+func (a *accountDeletionRepository) CancelDeletion(ctx context.Context, userId uint) (bool, error) {
+	result := a.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND deletion_due_at IS NOT NULL AND anonymized_at IS NULL", userId).
+		Updates(map[string]any{
+			"deletion_due_at": nil,
+			"version":         gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (a *accountDeletionRepository) GetUsersDueForDeletion(ctx context.Context, now time.Time, limit int) ([]domain.User, error) {
+	var users []domain.User
+	if err := a.dbRead.WithContext(ctx).
+		Where("deletion_due_at <= ? AND anonymized_at IS NULL", now).
+		Order("deletion_due_at").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AnonymizeUser deletes everything personal about the user and the bots they
+// own, keeping the user rows so conversations with them still have a sender.
+// Image and file messages go either way since their uploads are removed, text
+// messages only when purgeMessages is set. It returns the ids of the ended
+// sessions, or ErrRecordNotFound when the deletion was cancelled meanwhile.
+func (a *accountDeletionRepository) AnonymizeUser(ctx context.Context, userId uint, purgeMessages bool) ([]uint, error) {
+	var sessionIds []uint
+
+	err := a.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deletion_due_at IS NOT NULL AND anonymized_at IS NULL", userId).
+			First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			return err
+		}
+
+		var botUserIds []uint
+		if err := tx.Model(&domain.Bot{}).Where("owner_id = ?", userId).Pluck("user_id", &botUserIds).Error; err != nil {
+			return err
+		}
+		userIds := append([]uint{userId}, botUserIds...)
+
+		var sessions []domain.Session
+		if err := tx.Model(&sessions).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("user_id IN ?", userIds).
+			Delete(&sessions).Error; err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			sessionIds = append(sessionIds, session.Id)
+		}
+
+		deletes := []struct {
+			model any
+			query string
+		}{
+			{&domain.PasswordReset{}, "user_id IN @ids"},
+			{&domain.TwoFactor{}, "user_id IN @ids"},
+			{&domain.RecoveryCode{}, "user_id IN @ids"},
+			{&domain.UserIdentity{}, "user_id IN @ids"},
+			{&domain.PersonalAccessToken{}, "user_id IN @ids"},
+			{&domain.PrivacyRule{}, "user_id IN @ids"},
+			{&domain.UsernameChange{}, "user_id IN @ids"},
+			{&domain.Contact{}, "owner_id IN @ids OR contact_id IN @ids"},
+			{&domain.Block{}, "blocker_id IN @ids OR blocked_id IN @ids"},
+			{&domain.Webhook{}, "user_id IN @ids"},
+			{&domain.IncomingWebhook{}, "creator_id IN @ids"},
+			{&domain.BotCallbackQuery{}, "user_id IN @ids"},
+			{&domain.Bot{}, "owner_id IN @ids"},
+		}
+		for _, d := range deletes {
+			if err := tx.Where(d.query, map[string]any{"ids": userIds}).Delete(d.model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&domain.ImportJob{}).Where("requested_by_id IN ?", userIds).Update("requested_by_id", nil).Error; err != nil {
+			return err
+		}
+
+		messages := tx.Where("from_id IN ?", userIds)
+		if !purgeMessages {
+			messages = messages.Where("message_type IN ?", []domain.MessageType{domain.MessageTypeImage, domain.MessageTypeFile})
+		}
+		if err := messages.Delete(&domain.Message{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.User{}).
+			Where("id IN ?", userIds).
+			Updates(map[string]any{
+				"name":                 domain.DeletedAccountName,
+				"username":             nil,
+				"bio":                  "",
+				"avatar_url":           nil,
+				"last_seen_at":         nil,
+				"email":                gorm.Expr("'deleted-' || id || '@deleted.invalid'"),
+				"password":             "",
+				"email_verified_at":    nil,
+				"verification_sent_at": nil,
+				"deletion_due_at":      nil,
+				"anonymized_at":        time.Now(),
+				"version":              gorm.Expr("version + 1"),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessionIds, nil
+}
+
+func NewAccountDeletionRepository(dbWrite, dbRead *gorm.DB) AccountDeletionRepository {
+	return &accountDeletionRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AdminRepository interface {
+	GetUsers(ctx context.Context, query string, role domain.Role, status domain.AccountStatus, now time.Time, offset, limit int) ([]domain.User, int64, error)
+	SetRole(ctx context.Context, userId uint, role domain.Role) error
+	SuspendUser(ctx context.Context, userId uint, at time.Time, until *time.Time, reason string) error
+	UnsuspendUser(ctx context.Context, userId uint) (bool, error)
+	GetStats(ctx context.Context, now time.Time) (*domain.ServerStats, error)
+}
+
+type adminRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+// GetUsers lists accounts newest first, bots included. query matches an id
+// exactly, or part of a name, username or email; an empty role or status
+// doesn't filter.
+func (a *adminRepository) GetUsers(ctx context.Context, query string, role domain.Role, status domain.AccountStatus, now time.Time, offset, limit int) ([]domain.User, int64, error) {
+	db := a.dbRead.WithContext(ctx).Model(&domain.User{})
+
+	if query != "" {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(query)) + "%"
+		if id, err := strconv.ParseUint(query, 10, 64); err == nil {
+			db = db.Where("id = ? OR lower(name) LIKE ? OR lower(username) LIKE ? OR lower(email) LIKE ?", id, pattern, pattern, pattern)
+		} else {
+			db = db.Where("lower(name) LIKE ? OR lower(username) LIKE ? OR lower(email) LIKE ?", pattern, pattern, pattern)
+		}
+	}
+
+	if role != "" {
+		db = db.Where("role = ?", role)
+	}
+
+	suspended := "suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > @now)"
+	switch status {
+	case domain.AccountActive:
+		db = db.Where("anonymized_at IS NULL AND NOT ("+suspended+")", map[string]any{"now": now})
+	case domain.AccountSuspended:
+		db = db.Where("anonymized_at IS NULL AND "+suspended, map[string]any{"now": now})
+	case domain.AccountDeleted:
+		db = db.Where("anonymized_at IS NOT NULL")
+	}
+
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	if err := db.
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (a *adminRepository) SetRole(ctx context.Context, userId uint, role domain.Role) error {
+	result := a.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND type = ? AND anonymized_at IS NULL", userId, domain.UserTypeUser).
+		Updates(map[string]any{
+			"role":    role,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// SuspendUser replaces any earlier suspension. until is nil for a ban.
+func (a *adminRepository) SuspendUser(ctx context.Context, userId uint, at time.Time, until *time.Time, reason string) error {
+	result := a.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND type = ? AND anonymized_at IS NULL", userId, domain.UserTypeUser).
+		Updates(map[string]any{
+			"suspended_at":      at,
+			"suspended_until":   until,
+			"suspension_reason": reason,
+			"version":           gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// UnsuspendUser reports false when the user wasn't suspended.
+func (a *adminRepository) UnsuspendUser(ctx context.Context, userId uint) (bool, error) {
+	result := a.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND suspended_at IS NOT NULL", userId).
+		Updates(map[string]any{
+			"suspended_at":      nil,
+			"suspended_until":   nil,
+			"suspension_reason": "",
+			"version":           gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (a *adminRepository) GetStats(ctx context.Context, now time.Time) (*domain.ServerStats, error) {
+	var stats domain.ServerStats
+	if err := a.dbRead.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT count(*) FROM users WHERE type = @user AND anonymized_at IS NULL) AS users,
+			(SELECT count(*) FROM users WHERE type = @bot AND anonymized_at IS NULL) AS bots,
+			(SELECT count(*) FROM users WHERE anonymized_at IS NULL AND suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > @now)) AS suspended_users,
+			(SELECT count(*) FROM users WHERE anonymized_at IS NOT NULL) AS deleted_users,
+			(SELECT count(*) FROM sessions) AS sessions,
+			(SELECT count(*) FROM privates) AS privates,
+			(SELECT count(*) FROM messages) AS messages,
+			(SELECT count(*) FROM messages WHERE created_at > @dayAgo) AS messages_last_day`,
+		map[string]any{
+			"user":   domain.UserTypeUser,
+			"bot":    domain.UserTypeBot,
+			"now":    now,
+			"dayAgo": now.Add(-24 * time.Hour),
+		}).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func NewAdminRepository(dbWrite, dbRead *gorm.DB) AdminRepository {
+	return &adminRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
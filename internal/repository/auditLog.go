@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	CreateAuditLog(ctx context.Context, auditLog *domain.AuditLog) error
+}
+
+type auditLogRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (a *auditLogRepository) CreateAuditLog(ctx context.Context, auditLog *domain.AuditLog) error {
+	return a.dbWrite.WithContext(ctx).Omit("User").Create(auditLog).Error
+}
+
+func NewAuditLogRepository(dbWrite, dbRead *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BlockRepository interface {
+	CreateBlock(ctx context.Context, block *domain.Block) (bool, error)
+	DeleteBlock(ctx context.Context, blockerId, blockedId uint) error
+	GetBlocks(ctx context.Context, blockerId uint) ([]domain.Block, error)
+	IsBlocked(ctx context.Context, user1Id, user2Id uint) (bool, error)
+	GetBlockedUserIds(ctx context.Context, userId uint) ([]uint, error)
+}
+
+type blockRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+// CreateBlock reports false when the user had already been blocked.
+func (b *blockRepository) CreateBlock(ctx context.Context, block *domain.Block) (bool, error) {
+	result := b.dbWrite.WithContext(ctx).
+		Omit("Blocker", "Blocked").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&block)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (b *blockRepository) DeleteBlock(ctx context.Context, blockerId, blockedId uint) error {
+	result := b.dbWrite.WithContext(ctx).
+		Where("blocker_id = ? AND blocked_id = ?", blockerId, blockedId).
+		Delete(&domain.Block{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (b *blockRepository) GetBlocks(ctx context.Context, blockerId uint) ([]domain.Block, error) {
+	var blocks []domain.Block
+	if err := b.dbRead.WithContext(ctx).
+		Preload("Blocked").
+		Where("blocker_id = ?", blockerId).
+		Order("created_at DESC").
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// IsBlocked reports whether either user blocked the other.
+func (b *blockRepository) IsBlocked(ctx context.Context, user1Id, user2Id uint) (bool, error) {
+	var count int64
+	if err := b.dbRead.WithContext(ctx).
+		Model(&domain.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", user1Id, user2Id, user2Id, user1Id).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetBlockedUserIds returns the users userId blocked together with the users
+// who blocked userId.
+func (b *blockRepository) GetBlockedUserIds(ctx context.Context, userId uint) ([]uint, error) {
+	var userIds []uint
+	if err := b.dbRead.WithContext(ctx).
+		Model(&domain.Block{}).
+		Select("CASE WHEN blocker_id = ? THEN blocked_id ELSE blocker_id END", userId).
+		Where("blocker_id = ? OR blocked_id = ?", userId, userId).
+		Scan(&userIds).Error; err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+func NewBlockRepository(dbWrite, dbRead *gorm.DB) BlockRepository {
+	return &blockRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+)
+
+type BotRepository interface {
+	CreateBot(ctx context.Context, bot *domain.Bot) error
+	GetBotById(ctx context.Context, id uint) (*domain.Bot, error)
+	GetBotByUserId(ctx context.Context, userId uint) (*domain.Bot, error)
+	GetBotsByOwnerId(ctx context.Context, ownerId uint) ([]domain.Bot, error)
+	CheckBotUsernameExists(ctx context.Context, username string) (bool, error)
+	UpdateBot(ctx context.Context, bot *domain.Bot) error
+
+	CreateBotUpdate(ctx context.Context, update *domain.BotUpdate) error
+	GetBotUpdates(ctx context.Context, botId uint, offset uint, limit int) ([]domain.BotUpdate, error)
+	DeleteBotUpdatesBefore(ctx context.Context, botId uint, offset uint) error
+	DeleteBotUpdate(ctx context.Context, id uint) error
+
+	CreateCallbackQuery(ctx context.Context, query *domain.BotCallbackQuery) error
+	GetCallbackQueryById(ctx context.Context, id uint) (*domain.BotCallbackQuery, error)
+	MarkCallbackQueryAsAnswered(ctx context.Context, id uint) (bool, error)
+}
+
+type botRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+// CreateBot stores the bot's user account and its credentials in one
+// transaction; bot.User must be populated by the caller.
+func (b *botRepository) CreateBot(ctx context.Context, bot *domain.Bot) error {
+	return b.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&bot.User).Error; err != nil {
+			return err
+		}
+		bot.UserId = bot.User.Id
+		return tx.Omit("User", "Owner").Create(bot).Error
+	})
+}
+
+func (b *botRepository) GetBotById(ctx context.Context, id uint) (*domain.Bot, error) {
+	var bot domain.Bot
+	if err := b.dbRead.WithContext(ctx).Preload("User").First(&bot, id).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &bot, nil
+}
+
+func (b *botRepository) GetBotByUserId(ctx context.Context, userId uint) (*domain.Bot, error) {
+	var bot domain.Bot
+	if err := b.dbRead.WithContext(ctx).Preload("User").Where("user_id = ?", userId).First(&bot).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &bot, nil
+}
+
+func (b *botRepository) GetBotsByOwnerId(ctx context.Context, ownerId uint) ([]domain.Bot, error) {
+	var bots []domain.Bot
+	if err := b.dbRead.WithContext(ctx).
+		Preload("User").
+		Where("owner_id = ?", ownerId).
+		Order("created_at DESC").
+		Find(&bots).Error; err != nil {
+		return nil, err
+	}
+	return bots, nil
+}
+
+func (b *botRepository) CheckBotUsernameExists(ctx context.Context, username string) (bool, error) {
+	var count int64
+	if err := b.dbRead.WithContext(ctx).
+		Model(&domain.Bot{}).
+		Where("LOWER(username) = LOWER(?)", username).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (b *botRepository) UpdateBot(ctx context.Context, bot *domain.Bot) error {
+	return b.dbWrite.WithContext(ctx).Model(bot).Updates(map[string]any{
+		"token_hash":     bot.TokenHash,
+		"webhook_url":    bot.WebhookURL,
+		"webhook_secret": bot.WebhookSecret,
+		"version":        gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (b *botRepository) CreateBotUpdate(ctx context.Context, update *domain.BotUpdate) error {
+	return b.dbWrite.WithContext(ctx).Create(&update).Error
+}
+
+func (b *botRepository) GetBotUpdates(ctx context.Context, botId uint, offset uint, limit int) ([]domain.BotUpdate, error) {
+	var updates []domain.BotUpdate
+	if err := b.dbRead.WithContext(ctx).
+		Where("bot_id = ? AND id >= ?", botId, offset).
+		Order("id ASC").
+		Limit(limit).
+		Find(&updates).Error; err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (b *botRepository) DeleteBotUpdatesBefore(ctx context.Context, botId uint, offset uint) error {
+	return b.dbWrite.WithContext(ctx).Where("bot_id = ? AND id < ?", botId, offset).Delete(&domain.BotUpdate{}).Error
+}
+
+func (b *botRepository) DeleteBotUpdate(ctx context.Context, id uint) error {
+	return b.dbWrite.WithContext(ctx).Delete(&domain.BotUpdate{}, id).Error
+}
+
+func (b *botRepository) CreateCallbackQuery(ctx context.Context, query *domain.BotCallbackQuery) error {
+	return b.dbWrite.WithContext(ctx).Create(&query).Error
+}
+
+func (b *botRepository) GetCallbackQueryById(ctx context.Context, id uint) (*domain.BotCallbackQuery, error) {
+	var query domain.BotCallbackQuery
+	if err := b.dbRead.WithContext(ctx).First(&query, id).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &query, nil
+}
+
+// MarkCallbackQueryAsAnswered reports false when the query had already been
+// answered, so a query can only be answered once.
+func (b *botRepository) MarkCallbackQueryAsAnswered(ctx context.Context, id uint) (bool, error) {
+	result := b.dbWrite.WithContext(ctx).Model(&domain.BotCallbackQuery{}).
+		Where("id = ? AND answered = ?", id, false).
+		Update("answered", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func NewBotRepository(dbWrite, dbRead *gorm.DB) BotRepository {
+	return &botRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
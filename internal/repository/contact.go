@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContactRepository interface {
+	CreateContact(ctx context.Context, contact *domain.Contact) error
+	CreateContacts(ctx context.Context, contacts []domain.Contact) error
+	GetContact(ctx context.Context, ownerId, contactId uint) (*domain.Contact, error)
+	GetContacts(ctx context.Context, ownerId uint) ([]domain.Contact, error)
+	GetContactsByContactIds(ctx context.Context, ownerId uint, contactIds []uint) ([]domain.Contact, error)
+	UpdateContact(ctx context.Context, contact *domain.Contact) error
+	DeleteContact(ctx context.Context, ownerId, contactId uint) error
+	IsContact(ctx context.Context, ownerId, contactId uint) (bool, error)
+	GetContactIds(ctx context.Context, ownerId uint) ([]uint, error)
+	GetOwnerIdsHavingContact(ctx context.Context, contactId uint, ownerIds []uint) ([]uint, error)
+}
+
+type contactRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (c *contactRepository) CreateContact(ctx context.Context, contact *domain.Contact) error {
+	return c.dbWrite.WithContext(ctx).Omit("Owner", "Contact").Create(&contact).Error
+}
+
+// CreateContacts adds contacts in bulk, skipping the ones the owner already has.
+func (c *contactRepository) CreateContacts(ctx context.Context, contacts []domain.Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+	return c.dbWrite.WithContext(ctx).
+		Omit("Owner", "Contact").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&contacts).Error
+}
+
+func (c *contactRepository) GetContact(ctx context.Context, ownerId, contactId uint) (*domain.Contact, error) {
+	var contact domain.Contact
+	if err := c.dbRead.WithContext(ctx).
+		Preload("Contact").
+		Where("owner_id = ? AND contact_id = ?", ownerId, contactId).
+		First(&contact).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &contact, nil
+}
+
+func (c *contactRepository) GetContacts(ctx context.Context, ownerId uint) ([]domain.Contact, error) {
+	var contacts []domain.Contact
+	if err := c.dbRead.WithContext(ctx).
+		Preload("Contact").
+		Where("owner_id = ?", ownerId).
+		Order("created_at ASC").
+		Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+func (c *contactRepository) GetContactsByContactIds(ctx context.Context, ownerId uint, contactIds []uint) ([]domain.Contact, error) {
+	var contacts []domain.Contact
+	if len(contactIds) == 0 {
+		return contacts, nil
+	}
+	if err := c.dbRead.WithContext(ctx).
+		Preload("Contact").
+		Where("owner_id = ? AND contact_id IN ?", ownerId, contactIds).
+		Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+func (c *contactRepository) UpdateContact(ctx context.Context, contact *domain.Contact) error {
+	return c.dbWrite.WithContext(ctx).Model(contact).Updates(map[string]any{
+		"alias":   contact.Alias,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (c *contactRepository) DeleteContact(ctx context.Context, ownerId, contactId uint) error {
+	result := c.dbWrite.WithContext(ctx).
+		Where("owner_id = ? AND contact_id = ?", ownerId, contactId).
+		Delete(&domain.Contact{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsContact reports whether contactId is in ownerId's address book.
+func (c *contactRepository) IsContact(ctx context.Context, ownerId, contactId uint) (bool, error) {
+	var count int64
+	if err := c.dbRead.WithContext(ctx).
+		Model(&domain.Contact{}).
+		Where("owner_id = ? AND contact_id = ?", ownerId, contactId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (c *contactRepository) GetContactIds(ctx context.Context, ownerId uint) ([]uint, error) {
+	var contactIds []uint
+	if err := c.dbRead.WithContext(ctx).
+		Model(&domain.Contact{}).
+		Where("owner_id = ?", ownerId).
+		Pluck("contact_id", &contactIds).Error; err != nil {
+		return nil, err
+	}
+	return contactIds, nil
+}
+
+// GetOwnerIdsHavingContact returns which of ownerIds have contactId in their
+// contacts.
+func (c *contactRepository) GetOwnerIdsHavingContact(ctx context.Context, contactId uint, ownerIds []uint) ([]uint, error) {
+	var result []uint
+	if len(ownerIds) == 0 {
+		return result, nil
+	}
+	if err := c.dbRead.WithContext(ctx).
+		Model(&domain.Contact{}).
+		Where("contact_id = ? AND owner_id IN ?", contactId, ownerIds).
+		Pluck("owner_id", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func NewContactRepository(dbWrite, dbRead *gorm.DB) ContactRepository {
+	return &contactRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -3,8 +3,61 @@ package repository
 import "errors"
 
 var (
-	ErrRecordNotFound       = errors.New("record not found")
-	ErrEmailExists          = errors.New("email already exists")
-	ErrSameUser             = errors.New("cannot create private conversation with the same user")
-	ErrPrivateAlreadyExists = errors.New("private conversation already exists")
+	ErrRecordNotFound              = errors.New("record not found")
+	ErrEmailExists                 = errors.New("email already exists")
+	ErrRefreshTokenReused          = errors.New("refresh token was already used, the session has been revoked")
+	ErrInvalidRefreshToken         = errors.New("invalid refresh token")
+	ErrEmailNotVerified            = errors.New("email address is not verified")
+	ErrEmailAlreadyVerified        = errors.New("email address is already verified")
+	ErrInvalidVerificationToken    = errors.New("invalid or expired verification token")
+	ErrVerificationCooldown        = errors.New("a verification email was sent recently, try again later")
+	ErrInvalidPasswordResetToken   = errors.New("invalid or expired password reset token")
+	ErrPasswordResetRateLimited    = errors.New("too many password reset requests, try again later")
+	ErrIncorrectPassword           = errors.New("current password is incorrect")
+	ErrTwoFactorAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled         = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled        = errors.New("start two-factor enrollment first")
+	ErrInvalidTwoFactorCode        = errors.New("invalid two-factor code")
+	ErrTwoFactorRateLimited        = errors.New("too many two-factor attempts, try again later")
+	ErrInvalidChallengeToken       = errors.New("invalid or expired login challenge")
+	ErrInvalidCredentials          = errors.New("invalid credentials")
+	ErrLoginThrottled              = errors.New("too many failed login attempts, try again later")
+	ErrSignupRateLimited           = errors.New("too many signups, try again later")
+	ErrOIDCNotConfigured           = errors.New("single sign-on is not configured")
+	ErrInvalidOIDCState            = errors.New("invalid or expired single sign-on state")
+	ErrOIDCEmailNotVerified        = errors.New("the identity provider has not verified the email address")
+	ErrOIDCAccountNotVerified      = errors.New("an account with this email address exists but was never verified, log in with its password or reset it first")
+	ErrOIDCFailed                  = errors.New("single sign-on failed")
+	ErrInvalidPersonalAccessToken  = errors.New("invalid or expired personal access token")
+	ErrInvalidQRLoginToken         = errors.New("invalid or expired login QR code")
+	ErrQRLoginRateLimited          = errors.New("too many QR logins, try again later")
+	ErrDeletionAlreadyScheduled    = errors.New("account deletion is already scheduled")
+	ErrDeletionNotScheduled        = errors.New("no account deletion is scheduled")
+	ErrAccountDeleted              = errors.New("the account was deleted")
+	ErrAccountSuspended            = errors.New("the account is suspended")
+	ErrNotSuspended                = errors.New("user is not suspended")
+	ErrModerationDenied            = errors.New("can't moderate a user whose role isn't below yours")
+	ErrUsernameExists              = errors.New("username is already taken")
+	ErrContactExists               = errors.New("user is already a contact")
+	ErrContactSelf                 = errors.New("cannot add yourself as a contact")
+	ErrUserBlocked                 = errors.New("one of the users has blocked the other")
+	ErrBlockSelf                   = errors.New("cannot block yourself")
+	ErrAlreadyBlocked              = errors.New("user is already blocked")
+	ErrPrivacyRestricted           = errors.New("the user's privacy settings don't allow this")
+	ErrSameUser                    = errors.New("cannot create private conversation with the same user")
+	ErrPrivateAlreadyExists        = errors.New("private conversation already exists")
+	ErrBotUsernameExists           = errors.New("bot username already exists")
+	ErrInvalidBotToken             = errors.New("invalid bot token")
+	ErrBotWebhookActive            = errors.New("can't use getUpdates while a webhook is active")
+	ErrWebhookURLNotAllowed        = errors.New("webhook URL must resolve to a public address")
+	ErrBotChatNotFound             = errors.New("chat not found")
+	ErrReplyMarkupNotAllowed       = errors.New("only bots can attach inline keyboards")
+	ErrInvalidCallbackQuery        = errors.New("message has no such callback button")
+	ErrCallbackQueryNotFound       = errors.New("query is too old or query ID is invalid")
+	ErrBotMessageNotFound          = errors.New("message to edit not found")
+	ErrInvalidIncomingWebhookToken = errors.New("invalid incoming webhook token")
+	ErrIncomingWebhookRateLimited  = errors.New("incoming webhook rate limit exceeded")
+	ErrInvalidImage                = errors.New("image must be a valid JPEG, PNG or GIF")
+	ErrImportDisabled              = errors.New("imports are disabled")
+	ErrImportPathNotAllowed        = errors.New("export path is outside the import root")
 )
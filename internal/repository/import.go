@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+)
+
+type ImportRepository interface {
+	CreateImportJob(ctx context.Context, job *domain.ImportJob) error
+	GetImportJobById(ctx context.Context, id uint) (*domain.ImportJob, error)
+	UpdateImportJob(ctx context.Context, job *domain.ImportJob) error
+	FailRunningImportJobs(ctx context.Context, message string) (int64, error)
+	IsMessageImported(ctx context.Context, source domain.ImportSource, externalChatId string, externalMessageId int64, part int) (bool, error)
+	CreateImportedMessage(ctx context.Context, message *domain.Message, imported *domain.ImportedMessage) error
+}
+
+type importRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (i *importRepository) CreateImportJob(ctx context.Context, job *domain.ImportJob) error {
+	return i.dbWrite.WithContext(ctx).Create(&job).Error
+}
+
+func (i *importRepository) GetImportJobById(ctx context.Context, id uint) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	if err := i.dbRead.WithContext(ctx).First(&job, id).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &job, nil
+}
+
+func (i *importRepository) UpdateImportJob(ctx context.Context, job *domain.ImportJob) error {
+	return i.dbWrite.WithContext(ctx).Model(job).Updates(map[string]any{
+		"status":            job.Status,
+		"total_messages":    job.TotalMessages,
+		"imported_messages": job.ImportedMessages,
+		"skipped_messages":  job.SkippedMessages,
+		"error":             job.Error,
+		"version":           gorm.Expr("version + 1"),
+	}).Error
+}
+
+// FailRunningImportJobs returns how many jobs it marked as failed.
+func (i *importRepository) FailRunningImportJobs(ctx context.Context, message string) (int64, error) {
+	result := i.dbWrite.WithContext(ctx).Model(&domain.ImportJob{}).
+		Where("status = ?", domain.ImportStatusRunning).
+		Updates(map[string]any{
+			"status":  domain.ImportStatusFailed,
+			"error":   message,
+			"version": gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (i *importRepository) IsMessageImported(ctx context.Context, source domain.ImportSource, externalChatId string, externalMessageId int64, part int) (bool, error) {
+	var count int64
+	if err := i.dbRead.WithContext(ctx).
+		Model(&domain.ImportedMessage{}).
+		Where("source = ? AND external_chat_id = ? AND external_message_id = ? AND part = ?", source, externalChatId, externalMessageId, part).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateImportedMessage stores the message and its import record atomically, so
+// a crash between the two can never produce a message that gets imported twice.
+func (i *importRepository) CreateImportedMessage(ctx context.Context, message *domain.Message, imported *domain.ImportedMessage) error {
+	return i.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		imported.MessageId = message.Id
+		return tx.Create(imported).Error
+	})
+}
+
+func NewImportRepository(dbWrite, dbRead *gorm.DB) ImportRepository {
+	return &importRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+)
+
+type IncomingWebhookRepository interface {
+	CreateIncomingWebhook(ctx context.Context, webhook *domain.IncomingWebhook) error
+	GetIncomingWebhookById(ctx context.Context, id uint) (*domain.IncomingWebhook, error)
+	GetIncomingWebhooksByPrivateId(ctx context.Context, privateId uint) ([]domain.IncomingWebhook, error)
+	DeleteIncomingWebhook(ctx context.Context, id uint) error
+}
+
+type incomingWebhookRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (i *incomingWebhookRepository) CreateIncomingWebhook(ctx context.Context, webhook *domain.IncomingWebhook) error {
+	return i.dbWrite.WithContext(ctx).Create(&webhook).Error
+}
+
+func (i *incomingWebhookRepository) GetIncomingWebhookById(ctx context.Context, id uint) (*domain.IncomingWebhook, error) {
+	var webhook domain.IncomingWebhook
+	if err := i.dbRead.WithContext(ctx).First(&webhook, id).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &webhook, nil
+}
+
+func (i *incomingWebhookRepository) GetIncomingWebhooksByPrivateId(ctx context.Context, privateId uint) ([]domain.IncomingWebhook, error) {
+	var webhooks []domain.IncomingWebhook
+	if err := i.dbRead.WithContext(ctx).
+		Where("private_id = ?", privateId).
+		Order("created_at DESC").
+		Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (i *incomingWebhookRepository) DeleteIncomingWebhook(ctx context.Context, id uint) error {
+	return i.dbWrite.WithContext(ctx).Delete(&domain.IncomingWebhook{}, id).Error
+}
+
+func NewIncomingWebhookRepository(dbWrite, dbRead *gorm.DB) IncomingWebhookRepository {
+	return &incomingWebhookRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
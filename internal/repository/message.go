@@ -15,6 +15,7 @@ type MessageRepository interface {
 	GetUndeliveredMessagesByPrivateId(ctx context.Context, privateId uint) ([]domain.Message, error)
 	MarkMessageAsRead(ctx context.Context, id uint) error
 	MarkMessageAsDelivered(ctx context.Context, id uint) error
+	UpdateMessageReplyMarkup(ctx context.Context, id uint, replyMarkup *domain.InlineKeyboardMarkup) error
 }
 
 type messageRepository struct {
@@ -93,6 +94,12 @@ func (m *messageRepository) MarkMessageAsDelivered(ctx context.Context, id uint)
 	return nil
 }
 
+func (m *messageRepository) UpdateMessageReplyMarkup(ctx context.Context, id uint, replyMarkup *domain.InlineKeyboardMarkup) error {
+	return m.dbWrite.WithContext(ctx).Model(&domain.Message{Id: id}).
+		Select("reply_markup").
+		Updates(&domain.Message{ReplyMarkup: replyMarkup}).Error
+}
+
 func NewMessageRepository(dbWrite, dbRead *gorm.DB) MessageRepository {
 	return &messageRepository{
 		dbWrite: dbWrite,
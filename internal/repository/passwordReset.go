@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error
+	UsePasswordReset(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+	DeletePasswordResets(ctx context.Context, userId uint) error
+}
+
+type passwordResetRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (p *passwordResetRepository) CreatePasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
+	return p.dbWrite.WithContext(ctx).Omit("User").Create(reset).Error
+}
+
+// UsePasswordReset marks the token as used and returns it, or
+// ErrRecordNotFound when it doesn't exist, expired or was used before.
+func (p *passwordResetRepository) UsePasswordReset(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	now := time.Now()
+
+	var resets []domain.PasswordReset
+	if err := p.dbWrite.WithContext(ctx).Model(&resets).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now).Error; err != nil {
+		return nil, err
+	}
+
+	if len(resets) == 0 {
+		return nil, ErrRecordNotFound
+	}
+	return &resets[0], nil
+}
+
+func (p *passwordResetRepository) DeletePasswordResets(ctx context.Context, userId uint) error {
+	return p.dbWrite.WithContext(ctx).Where("user_id = ?", userId).Delete(&domain.PasswordReset{}).Error
+}
+
+func NewPasswordResetRepository(dbWrite, dbRead *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PersonalAccessTokenRepository interface {
+	CreatePersonalAccessToken(ctx context.Context, token *domain.PersonalAccessToken) error
+	GetPersonalAccessTokensByUserId(ctx context.Context, userId uint) ([]domain.PersonalAccessToken, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	MarkPersonalAccessTokenUsed(ctx context.Context, id uint, at time.Time, interval time.Duration) error
+	DeletePersonalAccessToken(ctx context.Context, id, userId uint) error
+	DeletePersonalAccessTokensByUserId(ctx context.Context, userId uint) error
+}
+
+type personalAccessTokenRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (p *personalAccessTokenRepository) CreatePersonalAccessToken(ctx context.Context, token *domain.PersonalAccessToken) error {
+	return p.dbWrite.WithContext(ctx).Omit("User").Create(token).Error
+}
+
+func (p *personalAccessTokenRepository) GetPersonalAccessTokensByUserId(ctx context.Context, userId uint) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+	if err := p.dbRead.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (p *personalAccessTokenRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	if err := p.dbRead.WithContext(ctx).Preload("User").Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &token, nil
+}
+
+// MarkPersonalAccessTokenUsed records at as the last use, but at most once per
+// interval so busy scripts don't cost a write per request.
+func (p *personalAccessTokenRepository) MarkPersonalAccessTokenUsed(ctx context.Context, id uint, at time.Time, interval time.Duration) error {
+	return p.dbWrite.WithContext(ctx).
+		Model(&domain.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-interval)).
+		Update("last_used_at", at).Error
+}
+
+func (p *personalAccessTokenRepository) DeletePersonalAccessToken(ctx context.Context, id, userId uint) error {
+	result := p.dbWrite.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userId).
+		Delete(&domain.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (p *personalAccessTokenRepository) DeletePersonalAccessTokensByUserId(ctx context.Context, userId uint) error {
+	return p.dbWrite.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Delete(&domain.PersonalAccessToken{}).Error
+}
+
+func NewPersonalAccessTokenRepository(dbWrite, dbRead *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PrivacyRepository interface {
+	GetPrivacyRules(ctx context.Context, userId uint) ([]domain.PrivacyRule, error)
+	GetPrivacyRulesForUsers(ctx context.Context, userIds []uint, key domain.PrivacyKey) ([]domain.PrivacyRule, error)
+	UpsertPrivacyRule(ctx context.Context, rule *domain.PrivacyRule) error
+}
+
+type privacyRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (p *privacyRepository) GetPrivacyRules(ctx context.Context, userId uint) ([]domain.PrivacyRule, error) {
+	var rules []domain.PrivacyRule
+	if err := p.dbRead.WithContext(ctx).Where("user_id = ?", userId).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetPrivacyRulesForUsers only returns the rules users stored, the ones
+// missing are at their default.
+func (p *privacyRepository) GetPrivacyRulesForUsers(ctx context.Context, userIds []uint, key domain.PrivacyKey) ([]domain.PrivacyRule, error) {
+	var rules []domain.PrivacyRule
+	if len(userIds) == 0 {
+		return rules, nil
+	}
+	if err := p.dbRead.WithContext(ctx).
+		Where("user_id IN ? AND key = ?", userIds, key).
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (p *privacyRepository) UpsertPrivacyRule(ctx context.Context, rule *domain.PrivacyRule) error {
+	return p.dbWrite.WithContext(ctx).
+		Omit("User").
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"level":          rule.Level,
+				"allow_user_ids": clause.Expr{SQL: "excluded.allow_user_ids"},
+				"deny_user_ids":  clause.Expr{SQL: "excluded.deny_user_ids"},
+				"updated_at":     gorm.Expr("now()"),
+				"version":        gorm.Expr("privacy_rules.version + 1"),
+			}),
+		}).
+		Create(rule).Error
+}
+
+func NewPrivacyRepository(dbWrite, dbRead *gorm.DB) PrivacyRepository {
+	return &privacyRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *domain.Session, refreshToken *domain.RefreshToken) error
+	GetSessionById(ctx context.Context, id uint) (*domain.Session, error)
+	GetSessionsByUserId(ctx context.Context, userId uint) ([]domain.Session, error)
+	DeleteSession(ctx context.Context, id, userId uint) error
+	DeleteOtherSessions(ctx context.Context, userId, exceptId uint) ([]uint, error)
+	DeleteAllSessions(ctx context.Context, userId uint) ([]uint, error)
+	DeleteExcessSessions(ctx context.Context, userId uint, platform string, keep int) ([]uint, error)
+
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, old, next *domain.RefreshToken) (bool, error)
+}
+
+type sessionRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+// CreateSession stores the session together with the first token of its
+// family.
+func (s *sessionRepository) CreateSession(ctx context.Context, session *domain.Session, refreshToken *domain.RefreshToken) error {
+	return s.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User").Create(session).Error; err != nil {
+			return err
+		}
+		refreshToken.SessionId = session.Id
+		return tx.Omit("Session").Create(refreshToken).Error
+	})
+}
+
+func (s *sessionRepository) GetSessionById(ctx context.Context, id uint) (*domain.Session, error) {
+	var session domain.Session
+	if err := s.dbRead.WithContext(ctx).Preload("User").First(&session, id).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &session, nil
+}
+
+func (s *sessionRepository) GetSessionsByUserId(ctx context.Context, userId uint) ([]domain.Session, error) {
+	var sessions []domain.Session
+	if err := s.dbRead.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *sessionRepository) DeleteSession(ctx context.Context, id, userId uint) error {
+	result := s.dbWrite.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userId).
+		Delete(&domain.Session{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteOtherSessions returns the ids of the sessions it deleted.
+func (s *sessionRepository) DeleteOtherSessions(ctx context.Context, userId, exceptId uint) ([]uint, error) {
+	var sessions []domain.Session
+	if err := s.dbWrite.WithContext(ctx).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("user_id = ? AND id <> ?", userId, exceptId).
+		Delete(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	sessionIds := make([]uint, len(sessions))
+	for i, session := range sessions {
+		sessionIds[i] = session.Id
+	}
+	return sessionIds, nil
+}
+
+// DeleteAllSessions returns the ids of the sessions it deleted.
+func (s *sessionRepository) DeleteAllSessions(ctx context.Context, userId uint) ([]uint, error) {
+	var sessions []domain.Session
+	if err := s.dbWrite.WithContext(ctx).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("user_id = ?", userId).
+		Delete(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	sessionIds := make([]uint, len(sessions))
+	for i, session := range sessions {
+		sessionIds[i] = session.Id
+	}
+	return sessionIds, nil
+}
+
+// DeleteExcessSessions keeps the user's keep most recently used sessions on
+// platform and returns the ids of the others it deleted.
+func (s *sessionRepository) DeleteExcessSessions(ctx context.Context, userId uint, platform string, keep int) ([]uint, error) {
+	excess := s.dbWrite.Model(&domain.Session{}).
+		Select("id").
+		Where("user_id = ? AND platform = ?", userId, platform).
+		Order("last_used_at DESC, id DESC").
+		Offset(keep)
+
+	var sessions []domain.Session
+	if err := s.dbWrite.WithContext(ctx).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("id IN (?)", excess).
+		Delete(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	sessionIds := make([]uint, len(sessions))
+	for i, session := range sessions {
+		sessionIds[i] = session.Id
+	}
+	return sessionIds, nil
+}
+
+func (s *sessionRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var refreshToken domain.RefreshToken
+	if err := s.dbRead.WithContext(ctx).
+		Preload("Session.User").
+		Where("token_hash = ?", tokenHash).
+		First(&refreshToken).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &refreshToken, nil
+}
+
+// RotateRefreshToken replaces old with next and records the use on old's
+// session. It reports false when old had already been rotated, so of two
+// concurrent refreshes with the same token only one succeeds. Rotated tokens
+// that have expired are no use for spotting reuse anymore and are dropped.
+func (s *sessionRepository) RotateRefreshToken(ctx context.Context, old, next *domain.RefreshToken) (bool, error) {
+	rotated := false
+	err := s.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL", old.Id).
+			Update("rotated_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		next.SessionId = old.SessionId
+		if err := tx.Omit("Session").Create(next).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("session_id = ? AND rotated_at IS NOT NULL AND expires_at < ?", old.SessionId, now).
+			Delete(&domain.RefreshToken{}).Error; err != nil {
+			return err
+		}
+
+		rotated = true
+		return tx.Model(&old.Session).Updates(map[string]any{
+			"user_agent":   old.Session.UserAgent,
+			"ip":           old.Session.IP,
+			"last_used_at": old.Session.LastUsedAt,
+			"version":      gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return rotated, nil
+}
+
+func NewSessionRepository(dbWrite, dbRead *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
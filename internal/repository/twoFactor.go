@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	GetTwoFactor(ctx context.Context, userId uint) (*domain.TwoFactor, error)
+	SaveTwoFactor(ctx context.Context, twoFactor *domain.TwoFactor, recoveryCodes []domain.RecoveryCode) error
+	ConfirmTwoFactor(ctx context.Context, userId uint, step int64) (bool, error)
+	UseTwoFactorStep(ctx context.Context, userId uint, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userId uint, codeHash string) (bool, error)
+	DeleteTwoFactor(ctx context.Context, userId uint) error
+}
+
+type twoFactorRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (t *twoFactorRepository) GetTwoFactor(ctx context.Context, userId uint) (*domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+	if err := t.dbRead.WithContext(ctx).Where("user_id = ?", userId).First(&twoFactor).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &twoFactor, nil
+}
+
+// SaveTwoFactor starts a new, unconfirmed enrollment, replacing an earlier
+// unconfirmed one together with its recovery codes.
+func (t *twoFactorRepository) SaveTwoFactor(ctx context.Context, twoFactor *domain.TwoFactor, recoveryCodes []domain.RecoveryCode) error {
+	return t.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", twoFactor.UserId).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("User").Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"secret":         twoFactor.Secret,
+				"confirmed_at":   nil,
+				"last_used_step": 0,
+				"updated_at":     time.Now(),
+				"version":        gorm.Expr("two_factors.version + 1"),
+			}),
+		}).Create(twoFactor).Error; err != nil {
+			return err
+		}
+
+		if len(recoveryCodes) == 0 {
+			return nil
+		}
+		return tx.Omit("User").Create(&recoveryCodes).Error
+	})
+}
+
+// ConfirmTwoFactor enables an unconfirmed enrollment and reports false when
+// there was none.
+func (t *twoFactorRepository) ConfirmTwoFactor(ctx context.Context, userId uint, step int64) (bool, error) {
+	result := t.dbWrite.WithContext(ctx).Model(&domain.TwoFactor{}).
+		Where("user_id = ? AND confirmed_at IS NULL", userId).
+		Updates(map[string]any{
+			"confirmed_at":   time.Now(),
+			"last_used_step": step,
+			"version":        gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UseTwoFactorStep reports false when a code of step or a later one was
+// already used, so every code only works once.
+func (t *twoFactorRepository) UseTwoFactorStep(ctx context.Context, userId uint, step int64) (bool, error) {
+	result := t.dbWrite.WithContext(ctx).Model(&domain.TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userId, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (t *twoFactorRepository) UseRecoveryCode(ctx context.Context, userId uint, codeHash string) (bool, error) {
+	result := t.dbWrite.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (t *twoFactorRepository) DeleteTwoFactor(ctx context.Context, userId uint) error {
+	return t.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userId).Delete(&domain.TwoFactor{}).Error
+	})
+}
+
+func NewTwoFactorRepository(dbWrite, dbRead *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDB connects to the Postgres database in TEST_DATABASE_DSN, skipping
+// the test when none is given. Tests clean up the rows they create.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		t.Fatalf("failed to create pg_trgm extension: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.User{}, &domain.TwoFactor{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return db
+}
+
+func TestUseTwoFactorStepRejectsReuse(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	user := &domain.User{
+		Name:     "Two Factor",
+		Email:    fmt.Sprintf("two-factor-%d@example.com", time.Now().UnixNano()),
+		Password: "x",
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("user_id = ?", user.Id).Delete(&domain.TwoFactor{})
+		db.Delete(user)
+	})
+
+	now := time.Now()
+	if err := db.Create(&domain.TwoFactor{UserId: user.Id, Secret: "secret", ConfirmedAt: &now}).Error; err != nil {
+		t.Fatalf("failed to create two-factor settings: %v", err)
+	}
+
+	repo := NewTwoFactorRepository(db, db)
+	for _, attempt := range []struct {
+		step int64
+		want bool
+	}{
+		{100, true},
+		{100, false}, // the same code again
+		{99, false},  // an earlier code still within the skew window
+		{101, true},
+	} {
+		used, err := repo.UseTwoFactorStep(ctx, user.Id, attempt.step)
+		if err != nil {
+			t.Fatalf("failed to use step %d: %v", attempt.step, err)
+		}
+		if used != attempt.want {
+			t.Errorf("step %d got %t, want %t", attempt.step, used, attempt.want)
+		}
+	}
+}
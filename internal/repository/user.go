@@ -3,9 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
 	"time"
 )
 
@@ -13,12 +14,19 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *domain.User) error
 	GetUserById(ctx context.Context, id uint) (*domain.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetUsersByEmails(ctx context.Context, emails []string) ([]domain.User, error)
 	UpdateUser(ctx context.Context, user *domain.User) error
+	UpdateLastSeen(ctx context.Context, lastSeen map[uint]time.Time) error
+	UpdatePassword(ctx context.Context, userId uint, password string) error
+	MarkEmailVerified(ctx context.Context, userId uint, email string) (bool, error)
+	ClaimVerificationSend(ctx context.Context, userId uint, cooldown time.Duration) (bool, error)
 	DeleteUser(ctx context.Context, id uint) error
 
-	GetUserByRefreshToken(ctx context.Context, refreshToken string, platform string) (*domain.User, error)
-	UpdateRefreshToken(ctx context.Context, userId uint, refreshToken string, platform string) error
-	DeleteRefreshToken(ctx context.Context, userId uint, platform string) error
+	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	CheckUsernameExists(ctx context.Context, username string, exceptUserId uint) (bool, error)
+	UpdateUsername(ctx context.Context, user *domain.User, change *domain.UsernameChange) error
+	GetUsernameChanges(ctx context.Context, userId uint) ([]domain.UsernameChange, error)
+	SearchUsers(ctx context.Context, query string, offset, limit int) ([]domain.User, int64, error)
 }
 
 type userRepository struct {
@@ -56,23 +64,99 @@ func (u *userRepository) GetUserByEmail(ctx context.Context, email string) (*dom
 	return &user, nil
 }
 
+// UpdateLastSeen stores the last seen time of many users in one statement.
+func (u *userRepository) UpdateLastSeen(ctx context.Context, lastSeen map[uint]time.Time) error {
+	if len(lastSeen) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(lastSeen))
+	args := make([]any, 0, len(lastSeen)*2)
+	for userId, at := range lastSeen {
+		values = append(values, "(?::bigint, ?::timestamptz)")
+		args = append(args, userId, at)
+	}
+
+	return u.dbWrite.WithContext(ctx).Exec(
+		"UPDATE users SET last_seen_at = v.last_seen_at FROM (VALUES "+strings.Join(values, ", ")+") AS v(id, last_seen_at) WHERE users.id = v.id",
+		args...,
+	).Error
+}
+
+// GetUsersByEmails matches emails case-insensitively and never returns bot
+// accounts.
+func (u *userRepository) GetUsersByEmails(ctx context.Context, emails []string) ([]domain.User, error) {
+	var users []domain.User
+	if len(emails) == 0 {
+		return users, nil
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	if err := u.dbRead.WithContext(ctx).
+		Where("lower(email) IN ? AND type = ?", lowered, domain.UserTypeUser).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateUser saves the profile fields of user. Credentials have their own
+// update methods so a profile edit can't overwrite a concurrent login.
 func (u *userRepository) UpdateUser(ctx context.Context, user *domain.User) error {
-	return u.dbWrite.WithContext(ctx).Save(&user).Error
+	return u.dbWrite.WithContext(ctx).Model(user).Updates(map[string]any{
+		"name":       user.Name,
+		"bio":        user.Bio,
+		"avatar_url": user.AvatarURL,
+		"version":    gorm.Expr("version + 1"),
+	}).Error
 }
 
-func (u *userRepository) DeleteUser(ctx context.Context, id uint) error {
-	return u.dbWrite.WithContext(ctx).Delete(&domain.User{}, id).Error
+func (u *userRepository) UpdatePassword(ctx context.Context, userId uint, password string) error {
+	return u.dbWrite.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userId).Updates(map[string]any{
+		"password": password,
+		"version":  gorm.Expr("version + 1"),
+	}).Error
 }
 
-func (u *userRepository) GetUserByRefreshToken(ctx context.Context, refreshToken string, platform string) (*domain.User, error) {
-	var user domain.User
+// MarkEmailVerified reports false when the user's email is no longer email,
+// so a token sent to an old address can't verify a new one.
+func (u *userRepository) MarkEmailVerified(ctx context.Context, userId uint, email string) (bool, error) {
+	result := u.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND email = ?", userId, email).
+		Updates(map[string]any{
+			"email_verified_at": gorm.Expr("COALESCE(email_verified_at, ?)", time.Now()),
+			"version":           gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
 
-	condition := u.buildRefreshTokenCondition(platform, refreshToken)
-	if condition == nil {
-		return nil, errors.New("invalid platform")
+// ClaimVerificationSend records that a verification email is about to be sent
+// and reports false when the previous one was sent less than cooldown ago.
+func (u *userRepository) ClaimVerificationSend(ctx context.Context, userId uint, cooldown time.Duration) (bool, error) {
+	now := time.Now()
+	result := u.dbWrite.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at <= ?)", userId, now.Add(-cooldown)).
+		Update("verification_sent_at", now)
+	if result.Error != nil {
+		return false, result.Error
 	}
+	return result.RowsAffected > 0, nil
+}
 
-	if err := u.dbRead.WithContext(ctx).Where(condition).First(&user).Error; err != nil {
+func (u *userRepository) DeleteUser(ctx context.Context, id uint) error {
+	return u.dbWrite.WithContext(ctx).Delete(&domain.User{}, id).Error
+}
+
+func (u *userRepository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	var user domain.User
+	if err := u.dbRead.WithContext(ctx).Where("lower(username) = lower(?)", username).First(&user).Error; err != nil {
 		switch {
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			return nil, ErrRecordNotFound
@@ -80,76 +164,73 @@ func (u *userRepository) GetUserByRefreshToken(ctx context.Context, refreshToken
 			return nil, err
 		}
 	}
-
 	return &user, nil
 }
 
-func (u *userRepository) UpdateRefreshToken(ctx context.Context, userId uint, refreshToken string, platform string) error {
-	updates := u.buildRefreshTokenUpdate(platform, refreshToken)
-	if updates == nil {
-		return errors.New("invalid platform")
+func (u *userRepository) CheckUsernameExists(ctx context.Context, username string, exceptUserId uint) (bool, error) {
+	var count int64
+	if err := u.dbRead.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("lower(username) = lower(?) AND id <> ?", username, exceptUserId).
+		Count(&count).Error; err != nil {
+		return false, err
 	}
-	updates["version"] = gorm.Expr("version + 1")
-
-	return u.dbWrite.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userId).Updates(updates).Error
+	return count > 0, nil
 }
 
-func (u *userRepository) DeleteRefreshToken(ctx context.Context, userId uint, platform string) error {
-	updates := u.buildRefreshTokenDeletion(platform)
-	if updates == nil {
-		return fmt.Errorf("invalid platform: %s", platform)
-	}
-
-	updates["version"] = gorm.Expr("version + 1")
-
-	return u.dbWrite.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userId).Updates(updates).Error
+// UpdateUsername stores the new username and its history entry together.
+func (u *userRepository) UpdateUsername(ctx context.Context, user *domain.User, change *domain.UsernameChange) error {
+	return u.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Updates(map[string]any{
+			"username": user.Username,
+			"version":  gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			// Lost a race for the username with a concurrent change
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrUsernameExists
+			}
+			return err
+		}
+		return tx.Create(change).Error
+	})
 }
 
-func (u *userRepository) buildRefreshTokenCondition(platform string, refreshToken string) map[string]any {
-	if platform == "web" {
-		return map[string]any{
-			"refresh_token_web": refreshToken,
-		}
+func (u *userRepository) GetUsernameChanges(ctx context.Context, userId uint) ([]domain.UsernameChange, error) {
+	var changes []domain.UsernameChange
+	if err := u.dbRead.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Find(&changes).Error; err != nil {
+		return nil, err
 	}
-	if platform == "mobile" {
-		return map[string]any{
-			"refresh_token_mobile": refreshToken,
-		}
-	}
-	return nil
+	return changes, nil
 }
 
-func (u *userRepository) buildRefreshTokenUpdate(platform string, refreshToken string) map[string]any {
-	if platform == "web" {
-		return map[string]any{
-			"refresh_token_web":    refreshToken,
-			"refresh_token_web_at": time.Now(),
-		}
-	}
-	if platform == "mobile" {
-		return map[string]any{
-			"refresh_token_mobile":    refreshToken,
-			"refresh_token_mobile_at": time.Now(),
-		}
-	}
+// SearchUsers matches usernames starting with query as well as similar ones,
+// using the trigram index. Exact and prefix matches are ranked first.
+func (u *userRepository) SearchUsers(ctx context.Context, query string, offset, limit int) ([]domain.User, int64, error) {
+	prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(query)) + "%"
 
-	return nil
-}
+	db := u.dbRead.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("username IS NOT NULL").
+		Where("lower(username) LIKE ? OR lower(username) % ?", prefix, strings.ToLower(query)).
+		Session(&gorm.Session{})
 
-func (u *userRepository) buildRefreshTokenDeletion(platform string) map[string]any {
-	if platform == "web" {
-		return map[string]any{
-			"refresh_token_web":    nil,
-			"refresh_token_web_at": nil,
-		}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	if platform == "mobile" {
-		return map[string]any{
-			"refresh_token_mobile":    nil,
-			"refresh_token_mobile_at": nil,
-		}
+
+	var users []domain.User
+	if err := db.
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "lower(username) = ? DESC, lower(username) LIKE ? DESC, similarity(lower(username), ?) DESC, lower(username)", Vars: []any{strings.ToLower(query), prefix, strings.ToLower(query)}}}).
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
 	}
-	return nil
+	return users, total, nil
 }
 
 func NewUserRepository(dbWrite, dbRead *gorm.DB) UserRepository {
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository interface {
+	GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) error
+	CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error
+}
+
+type userIdentityRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (u *userIdentityRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	if err := u.dbRead.WithContext(ctx).Preload("User").Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &identity, nil
+}
+
+func (u *userIdentityRepository) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	return u.dbWrite.WithContext(ctx).Omit("User").Create(identity).Error
+}
+
+func (u *userIdentityRepository) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	return u.dbWrite.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		identity.UserId = user.Id
+		return tx.Omit("User").Create(identity).Error
+	})
+}
+
+func NewUserIdentityRepository(dbWrite, dbRead *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
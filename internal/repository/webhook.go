@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	GetWebhookById(ctx context.Context, id uint) (*domain.Webhook, error)
+	GetWebhooksByUserId(ctx context.Context, userId uint) ([]domain.Webhook, error)
+	GetActiveWebhooksForEvent(ctx context.Context, userIds []uint, privateId uint, event domain.WebhookEvent) ([]domain.Webhook, error)
+	EnableWebhook(ctx context.Context, id uint) error
+	RecordWebhookSuccess(ctx context.Context, id uint) error
+	RecordWebhookFailure(ctx context.Context, id uint, maxFailures int) error
+	DeleteWebhook(ctx context.Context, id uint) error
+
+	CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context, webhookId uint, offset, limit int) ([]domain.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	dbWrite *gorm.DB
+	dbRead  *gorm.DB
+}
+
+func (w *webhookRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	return w.dbWrite.WithContext(ctx).Create(&webhook).Error
+}
+
+func (w *webhookRepository) GetWebhookById(ctx context.Context, id uint) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	if err := w.dbRead.WithContext(ctx).First(&webhook, id).Error; err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &webhook, nil
+}
+
+func (w *webhookRepository) GetWebhooksByUserId(ctx context.Context, userId uint) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	if err := w.dbRead.WithContext(ctx).
+		Where("user_id = ?", userId).
+		Order("created_at DESC").
+		Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (w *webhookRepository) GetActiveWebhooksForEvent(ctx context.Context, userIds []uint, privateId uint, event domain.WebhookEvent) ([]domain.Webhook, error) {
+	events, err := json.Marshal([]domain.WebhookEvent{event})
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []domain.Webhook
+	if err := w.dbRead.WithContext(ctx).
+		Where("user_id IN ? AND active = ?", userIds, true).
+		Where("private_id IS NULL OR private_id = ?", privateId).
+		Where("events @> ?", string(events)).
+		Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (w *webhookRepository) EnableWebhook(ctx context.Context, id uint) error {
+	return w.dbWrite.WithContext(ctx).Model(&domain.Webhook{}).Where("id = ?", id).Updates(map[string]any{
+		"active":        true,
+		"failure_count": 0,
+		"version":       gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (w *webhookRepository) RecordWebhookSuccess(ctx context.Context, id uint) error {
+	return w.dbWrite.WithContext(ctx).Model(&domain.Webhook{}).
+		Where("id = ? AND failure_count > 0", id).
+		Update("failure_count", 0).Error
+}
+
+// RecordWebhookFailure counts a delivery that failed all of its attempts and
+// disables the webhook in the same statement once maxFailures is reached.
+func (w *webhookRepository) RecordWebhookFailure(ctx context.Context, id uint, maxFailures int) error {
+	return w.dbWrite.WithContext(ctx).Model(&domain.Webhook{}).Where("id = ?", id).Updates(map[string]any{
+		"failure_count": gorm.Expr("failure_count + 1"),
+		"active":        gorm.Expr("CASE WHEN failure_count + 1 >= ? THEN false ELSE active END", maxFailures),
+		"version":       gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (w *webhookRepository) DeleteWebhook(ctx context.Context, id uint) error {
+	return w.dbWrite.WithContext(ctx).Delete(&domain.Webhook{}, id).Error
+}
+
+func (w *webhookRepository) CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return w.dbWrite.WithContext(ctx).Create(&delivery).Error
+}
+
+func (w *webhookRepository) GetWebhookDeliveries(ctx context.Context, webhookId uint, offset, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	if err := w.dbRead.WithContext(ctx).
+		Where("webhook_id = ?", webhookId).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func NewWebhookRepository(dbWrite, dbRead *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		dbWrite: dbWrite,
+		dbRead:  dbRead,
+	}
+}
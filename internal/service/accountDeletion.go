@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/filestore"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/revocation"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"time"
+)
+
+const accountDeletionBatchSize = 100
+
+// AccountDeletionService lets users delete their account. Nothing happens
+// until the grace period is over, then the account is anonymized: the user
+// row stays as "Deleted Account" so the other side of a conversation keeps
+// its history, everything else about the user goes.
+type AccountDeletionService interface {
+	ScheduleDeletion(ctx context.Context, userId uint, input *dto.DeleteAccountRequest) (*dto.AccountDeletionResponse, error)
+	CancelDeletion(ctx context.Context, userId uint) error
+	DeleteDueAccounts(ctx context.Context) error
+}
+
+type accountDeletionService struct {
+	accountDeletionRepository repository.AccountDeletionRepository
+	userRepository            repository.UserRepository
+	privateRepository         repository.PrivateRepository
+	revocationStore           revocation.Store
+	fileStore                 filestore.FileStore
+	logger                    utils.LoggerStrategy
+	cfg                       *config.Config
+}
+
+func (a *accountDeletionService) ScheduleDeletion(ctx context.Context, userId uint, input *dto.DeleteAccountRequest) (*dto.AccountDeletionResponse, error) {
+	user, err := a.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !utils.CheckPasswordHash(user.Password, input.Password) {
+		return nil, repository.ErrIncorrectPassword
+	}
+
+	if user.DeletionDueAt != nil {
+		return nil, repository.ErrDeletionAlreadyScheduled
+	}
+
+	dueAt := time.Now().Add(a.cfg.AccountDeletion.GracePeriod)
+	if err := a.accountDeletionRepository.ScheduleDeletion(ctx, userId, dueAt); err != nil {
+		return nil, fmt.Errorf("failed to schedule account deletion: %w", err)
+	}
+
+	return &dto.AccountDeletionResponse{
+		DeletionDueAt: dueAt,
+	}, nil
+}
+
+func (a *accountDeletionService) CancelDeletion(ctx context.Context, userId uint) error {
+	cancelled, err := a.accountDeletionRepository.CancelDeletion(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to cancel account deletion: %w", err)
+	}
+	if !cancelled {
+		return repository.ErrDeletionNotScheduled
+	}
+	return nil
+}
+
+// DeleteDueAccounts anonymizes every account whose grace period is over. A
+// failing account is logged and retried on the next run.
+func (a *accountDeletionService) DeleteDueAccounts(ctx context.Context) error {
+	for {
+		users, err := a.accountDeletionRepository.GetUsersDueForDeletion(ctx, time.Now(), accountDeletionBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get accounts due for deletion: %w", err)
+		}
+
+		deleted := 0
+		for _, user := range users {
+			if err := a.deleteAccount(ctx, user.Id); err != nil {
+				a.logger.Error("failed to delete account", "user_id", user.Id, "error", err)
+				continue
+			}
+			deleted++
+		}
+
+		// Stop when a whole batch failed, those would be fetched over again
+		if len(users) < accountDeletionBatchSize || deleted == 0 {
+			return nil
+		}
+	}
+}
+
+func (a *accountDeletionService) deleteAccount(ctx context.Context, userId uint) error {
+	privates, err := a.privateRepository.GetPrivatesForUser(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to get privates: %w", err)
+	}
+
+	sessionIds, err := a.accountDeletionRepository.AnonymizeUser(ctx, userId, a.cfg.AccountDeletion.Messages == "purge")
+	if err != nil {
+		// Cancelled since it was fetched
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to anonymize account: %w", err)
+	}
+
+	deadline := time.Now().Add(a.cfg.MaxAccessTokenExpires())
+	for _, sessionId := range sessionIds {
+		a.revocationStore.RevokeSession(sessionId, deadline)
+	}
+
+	// The account is gone already, leftover files are only logged
+	keys := []string{fmt.Sprintf("avatars/%d", userId)}
+	for _, private := range privates {
+		keys = append(keys, fmt.Sprintf("chats/%d/%d", private.Id, userId))
+	}
+	for _, key := range keys {
+		if err := a.fileStore.Delete(key); err != nil {
+			a.logger.Error("failed to delete uploads of deleted account", "user_id", userId, "key", key, "error", err)
+		}
+	}
+
+	a.logger.Info("account deleted", "user_id", userId, "sessions", len(sessionIds))
+	return nil
+}
+
+func (a *accountDeletionService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := a.DeleteDueAccounts(context.Background()); err != nil {
+			a.logger.Error("failed to delete due accounts", "error", err)
+		}
+	}
+}
+
+func NewAccountDeletionService(accountDeletionRepository repository.AccountDeletionRepository, userRepository repository.UserRepository, privateRepository repository.PrivateRepository, revocationStore revocation.Store, fileStore filestore.FileStore, logger utils.LoggerStrategy, cfg *config.Config) AccountDeletionService {
+	a := &accountDeletionService{
+		accountDeletionRepository: accountDeletionRepository,
+		userRepository:            userRepository,
+		privateRepository:         privateRepository,
+		revocationStore:           revocationStore,
+		fileStore:                 fileStore,
+		logger:                    logger,
+		cfg:                       cfg,
+	}
+
+	go a.run(cfg.AccountDeletion.CheckInterval)
+	return a
+}
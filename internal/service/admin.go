@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/revocation"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"slices"
+	"time"
+)
+
+// AdminService is the moderation side of the server. Moderators can only act
+// against users whose role is below their own; the admins listed in
+// config.Admin always count as admins and outrank every other one, so they
+// can't be locked out.
+type AdminService interface {
+	GetRole(ctx context.Context, userId uint) (domain.Role, error)
+	GetUsers(ctx context.Context, query string, role domain.Role, status domain.AccountStatus, page, limit int) ([]dto.AdminUserResponse, int64, error)
+	GetUser(ctx context.Context, userId uint) (*dto.AdminUserResponse, error)
+	SetRole(ctx context.Context, adminId, userId uint, input *dto.SetRoleRequest) error
+	SuspendUser(ctx context.Context, moderatorId, userId uint, input *dto.SuspendUserRequest) ([]uint, error)
+	UnsuspendUser(ctx context.Context, moderatorId, userId uint) error
+	ForceLogout(ctx context.Context, moderatorId, userId uint) ([]uint, error)
+	GetStats(ctx context.Context) (*domain.ServerStats, error)
+}
+
+type adminService struct {
+	adminRepository               repository.AdminRepository
+	userRepository                repository.UserRepository
+	sessionRepository             repository.SessionRepository
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	auditLogRepository            repository.AuditLogRepository
+	revocationStore               revocation.Store
+	logger                        utils.LoggerStrategy
+	cfg                           *config.Config
+}
+
+func (a *adminService) GetRole(ctx context.Context, userId uint) (domain.Role, error) {
+	if a.isConfigAdmin(userId) {
+		return domain.RoleAdmin, nil
+	}
+
+	user, err := a.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	return user.Role, nil
+}
+
+func (a *adminService) GetUsers(ctx context.Context, query string, role domain.Role, status domain.AccountStatus, page, limit int) ([]dto.AdminUserResponse, int64, error) {
+	now := time.Now()
+	users, total, err := a.adminRepository.GetUsers(ctx, query, role, status, now, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	responses := make([]dto.AdminUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *a.toAdminUserResponse(&user, now)
+	}
+	return responses, total, nil
+}
+
+func (a *adminService) GetUser(ctx context.Context, userId uint) (*dto.AdminUserResponse, error) {
+	user, err := a.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return a.toAdminUserResponse(user, time.Now()), nil
+}
+
+func (a *adminService) SetRole(ctx context.Context, adminId, userId uint, input *dto.SetRoleRequest) error {
+	user, err := a.checkModeration(ctx, adminId, userId)
+	if err != nil {
+		return err
+	}
+
+	role := domain.Role(input.Role)
+	if err := a.adminRepository.SetRole(ctx, userId, role); err != nil {
+		return err
+	}
+
+	a.audit(ctx, userId, domain.AuditRoleChanged, fmt.Sprintf("by %d: %s to %s", adminId, user.Role, role))
+	return nil
+}
+
+// SuspendUser also ends all of the user's sessions, it returns their ids.
+func (a *adminService) SuspendUser(ctx context.Context, moderatorId, userId uint, input *dto.SuspendUserRequest) ([]uint, error) {
+	if _, err := a.checkModeration(ctx, moderatorId, userId); err != nil {
+		return nil, err
+	}
+
+	if err := a.adminRepository.SuspendUser(ctx, userId, time.Now(), input.Until, input.Reason); err != nil {
+		return nil, err
+	}
+
+	until := "further notice"
+	if input.Until != nil {
+		until = input.Until.UTC().Format(time.RFC3339)
+	}
+	a.audit(ctx, userId, domain.AuditUserSuspended, fmt.Sprintf("by %d until %s: %s", moderatorId, until, input.Reason))
+
+	return a.revokeSessions(ctx, userId)
+}
+
+func (a *adminService) UnsuspendUser(ctx context.Context, moderatorId, userId uint) error {
+	if _, err := a.checkModeration(ctx, moderatorId, userId); err != nil {
+		return err
+	}
+
+	unsuspended, err := a.adminRepository.UnsuspendUser(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to lift suspension: %w", err)
+	}
+	if !unsuspended {
+		return repository.ErrNotSuspended
+	}
+
+	a.audit(ctx, userId, domain.AuditUserUnsuspended, fmt.Sprintf("by %d", moderatorId))
+	return nil
+}
+
+// ForceLogout ends every session and personal access token of the user and
+// returns the ids of the sessions it ended. The user can log in again right
+// away.
+func (a *adminService) ForceLogout(ctx context.Context, moderatorId, userId uint) ([]uint, error) {
+	if _, err := a.checkModeration(ctx, moderatorId, userId); err != nil {
+		return nil, err
+	}
+
+	sessionIds, err := a.revokeSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.personalAccessTokenRepository.DeletePersonalAccessTokensByUserId(ctx, userId); err != nil {
+		return nil, fmt.Errorf("failed to delete personal access tokens: %w", err)
+	}
+
+	a.audit(ctx, userId, domain.AuditUserLoggedOut, fmt.Sprintf("by %d", moderatorId))
+	return sessionIds, nil
+}
+
+func (a *adminService) GetStats(ctx context.Context) (*domain.ServerStats, error) {
+	stats, err := a.adminRepository.GetStats(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server stats: %w", err)
+	}
+	return stats, nil
+}
+
+// checkModeration returns the user moderatorId wants to act against, once
+// it's made sure they may.
+func (a *adminService) checkModeration(ctx context.Context, moderatorId, userId uint) (*domain.User, error) {
+	user, err := a.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBot() || user.IsDeleted() {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	if moderatorId == userId || a.isConfigAdmin(userId) {
+		return nil, repository.ErrModerationDenied
+	}
+	if a.isConfigAdmin(moderatorId) {
+		return user, nil
+	}
+
+	role, err := a.GetRole(ctx, moderatorId)
+	if err != nil {
+		return nil, err
+	}
+	if role == user.Role || !role.AtLeast(user.Role) {
+		return nil, repository.ErrModerationDenied
+	}
+	return user, nil
+}
+
+func (a *adminService) revokeSessions(ctx context.Context, userId uint) ([]uint, error) {
+	sessionIds, err := a.sessionRepository.DeleteAllSessions(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	deadline := time.Now().Add(a.cfg.MaxAccessTokenExpires())
+	for _, sessionId := range sessionIds {
+		a.revocationStore.RevokeSession(sessionId, deadline)
+	}
+	return sessionIds, nil
+}
+
+func (a *adminService) audit(ctx context.Context, userId uint, action domain.AuditAction, detail string) {
+	if err := a.auditLogRepository.CreateAuditLog(ctx, &domain.AuditLog{
+		UserId: &userId,
+		Action: action,
+		Detail: detail,
+	}); err != nil {
+		a.logger.Error("failed to write audit log", "action", action, "error", err)
+	}
+}
+
+func (a *adminService) isConfigAdmin(userId uint) bool {
+	return slices.Contains(a.cfg.Admin.UserIds, userId)
+}
+
+func (a *adminService) toAdminUserResponse(user *domain.User, now time.Time) *dto.AdminUserResponse {
+	response := &dto.AdminUserResponse{
+		Id:               user.Id,
+		Type:             string(user.Type),
+		Role:             string(user.Role),
+		Status:           string(user.Status(now)),
+		Name:             user.Name,
+		Email:            user.Email,
+		EmailVerified:    user.IsEmailVerified(),
+		SuspensionReason: user.SuspensionReason,
+		DeletionDueAt:    user.DeletionDueAt,
+		LastSeenAt:       user.LastSeenAt,
+		CreatedAt:        user.CreatedAt,
+	}
+	if a.isConfigAdmin(user.Id) {
+		response.Role = string(domain.RoleAdmin)
+	}
+	if user.Username != nil {
+		response.Username = *user.Username
+	}
+	if user.IsSuspended(now) {
+		response.SuspendedAt = user.SuspendedAt
+		response.SuspendedUntil = user.SuspendedUntil
+	} else {
+		response.SuspensionReason = ""
+	}
+	return response
+}
+
+func NewAdminService(adminRepository repository.AdminRepository, userRepository repository.UserRepository, sessionRepository repository.SessionRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository, auditLogRepository repository.AuditLogRepository, revocationStore revocation.Store, logger utils.LoggerStrategy, cfg *config.Config) AdminService {
+	return &adminService{
+		adminRepository:               adminRepository,
+		userRepository:                userRepository,
+		sessionRepository:             sessionRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		auditLogRepository:            auditLogRepository,
+		revocationStore:               revocationStore,
+		logger:                        logger,
+		cfg:                           cfg,
+	}
+}
@@ -5,26 +5,63 @@ import (
 	"errors"
 	"fmt"
 	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/mailer"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/revocation"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/throttle"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 )
 
 type AuthService interface {
-	Register(ctx context.Context, input *dto.RegisterRequest) (*dto.RegisterResponse, error)
-	Login(ctx context.Context, input *dto.LoginRequest, platform string) (*dto.LoginResponse, error)
-	Logout(ctx context.Context, userId uint, platform string) error
+	Register(ctx context.Context, input *dto.RegisterRequest, ip string) (*dto.RegisterResponse, error)
+	Login(ctx context.Context, input *dto.LoginRequest, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error)
+	LoginTwoFactor(ctx context.Context, input *dto.LoginTwoFactorRequest, device *dto.DeviceInfo) (*dto.LoginResponse, error)
+	LoginUser(ctx context.Context, user *domain.User, deviceName string, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error)
+	LoginLinkedDevice(ctx context.Context, userId uint, deviceName string, device *dto.DeviceInfo) (*dto.LoginResponse, error)
+	Logout(ctx context.Context, userId, sessionId uint, tokenId string) error
 	GetUserByRefreshToken(ctx context.Context, input *dto.RefreshTokenRequest, platform string) (*dto.UserResponse, error)
-	RefreshToken(ctx context.Context, input *dto.RefreshTokenRequest, platform string) (*dto.RefreshTokenResponse, error)
+	RefreshToken(ctx context.Context, input *dto.RefreshTokenRequest, device *dto.DeviceInfo) (*dto.RefreshTokenResponse, error)
+
+	GetSessions(ctx context.Context, userId, currentSessionId uint) ([]dto.SessionResponse, error)
+	RevokeSession(ctx context.Context, userId, sessionId uint) error
+	RevokeOtherSessions(ctx context.Context, userId, currentSessionId uint) ([]uint, error)
+
+	VerifyEmail(ctx context.Context, input *dto.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, userId uint) error
+
+	ForgotPassword(ctx context.Context, input *dto.ForgotPasswordRequest, ip string) error
+	ResetPassword(ctx context.Context, input *dto.ResetPasswordRequest) (uint, []uint, error)
+	ChangePassword(ctx context.Context, userId, currentSessionId uint, input *dto.ChangePasswordRequest) ([]uint, error)
 }
 
 type authService struct {
-	userRepository repository.UserRepository
-	cfg            *config.Config
+	userRepository                repository.UserRepository
+	sessionRepository             repository.SessionRepository
+	passwordResetRepository       repository.PasswordResetRepository
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	twoFactorService              TwoFactorService
+	revocationStore               revocation.Store
+	tokenKeys                     utils.TokenKeys
+	mailer                        mailer.Mailer
+	loginGuard                    *loginGuard
+	signupLimiter                 *utils.RateLimiter
+	resetEmailLimiter             *utils.RateLimiter
+	resetIPLimiter                *utils.RateLimiter
+	logger                        utils.LoggerStrategy
+	cfg                           *config.Config
 }
 
-func (a *authService) Register(ctx context.Context, input *dto.RegisterRequest) (*dto.RegisterResponse, error) {
+func (a *authService) Register(ctx context.Context, input *dto.RegisterRequest, ip string) (*dto.RegisterResponse, error) {
+	if !a.signupLimiter.Allow(ip) {
+		return nil, repository.ErrSignupRateLimited
+	}
+
 	if _, err := a.userRepository.GetUserByEmail(ctx, input.Email); err == nil {
 		return nil, repository.ErrEmailExists
 	}
@@ -38,75 +75,506 @@ func (a *authService) Register(ctx context.Context, input *dto.RegisterRequest)
 		return nil, err
 	}
 
+	// The account exists either way, the user can ask for another email
+	if err := a.sendVerification(ctx, user); err != nil {
+		a.logger.Error("failed to send verification email", "user", user.Id, "error", err)
+	}
+
 	return a.toRegisterResponse(user), nil
 }
 
-func (a *authService) Login(ctx context.Context, input *dto.LoginRequest, platform string) (*dto.LoginResponse, error) {
+// Login starts a new session for the device, leaving the user's other
+// sessions untouched. Users with two-factor authentication get a challenge
+// instead, the session starts once LoginTwoFactor gets a valid code.
+//
+// Failed attempts are throttled per account and per IP. Unknown emails are
+// still compared against a password hash, so response times don't tell
+// which emails are registered.
+func (a *authService) Login(ctx context.Context, input *dto.LoginRequest, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error) {
+	if err := a.loginGuard.check(input.Email, device.IP); err != nil {
+		return nil, nil, err
+	}
+
 	user, err := a.userRepository.GetUserByEmail(ctx, input.Email)
-	if err != nil {
-		switch {
-		case errors.Is(err, repository.ErrRecordNotFound):
-			return nil, errors.New("invalid credentials")
-		default:
-			return nil, err
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	if user == nil {
+		utils.CheckDummyPassword(input.Password)
+		a.loginGuard.fail(ctx, input.Email, device.IP, nil)
+		return nil, nil, repository.ErrInvalidCredentials
+	}
+
+	if !utils.CheckPasswordHash(user.Password, input.Password) || user.IsBot() {
+		a.loginGuard.fail(ctx, input.Email, device.IP, &user.Id)
+		return nil, nil, repository.ErrInvalidCredentials
+	}
+	a.loginGuard.succeed(input.Email)
+
+	// Users who may not log in unverified can't ask for another email
+	// themselves, so logging in sends one
+	if !user.IsEmailVerified() && !a.unverifiedCan(domain.CapabilityLogin) {
+		if err := a.sendVerification(ctx, user); err != nil && !errors.Is(err, repository.ErrVerificationCooldown) {
+			a.logger.Error("failed to send verification email", "user", user.Id, "error", err)
 		}
+		return nil, nil, repository.ErrEmailNotVerified
 	}
 
-	if !utils.CheckPasswordHash(user.Password, input.Password) {
-		return nil, errors.New("invalid credentials")
+	return a.LoginUser(ctx, user, input.DeviceName, device)
+}
+
+// LoginUser starts a session for a user whose identity is already proven,
+// by password or by single sign-on. Two-factor authentication still applies.
+func (a *authService) LoginUser(ctx context.Context, user *domain.User, deviceName string, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error) {
+	if user.IsSuspended(time.Now()) {
+		return nil, nil, repository.ErrAccountSuspended
 	}
 
-	accessToken, err := utils.GenerateToken(a.cfg, user.Id, user.Name, platform)
+	enabled, err := a.twoFactorService.IsEnabled(ctx, user.Id)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if enabled {
+		challengeToken, err := utils.GenerateChallengeToken(a.cfg.JWT.Secret, user.Id, device.Platform, deviceName, a.cfg.TwoFactor.ChallengeExpires)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &dto.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+			ExpiresIn:         int64(a.cfg.TwoFactor.ChallengeExpires.Seconds()),
+		}, nil
+	}
+
+	response, err := a.createSession(ctx, user, deviceName, device)
+	if err != nil {
+		return nil, nil, err
 	}
+	return response, nil, nil
+}
 
-	refreshToken, err := utils.GenerateRefreshToken()
+// LoginLinkedDevice starts a session for a new device the user approved from
+// one of their sessions. That session already passed the second factor, so
+// none is asked for.
+func (a *authService) LoginLinkedDevice(ctx context.Context, userId uint, deviceName string, device *dto.DeviceInfo) (*dto.LoginResponse, error) {
+	user, err := a.userRepository.GetUserById(ctx, userId)
 	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsDeleted() {
+		return nil, repository.ErrAccountDeleted
+	}
+
+	return a.createSession(ctx, user, deviceName, device)
+}
+
+// LoginTwoFactor finishes a login started with Login. The challenge is bound
+// to the platform it was issued for and only finishes a single login.
+func (a *authService) LoginTwoFactor(ctx context.Context, input *dto.LoginTwoFactorRequest, device *dto.DeviceInfo) (*dto.LoginResponse, error) {
+	claims, err := utils.ValidateChallengeToken(input.ChallengeToken, a.cfg.JWT.Secret)
+	if err != nil || claims.Platform != device.Platform {
+		return nil, repository.ErrInvalidChallengeToken
+	}
+
+	if a.revocationStore.IsRevoked(claims.ID, 0) {
+		return nil, repository.ErrInvalidChallengeToken
+	}
+
+	if err := a.twoFactorService.VerifyCode(ctx, claims.UserId, input.Code); err != nil {
 		return nil, err
 	}
 
-	if err := a.userRepository.UpdateRefreshToken(ctx, user.Id, refreshToken, platform); err != nil {
+	// A wrong code leaves the challenge usable, the right one uses it up
+	if !a.revocationStore.ConsumeToken(claims.ID, claims.ExpiresAt.Time) {
+		return nil, repository.ErrInvalidChallengeToken
+	}
+
+	user, err := a.userRepository.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, repository.ErrInvalidChallengeToken
+		}
 		return nil, err
 	}
 
-	return a.toLoginResponse(user, accessToken, refreshToken), nil
+	return a.createSession(ctx, user, claims.DeviceName, device)
 }
 
-func (a *authService) Logout(ctx context.Context, userId uint, platform string) error {
-	return a.userRepository.DeleteRefreshToken(ctx, userId, platform)
+// Logout ends the session and revokes the access token used for it, so it
+// can't be used for the rest of its lifetime.
+func (a *authService) Logout(ctx context.Context, userId, sessionId uint, tokenId string) error {
+	a.revocationStore.RevokeToken(tokenId, a.accessTokenDeadline())
+	if sessionId == 0 {
+		return nil
+	}
+	return a.RevokeSession(ctx, userId, sessionId)
 }
 
-func (a *authService) RefreshToken(ctx context.Context, input *dto.RefreshTokenRequest, platform string) (*dto.RefreshTokenResponse, error) {
-	user, err := a.userRepository.GetUserByRefreshToken(ctx, input.RefreshToken, platform)
+// RefreshToken rotates the refresh token, handing out a new one that starts a
+// fresh expiry period, and notes where the session was last used from.
+func (a *authService) RefreshToken(ctx context.Context, input *dto.RefreshTokenRequest, device *dto.DeviceInfo) (*dto.RefreshTokenResponse, error) {
+	current, err := a.getRefreshToken(ctx, input.RefreshToken, device.Platform)
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, err := utils.GenerateToken(a.cfg, user.Id, user.Name, platform)
+	// Suspending or deleting an account ends its sessions, this covers a
+	// refresh racing with that
+	if current.Session.User.IsDeleted() {
+		return nil, repository.ErrInvalidRefreshToken
+	}
+	if current.Session.User.IsSuspended(time.Now()) {
+		return nil, repository.ErrAccountSuspended
+	}
+
+	next, token, err := a.newRefreshToken(current.Session.Platform)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken()
+	session := &current.Session
+	session.UserAgent = device.UserAgent
+	session.IP = device.IP
+	session.LastUsedAt = time.Now()
+
+	rotated, err := a.sessionRepository.RotateRefreshToken(ctx, current, next)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !rotated {
+		return nil, a.revokeFamily(ctx, session)
 	}
 
-	if err := a.userRepository.UpdateRefreshToken(ctx, user.Id, refreshToken, platform); err != nil {
+	accessToken, err := utils.GenerateToken(a.tokenKeys, a.cfg, session.UserId, session.Id, session.User.Name, session.Platform, session.User.IsEmailVerified())
+	if err != nil {
 		return nil, err
 	}
 
-	return a.toRefreshToken(accessToken, refreshToken), nil
+	return a.toRefreshToken(accessToken, token), nil
 }
 
 func (a *authService) GetUserByRefreshToken(ctx context.Context, input *dto.RefreshTokenRequest, platform string) (*dto.UserResponse, error) {
-	user, err := a.userRepository.GetUserByRefreshToken(ctx, input.RefreshToken, platform)
+	refreshToken, err := a.getRefreshToken(ctx, input.RefreshToken, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(&refreshToken.Session.User), nil
+}
+
+func (a *authService) GetSessions(ctx context.Context, userId, currentSessionId uint) ([]dto.SessionResponse, error) {
+	sessions, err := a.sessionRepository.GetSessionsByUserId(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	responses := make([]dto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = a.toSessionResponse(&session)
+		responses[i].Current = session.Id == currentSessionId
+	}
+	return responses, nil
+}
+
+// RevokeSession ends the session along with the access tokens already issued
+// for it.
+func (a *authService) RevokeSession(ctx context.Context, userId, sessionId uint) error {
+	if err := a.sessionRepository.DeleteSession(ctx, sessionId, userId); err != nil {
+		return err
+	}
+	a.revocationStore.RevokeSession(sessionId, a.accessTokenDeadline())
+	return nil
+}
+
+// RevokeOtherSessions returns the ids of the sessions it ended.
+func (a *authService) RevokeOtherSessions(ctx context.Context, userId, currentSessionId uint) ([]uint, error) {
+	sessionIds, err := a.sessionRepository.DeleteOtherSessions(ctx, userId, currentSessionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	deadline := a.accessTokenDeadline()
+	for _, sessionId := range sessionIds {
+		a.revocationStore.RevokeSession(sessionId, deadline)
+	}
+	return sessionIds, nil
+}
+
+func (a *authService) VerifyEmail(ctx context.Context, input *dto.VerifyEmailRequest) error {
+	claims, err := utils.ValidateEmailToken(input.Token, a.cfg.JWT.Secret, utils.EmailTokenVerification)
+	if err != nil {
+		return repository.ErrInvalidVerificationToken
+	}
+
+	verified, err := a.userRepository.MarkEmailVerified(ctx, claims.UserId, claims.Email)
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	if !verified {
+		return repository.ErrInvalidVerificationToken
+	}
+	return nil
+}
+
+func (a *authService) ResendVerification(ctx context.Context, userId uint) error {
+	user, err := a.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.IsEmailVerified() {
+		return repository.ErrEmailAlreadyVerified
+	}
+	return a.sendVerification(ctx, user)
+}
+
+// sendVerification mails user a link to verify their email address, unless
+// one was sent within the resend cooldown.
+func (a *authService) sendVerification(ctx context.Context, user *domain.User) error {
+	claimed, err := a.userRepository.ClaimVerificationSend(ctx, user.Id, a.cfg.Verification.ResendCooldown)
+	if err != nil {
+		return fmt.Errorf("failed to record verification email: %w", err)
+	}
+	if !claimed {
+		return repository.ErrVerificationCooldown
+	}
+
+	token, err := utils.GenerateEmailToken(a.cfg.JWT.Secret, user.Id, user.Email, utils.EmailTokenVerification, a.cfg.Verification.TokenExpires)
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. The link expires in %s.\n\n%s?token=%s\n",
+			user.Name, a.cfg.Verification.TokenExpires, a.cfg.Verification.URL, url.QueryEscape(token)),
+	})
+}
+
+// ForgotPassword emails a reset link if an account with the address exists.
+// The answer is the same either way and the email goes out in the background,
+// so the endpoint doesn't tell which addresses are registered.
+func (a *authService) ForgotPassword(ctx context.Context, input *dto.ForgotPasswordRequest, ip string) error {
+	if !a.resetIPLimiter.Allow(ip) || !a.resetEmailLimiter.Allow(strings.ToLower(input.Email)) {
+		return repository.ErrPasswordResetRateLimited
+	}
+
+	user, err := a.userRepository.GetUserByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.IsBot() {
+		return nil
+	}
+
+	go func() {
+		if err := a.sendPasswordReset(context.Background(), user); err != nil {
+			a.logger.Error("failed to send password reset email", "user", user.Id, "error", err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword sets a new password and ends every session and personal
+// access token of the user. It returns the user's id and the ids of the ended
+// sessions.
+func (a *authService) ResetPassword(ctx context.Context, input *dto.ResetPasswordRequest) (uint, []uint, error) {
+	reset, err := a.passwordResetRepository.UsePasswordReset(ctx, utils.HashToken(input.Token))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, nil, repository.ErrInvalidPasswordResetToken
+		}
+		return 0, nil, fmt.Errorf("failed to use password reset token: %w", err)
+	}
+
+	if err := a.setPassword(ctx, reset.UserId, input.Password); err != nil {
+		return 0, nil, err
+	}
+
+	if err := a.passwordResetRepository.DeletePasswordResets(ctx, reset.UserId); err != nil {
+		return 0, nil, fmt.Errorf("failed to delete password reset tokens: %w", err)
+	}
+
+	if err := a.personalAccessTokenRepository.DeletePersonalAccessTokensByUserId(ctx, reset.UserId); err != nil {
+		return 0, nil, fmt.Errorf("failed to delete personal access tokens: %w", err)
+	}
+
+	// Session ids start at 1, so none is kept
+	sessionIds, err := a.RevokeOtherSessions(ctx, reset.UserId, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	return reset.UserId, sessionIds, nil
+}
+
+// ChangePassword keeps the current session and ends all the others, returning
+// their ids. Personal access tokens are deleted, whoever knew the old password
+// could have created them.
+func (a *authService) ChangePassword(ctx context.Context, userId, currentSessionId uint, input *dto.ChangePasswordRequest) ([]uint, error) {
+	user, err := a.userRepository.GetUserById(ctx, userId)
 	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !utils.CheckPasswordHash(user.Password, input.CurrentPassword) {
+		return nil, repository.ErrIncorrectPassword
+	}
+
+	if err := a.setPassword(ctx, user.Id, input.NewPassword); err != nil {
 		return nil, err
 	}
 
-	return a.toUserResponse(user), nil
+	// Reset links asked for with the old password must not outlive it
+	if err := a.passwordResetRepository.DeletePasswordResets(ctx, user.Id); err != nil {
+		return nil, fmt.Errorf("failed to delete password reset tokens: %w", err)
+	}
+
+	if err := a.personalAccessTokenRepository.DeletePersonalAccessTokensByUserId(ctx, user.Id); err != nil {
+		return nil, fmt.Errorf("failed to delete personal access tokens: %w", err)
+	}
+
+	return a.RevokeOtherSessions(ctx, user.Id, currentSessionId)
+}
+
+func (a *authService) setPassword(ctx context.Context, userId uint, password string) error {
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if err := a.userRepository.UpdatePassword(ctx, userId, hashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+func (a *authService) sendPasswordReset(ctx context.Context, user *domain.User) error {
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	if err := a.passwordResetRepository.CreatePasswordReset(ctx, &domain.PasswordReset{
+		UserId:    user.Id,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(a.cfg.PasswordReset.TokenExpires),
+	}); err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return a.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. If that was you, open the link below to choose a new one. The link expires in %s and works once.\n\n%s?token=%s\n\nIf you didn't ask for this, you can ignore this email.\n",
+			user.Name, a.cfg.PasswordReset.TokenExpires, a.cfg.PasswordReset.URL, url.QueryEscape(token)),
+	})
+}
+
+// createSession is where every login ends up, so suspended users are turned
+// away here whichever way they came.
+func (a *authService) createSession(ctx context.Context, user *domain.User, deviceName string, device *dto.DeviceInfo) (*dto.LoginResponse, error) {
+	if user.IsSuspended(time.Now()) {
+		return nil, repository.ErrAccountSuspended
+	}
+
+	refreshToken, token, err := a.newRefreshToken(device.Platform)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		UserId:     user.Id,
+		Platform:   device.Platform,
+		DeviceName: deviceName,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		LastUsedAt: time.Now(),
+	}
+
+	if err := a.sessionRepository.CreateSession(ctx, session, refreshToken); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// Over the platform's limit the least recently used sessions make room
+	if maxSessions := a.cfg.MaxSessions(session.Platform); maxSessions > 0 {
+		sessionIds, err := a.sessionRepository.DeleteExcessSessions(ctx, user.Id, session.Platform, maxSessions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to limit sessions: %w", err)
+		}
+
+		deadline := a.accessTokenDeadline()
+		for _, sessionId := range sessionIds {
+			a.revocationStore.RevokeSession(sessionId, deadline)
+		}
+	}
+
+	accessToken, err := utils.GenerateToken(a.tokenKeys, a.cfg, user.Id, session.Id, user.Name, session.Platform, user.IsEmailVerified())
+	if err != nil {
+		return nil, err
+	}
+
+	return a.toLoginResponse(user, accessToken, token), nil
+}
+
+func (a *authService) unverifiedCan(capability domain.Capability) bool {
+	return slices.Contains(a.cfg.Verification.UnverifiedCapabilities, string(capability))
+}
+
+// getRefreshToken only accepts the current, unexpired token of a session, and
+// only from the platform the session was started on. Presenting a token that
+// was already rotated means it leaked, so the whole session is revoked.
+func (a *authService) getRefreshToken(ctx context.Context, token, platform string) (*domain.RefreshToken, error) {
+	refreshToken, err := a.sessionRepository.GetRefreshTokenByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, repository.ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if refreshToken.RotatedAt != nil {
+		return nil, a.revokeFamily(ctx, &refreshToken.Session)
+	}
+
+	if refreshToken.IsExpired(time.Now()) || refreshToken.Session.Platform != platform {
+		return nil, repository.ErrInvalidRefreshToken
+	}
+	return refreshToken, nil
+}
+
+// revokeFamily ends session and with it every refresh token ever issued for
+// it.
+func (a *authService) revokeFamily(ctx context.Context, session *domain.Session) error {
+	if err := a.sessionRepository.DeleteSession(ctx, session.Id, session.UserId); err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	a.revocationStore.RevokeSession(session.Id, a.accessTokenDeadline())
+	return repository.ErrRefreshTokenReused
+}
+
+// accessTokenDeadline is when every access token issued until now will have
+// expired, revocations don't need to be kept any longer.
+func (a *authService) accessTokenDeadline() time.Time {
+	return time.Now().Add(a.cfg.MaxAccessTokenExpires())
+}
+
+// newRefreshToken returns the token to hand out and its record; only the
+// token's hash is stored.
+func (a *authService) newRefreshToken(platform string) (*domain.RefreshToken, string, error) {
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &domain.RefreshToken{
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(a.cfg.RefreshTokenExpires(platform)),
+	}, token, nil
 }
 
 func (a *authService) toUserDomain(input *dto.RegisterRequest) (*domain.User, error) {
@@ -145,18 +613,33 @@ func (a *authService) toRefreshToken(accessToken, refreshToken string) *dto.Refr
 	}
 }
 
-func (a *authService) toUserResponse(user *domain.User) *dto.UserResponse {
-	return &dto.UserResponse{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+func (a *authService) toSessionResponse(session *domain.Session) dto.SessionResponse {
+	return dto.SessionResponse{
+		Id:         session.Id,
+		Platform:   session.Platform,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
 	}
 }
 
-func NewAuthService(userRepository repository.UserRepository, cfg *config.Config) AuthService {
+func NewAuthService(userRepository repository.UserRepository, sessionRepository repository.SessionRepository, passwordResetRepository repository.PasswordResetRepository, personalAccessTokenRepository repository.PersonalAccessTokenRepository, auditLogRepository repository.AuditLogRepository, twoFactorService TwoFactorService, revocationStore revocation.Store, tokenKeys utils.TokenKeys, throttleStore throttle.Store, mailer mailer.Mailer, logger utils.LoggerStrategy, cfg *config.Config) AuthService {
 	return &authService{
-		userRepository: userRepository,
-		cfg:            cfg,
+		userRepository:                userRepository,
+		sessionRepository:             sessionRepository,
+		passwordResetRepository:       passwordResetRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		twoFactorService:              twoFactorService,
+		revocationStore:               revocationStore,
+		tokenKeys:                     tokenKeys,
+		mailer:                        mailer,
+		loginGuard:                    newLoginGuard(throttleStore, auditLogRepository, logger, cfg),
+		signupLimiter:                 utils.NewRateLimiter(float64(cfg.LoginThrottle.SignupIPRatePerHour)/3600, cfg.LoginThrottle.SignupIPRatePerHour),
+		resetEmailLimiter:             utils.NewRateLimiter(float64(cfg.PasswordReset.EmailRatePerHour)/3600, cfg.PasswordReset.EmailRatePerHour),
+		resetIPLimiter:                utils.NewRateLimiter(float64(cfg.PasswordReset.IPRatePerHour)/3600, cfg.PasswordReset.IPRatePerHour),
+		logger:                        logger,
+		cfg:                           cfg,
 	}
 }
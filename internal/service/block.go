@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+)
+
+type BlockService interface {
+	BlockUser(ctx context.Context, blockerId uint, input *dto.BlockRequest) (*dto.BlockResponse, error)
+	UnblockUser(ctx context.Context, blockerId, blockedId uint) error
+	GetBlockedUsers(ctx context.Context, blockerId uint) ([]dto.BlockResponse, error)
+
+	IsBlocked(ctx context.Context, user1Id, user2Id uint) (bool, error)
+	GetBlockedUserIds(ctx context.Context, userId uint) ([]uint, error)
+}
+
+type blockService struct {
+	blockRepository repository.BlockRepository
+	userRepository  repository.UserRepository
+}
+
+func (b *blockService) BlockUser(ctx context.Context, blockerId uint, input *dto.BlockRequest) (*dto.BlockResponse, error) {
+	if input.UserId == blockerId {
+		return nil, repository.ErrBlockSelf
+	}
+
+	user, err := b.userRepository.GetUserById(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &domain.Block{
+		BlockerId: blockerId,
+		BlockedId: user.Id,
+	}
+
+	created, err := b.blockRepository.CreateBlock(ctx, block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to block user: %w", err)
+	}
+	if !created {
+		return nil, repository.ErrAlreadyBlocked
+	}
+
+	block.Blocked = *user
+	return toBlockResponse(block), nil
+}
+
+func (b *blockService) UnblockUser(ctx context.Context, blockerId, blockedId uint) error {
+	return b.blockRepository.DeleteBlock(ctx, blockerId, blockedId)
+}
+
+func (b *blockService) GetBlockedUsers(ctx context.Context, blockerId uint) ([]dto.BlockResponse, error) {
+	blocks, err := b.blockRepository.GetBlocks(ctx, blockerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+
+	responses := make([]dto.BlockResponse, len(blocks))
+	for i, block := range blocks {
+		responses[i] = *toBlockResponse(&block)
+	}
+	return responses, nil
+}
+
+// IsBlocked reports whether either user blocked the other.
+func (b *blockService) IsBlocked(ctx context.Context, user1Id, user2Id uint) (bool, error) {
+	return b.blockRepository.IsBlocked(ctx, user1Id, user2Id)
+}
+
+// GetBlockedUserIds returns everyone userId shouldn't share presence with, in
+// either direction.
+func (b *blockService) GetBlockedUserIds(ctx context.Context, userId uint) ([]uint, error) {
+	return b.blockRepository.GetBlockedUserIds(ctx, userId)
+}
+
+func toBlockResponse(block *domain.Block) *dto.BlockResponse {
+	return &dto.BlockResponse{
+		User:      *toPublicUserResponse(&block.Blocked),
+		CreatedAt: block.CreatedAt,
+	}
+}
+
+func NewBlockService(blockRepository repository.BlockRepository, userRepository repository.UserRepository) BlockService {
+	return &blockService{
+		blockRepository: blockRepository,
+		userRepository:  userRepository,
+	}
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"strconv"
+	"strings"
+)
+
+// botPassword is not a valid bcrypt hash, so a bot account can never log in
+// through the user login endpoint.
+const botPassword = "!"
+
+type BotService interface {
+	CreateBot(ctx context.Context, input *dto.BotRequest, ownerId uint) (*dto.BotResponse, error)
+	GetBotsForOwner(ctx context.Context, ownerId uint) ([]dto.BotResponse, error)
+	RegenerateToken(ctx context.Context, botId, ownerId uint) (*dto.BotResponse, error)
+
+	Authenticate(ctx context.Context, token string) (*domain.Bot, error)
+	GetMe(ctx context.Context, bot *domain.Bot) *dto.BotApiUser
+	SendMessage(ctx context.Context, bot *domain.Bot, input *dto.BotSendMessageRequest) (*dto.MessageResponse, *dto.BotApiMessage, error)
+	GetUpdates(ctx context.Context, bot *domain.Bot, input *dto.BotGetUpdatesRequest) ([]dto.BotApiUpdate, error)
+	SetWebhook(ctx context.Context, bot *domain.Bot, input *dto.BotSetWebhookRequest) error
+	AnswerCallbackQuery(ctx context.Context, bot *domain.Bot, input *dto.BotAnswerCallbackQueryRequest) (*dto.CallbackAnswerResponse, error)
+	EditMessageReplyMarkup(ctx context.Context, bot *domain.Bot, input *dto.BotEditMessageReplyMarkupRequest) (*dto.MessageResponse, *dto.BotApiMessage, error)
+
+	CallbackQuery(ctx context.Context, userId uint, input *dto.CallbackQueryRequest) error
+}
+
+type botService struct {
+	botRepository     repository.BotRepository
+	userRepository    repository.UserRepository
+	privateRepository repository.PrivateRepository
+	messageRepository repository.MessageRepository
+	messageService    MessageService
+	botUpdateService  BotUpdateService
+	webhookService    WebhookService
+}
+
+func (b *botService) CreateBot(ctx context.Context, input *dto.BotRequest, ownerId uint) (*dto.BotResponse, error) {
+	exists, err := b.botRepository.CheckBotUsernameExists(ctx, input.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bot username: %w", err)
+	}
+	if exists {
+		return nil, repository.ErrBotUsernameExists
+	}
+
+	// Bots share the username namespace with users so they can be found by
+	// username as well
+	exists, err = b.userRepository.CheckUsernameExists(ctx, input.Username, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if exists {
+		return nil, repository.ErrBotUsernameExists
+	}
+
+	bot := &domain.Bot{
+		OwnerId:  ownerId,
+		Username: input.Username,
+		User: domain.User{
+			Type:     domain.UserTypeBot,
+			Name:     input.Name,
+			Username: &input.Username,
+			Email:    strings.ToLower(input.Username) + "@bots.invalid",
+			Password: botPassword,
+		},
+	}
+
+	// The token embeds the bot's user id, which is only known after insert, so
+	// the bot is created with a throwaway hash and the real one set right after.
+	bot.TokenHash, err = utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.botRepository.CreateBot(ctx, bot); err != nil {
+		return nil, fmt.Errorf("failed to create bot: %w", err)
+	}
+
+	token, err := b.rotateToken(ctx, bot)
+	if err != nil {
+		return nil, err
+	}
+
+	response := b.toBotResponse(bot)
+	response.Token = token
+	return response, nil
+}
+
+func (b *botService) GetBotsForOwner(ctx context.Context, ownerId uint) ([]dto.BotResponse, error) {
+	bots, err := b.botRepository.GetBotsByOwnerId(ctx, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bots: %w", err)
+	}
+
+	responses := make([]dto.BotResponse, len(bots))
+	for i, bot := range bots {
+		responses[i] = *b.toBotResponse(&bot)
+	}
+	return responses, nil
+}
+
+func (b *botService) RegenerateToken(ctx context.Context, botId, ownerId uint) (*dto.BotResponse, error) {
+	bot, err := b.botRepository.GetBotById(ctx, botId)
+	if err != nil {
+		return nil, err
+	}
+
+	if bot.OwnerId != ownerId {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	token, err := b.rotateToken(ctx, bot)
+	if err != nil {
+		return nil, err
+	}
+
+	response := b.toBotResponse(bot)
+	response.Token = token
+	return response, nil
+}
+
+func (b *botService) Authenticate(ctx context.Context, token string) (*domain.Bot, error) {
+	botUserId, secret, ok := utils.ParseBotToken(token)
+	if !ok {
+		return nil, repository.ErrInvalidBotToken
+	}
+
+	bot, err := b.botRepository.GetBotByUserId(ctx, botUserId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, repository.ErrInvalidBotToken
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(bot.TokenHash), []byte(utils.HashToken(secret))) != 1 {
+		return nil, repository.ErrInvalidBotToken
+	}
+
+	return bot, nil
+}
+
+func (b *botService) GetMe(ctx context.Context, bot *domain.Bot) *dto.BotApiUser {
+	return toBotApiUser(&bot.User, bot.Username)
+}
+
+// SendMessage posts into the private between the bot and chat_id. Like on
+// Telegram, a bot can only write to users who already have a chat with it.
+func (b *botService) SendMessage(ctx context.Context, bot *domain.Bot, input *dto.BotSendMessageRequest) (*dto.MessageResponse, *dto.BotApiMessage, error) {
+	private, err := b.privateRepository.GetPrivateByUsers(ctx, bot.UserId, input.ChatId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil, repository.ErrBotChatNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get private: %w", err)
+	}
+
+	chatUser, err := b.userRepository.GetUserById(ctx, input.ChatId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get chat user: %w", err)
+	}
+
+	message, err := b.messageService.SendMessage(ctx, &dto.MessageRequest{
+		PrivateId:   private.Id,
+		MessageType: string(domain.MessageTypeText),
+		Content:     input.Text,
+		ReplyMarkup: input.ReplyMarkup,
+	}, bot.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apiMessage := toBotApiMessage(&domain.Message{
+		Id:          message.Id,
+		MessageType: domain.MessageType(message.MessageType),
+		Content:     message.Content,
+		ReplyMarkup: toInlineKeyboardDomain(message.ReplyMarkup),
+		CreatedAt:   message.CreatedAt,
+	}, &bot.User, chatUser)
+	apiMessage.From.Username = bot.Username
+
+	return message, apiMessage, nil
+}
+
+func (b *botService) GetUpdates(ctx context.Context, bot *domain.Bot, input *dto.BotGetUpdatesRequest) ([]dto.BotApiUpdate, error) {
+	return b.botUpdateService.GetUpdates(ctx, bot, input)
+}
+
+func (b *botService) SetWebhook(ctx context.Context, bot *domain.Bot, input *dto.BotSetWebhookRequest) error {
+	bot.WebhookURL = nil
+	bot.WebhookSecret = nil
+
+	if input.Url != "" {
+		if err := utils.CheckPublicURL(ctx, input.Url); err != nil {
+			return repository.ErrWebhookURLNotAllowed
+		}
+
+		bot.WebhookURL = &input.Url
+		if input.SecretToken != "" {
+			bot.WebhookSecret = &input.SecretToken
+		}
+	}
+
+	if err := b.botRepository.UpdateBot(ctx, bot); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	if bot.WebhookURL != nil {
+		go b.botUpdateService.DeliverWebhook(bot)
+	}
+	return nil
+}
+
+func (b *botService) AnswerCallbackQuery(ctx context.Context, bot *domain.Bot, input *dto.BotAnswerCallbackQueryRequest) (*dto.CallbackAnswerResponse, error) {
+	query, err := b.botRepository.GetCallbackQueryById(ctx, input.CallbackQueryId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, repository.ErrCallbackQueryNotFound
+		}
+		return nil, fmt.Errorf("failed to get callback query: %w", err)
+	}
+
+	if query.BotId != bot.Id {
+		return nil, repository.ErrCallbackQueryNotFound
+	}
+
+	answered, err := b.botRepository.MarkCallbackQueryAsAnswered(ctx, query.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer callback query: %w", err)
+	}
+	if !answered {
+		return nil, repository.ErrCallbackQueryNotFound
+	}
+
+	return &dto.CallbackAnswerResponse{
+		CallbackQueryId: strconv.FormatUint(uint64(query.Id), 10),
+		UserId:          query.UserId,
+		MessageId:       query.MessageId,
+		Text:            input.Text,
+		ShowAlert:       input.ShowAlert,
+	}, nil
+}
+
+// EditMessageReplyMarkup replaces or, when ReplyMarkup is nil, removes the
+// keyboard of a message the bot sent to chat_id.
+func (b *botService) EditMessageReplyMarkup(ctx context.Context, bot *domain.Bot, input *dto.BotEditMessageReplyMarkupRequest) (*dto.MessageResponse, *dto.BotApiMessage, error) {
+	message, err := b.messageRepository.GetMessageById(ctx, input.MessageId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil, repository.ErrBotMessageNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if message.FromId != bot.UserId || message.PrivateId == nil {
+		return nil, nil, repository.ErrBotMessageNotFound
+	}
+
+	private, err := b.privateRepository.GetPrivateById(ctx, *message.PrivateId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get private: %w", err)
+	}
+
+	if private.User1Id != input.ChatId && private.User2Id != input.ChatId {
+		return nil, nil, repository.ErrBotMessageNotFound
+	}
+
+	message.ReplyMarkup = toInlineKeyboardDomain(input.ReplyMarkup)
+	if err := b.messageRepository.UpdateMessageReplyMarkup(ctx, message.Id, message.ReplyMarkup); err != nil {
+		return nil, nil, fmt.Errorf("failed to update reply markup: %w", err)
+	}
+
+	chatUser := &private.User1
+	if private.User2Id == input.ChatId {
+		chatUser = &private.User2
+	}
+
+	apiMessage := toBotApiMessage(message, &bot.User, chatUser)
+	apiMessage.From.Username = bot.Username
+
+	response := toMessageDTO(message)
+	b.webhookService.Publish(ctx, domain.WebhookEventMessageEdited, private, map[string]any{
+		"message": response,
+	})
+
+	return response, apiMessage, nil
+}
+
+// CallbackQuery is sent by a user tapping a callback button under a bot's
+// message. The data must belong to one of the message's current buttons.
+func (b *botService) CallbackQuery(ctx context.Context, userId uint, input *dto.CallbackQueryRequest) error {
+	message, err := b.messageRepository.GetMessageById(ctx, input.MessageId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return fmt.Errorf("message not found")
+		}
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if message.PrivateId == nil {
+		return fmt.Errorf("message not associated with a private chat")
+	}
+
+	private, err := b.privateRepository.GetPrivateById(ctx, *message.PrivateId)
+	if err != nil {
+		return fmt.Errorf("failed to get private chat: %w", err)
+	}
+
+	if private.User1Id != userId && private.User2Id != userId {
+		return fmt.Errorf("unauthorized to interact with this message")
+	}
+
+	if message.FromId == userId || message.ReplyMarkup == nil || !message.ReplyMarkup.HasCallbackData(input.Data) {
+		return repository.ErrInvalidCallbackQuery
+	}
+
+	bot, err := b.botRepository.GetBotByUserId(ctx, message.FromId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return repository.ErrInvalidCallbackQuery
+		}
+		return fmt.Errorf("failed to get bot: %w", err)
+	}
+
+	query := &domain.BotCallbackQuery{
+		BotId:     bot.Id,
+		UserId:    userId,
+		MessageId: message.Id,
+		Data:      input.Data,
+	}
+
+	if err := b.botRepository.CreateCallbackQuery(ctx, query); err != nil {
+		return fmt.Errorf("failed to create callback query: %w", err)
+	}
+
+	user := &private.User1
+	if private.User2Id == userId {
+		user = &private.User2
+	}
+
+	b.botUpdateService.PushCallbackQuery(ctx, bot, query, message, user)
+	return nil
+}
+
+func (b *botService) rotateToken(ctx context.Context, bot *domain.Bot) (string, error) {
+	token, hash, err := utils.GenerateBotToken(bot.UserId)
+	if err != nil {
+		return "", err
+	}
+
+	bot.TokenHash = hash
+	if err := b.botRepository.UpdateBot(ctx, bot); err != nil {
+		return "", fmt.Errorf("failed to update bot token: %w", err)
+	}
+	return token, nil
+}
+
+func (b *botService) toBotResponse(bot *domain.Bot) *dto.BotResponse {
+	response := &dto.BotResponse{
+		Id:        bot.Id,
+		UserId:    bot.UserId,
+		Name:      bot.User.Name,
+		Username:  bot.Username,
+		CreatedAt: bot.CreatedAt,
+	}
+	if bot.WebhookURL != nil {
+		response.WebhookURL = *bot.WebhookURL
+	}
+	return response
+}
+
+func NewBotService(botRepository repository.BotRepository, userRepository repository.UserRepository, privateRepository repository.PrivateRepository, messageRepository repository.MessageRepository, messageService MessageService, botUpdateService BotUpdateService, webhookService WebhookService) BotService {
+	return &botService{
+		botRepository:     botRepository,
+		userRepository:    userRepository,
+		privateRepository: privateRepository,
+		messageRepository: messageRepository,
+		messageService:    messageService,
+		botUpdateService:  botUpdateService,
+		webhookService:    webhookService,
+	}
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const botWebhookSecretHeader = "X-Telegopher-Bot-Api-Secret-Token"
+
+// A bot webhook that fails is tried again after botWebhookInitialBackoff,
+// waiting twice as long after every further failure up to botWebhookMaxBackoff.
+const (
+	botWebhookInitialBackoff = 5 * time.Second
+	botWebhookMaxBackoff     = 10 * time.Minute
+)
+
+// BotUpdateService queues Bot API updates for bots and hands them out either
+// through getUpdates long polling or by pushing them to the bot's webhook.
+type BotUpdateService interface {
+	PushMessage(ctx context.Context, botUser, sender *domain.User, message *domain.Message)
+	PushCallbackQuery(ctx context.Context, bot *domain.Bot, query *domain.BotCallbackQuery, message *domain.Message, user *domain.User)
+	GetUpdates(ctx context.Context, bot *domain.Bot, input *dto.BotGetUpdatesRequest) ([]dto.BotApiUpdate, error)
+	DeliverWebhook(bot *domain.Bot)
+}
+
+type botUpdateService struct {
+	botRepository repository.BotRepository
+	httpClient    *http.Client
+	logger        utils.LoggerStrategy
+
+	mu         sync.Mutex
+	waiters    map[uint]chan struct{}
+	delivering map[uint]*sync.Mutex
+	retries    map[uint]*time.Timer
+	backoffs   map[uint]time.Duration
+}
+
+func (b *botUpdateService) PushMessage(ctx context.Context, botUser, sender *domain.User, message *domain.Message) {
+	bot, err := b.botRepository.GetBotByUserId(ctx, botUser.Id)
+	if err != nil {
+		b.logger.Error("failed to get bot for update", "bot_user", botUser.Id, "error", err)
+		return
+	}
+
+	update := &dto.BotApiUpdate{
+		Message: toBotApiMessage(message, sender, sender),
+	}
+
+	b.push(ctx, bot, update)
+}
+
+func (b *botUpdateService) PushCallbackQuery(ctx context.Context, bot *domain.Bot, query *domain.BotCallbackQuery, message *domain.Message, user *domain.User) {
+	apiMessage := toBotApiMessage(message, &bot.User, user)
+	apiMessage.From.Username = bot.Username
+
+	update := &dto.BotApiUpdate{
+		CallbackQuery: &dto.BotApiCallbackQuery{
+			Id:           strconv.FormatUint(uint64(query.Id), 10),
+			From:         *toBotApiUser(user, ""),
+			Message:      apiMessage,
+			ChatInstance: strconv.FormatUint(uint64(*message.PrivateId), 10),
+			Data:         query.Data,
+		},
+	}
+
+	b.push(ctx, bot, update)
+}
+
+func (b *botUpdateService) push(ctx context.Context, bot *domain.Bot, update *dto.BotApiUpdate) {
+	payload, err := json.Marshal(update)
+	if err != nil {
+		b.logger.Error("failed to marshal bot update", "bot", bot.Id, "error", err)
+		return
+	}
+
+	if err := b.botRepository.CreateBotUpdate(ctx, &domain.BotUpdate{
+		BotId:   bot.Id,
+		Payload: string(payload),
+	}); err != nil {
+		b.logger.Error("failed to store bot update", "bot", bot.Id, "error", err)
+		return
+	}
+
+	if bot.WebhookURL != nil {
+		go b.DeliverWebhook(bot)
+		return
+	}
+
+	b.notify(bot.Id)
+}
+
+func (b *botUpdateService) GetUpdates(ctx context.Context, bot *domain.Bot, input *dto.BotGetUpdatesRequest) ([]dto.BotApiUpdate, error) {
+	if bot.WebhookURL != nil {
+		return nil, repository.ErrBotWebhookActive
+	}
+
+	// Updates before the offset have been seen by the bot, which is how the
+	// Bot API confirms them.
+	if input.Offset > 0 {
+		if err := b.botRepository.DeleteBotUpdatesBefore(ctx, bot.Id, input.Offset); err != nil {
+			return nil, fmt.Errorf("failed to confirm updates: %w", err)
+		}
+	}
+
+	// Take the wait channel before querying so an update stored in between is
+	// not missed.
+	wait := b.wait(bot.Id)
+
+	updates, err := b.botRepository.GetBotUpdates(ctx, bot.Id, input.Offset, input.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updates: %w", err)
+	}
+
+	if len(updates) == 0 && input.Timeout > 0 {
+		timer := time.NewTimer(input.Timeout)
+		defer timer.Stop()
+
+		select {
+		case <-wait:
+			updates, err = b.botRepository.GetBotUpdates(ctx, bot.Id, input.Offset, input.Limit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get updates: %w", err)
+			}
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return b.toBotApiUpdates(updates), nil
+}
+
+// DeliverWebhook posts pending updates to the bot's webhook in order. An update
+// is removed once the webhook answers with a 2xx status; on failure the rest
+// stay queued and are retried with backoff, or sooner with the next update.
+func (b *botUpdateService) DeliverWebhook(bot *domain.Bot) {
+	if b.deliverWebhook(bot) {
+		b.resetRetry(bot.Id)
+		return
+	}
+	b.scheduleRetry(bot.Id)
+}
+
+// deliverWebhook reports whether the queue was emptied.
+func (b *botUpdateService) deliverWebhook(bot *domain.Bot) bool {
+	lock := b.deliveryLock(bot.Id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	ctx := context.Background()
+
+	for {
+		updates, err := b.botRepository.GetBotUpdates(ctx, bot.Id, 0, 100)
+		if err != nil {
+			b.logger.Error("failed to get bot updates for webhook", "bot", bot.Id, "error", err)
+			return false
+		}
+
+		apiUpdates := b.toBotApiUpdates(updates)
+		for _, update := range apiUpdates {
+			if err := b.postWebhook(ctx, bot, &update); err != nil {
+				b.logger.Warn("failed to deliver bot webhook", "bot", bot.Id, "update", update.UpdateId, "error", err)
+				return false
+			}
+
+			if err := b.botRepository.DeleteBotUpdate(ctx, update.UpdateId); err != nil {
+				b.logger.Error("failed to delete delivered bot update", "bot", bot.Id, "update", update.UpdateId, "error", err)
+				return false
+			}
+		}
+
+		// A full page may have more behind it, unless none of it could be read
+		if len(updates) < 100 || len(apiUpdates) == 0 {
+			return true
+		}
+	}
+}
+
+// scheduleRetry tries the webhook again later, unless a retry is pending
+// already.
+func (b *botUpdateService) scheduleRetry(botId uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.retries[botId]; ok {
+		return
+	}
+
+	backoff := botWebhookInitialBackoff
+	if previous, ok := b.backoffs[botId]; ok {
+		backoff = min(previous*2, botWebhookMaxBackoff)
+	}
+	b.backoffs[botId] = backoff
+
+	b.retries[botId] = time.AfterFunc(backoff, func() {
+		b.mu.Lock()
+		delete(b.retries, botId)
+		b.mu.Unlock()
+
+		b.retryWebhook(botId)
+	})
+}
+
+func (b *botUpdateService) resetRetry(botId uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if timer, ok := b.retries[botId]; ok {
+		timer.Stop()
+		delete(b.retries, botId)
+	}
+	delete(b.backoffs, botId)
+}
+
+// retryWebhook reloads the bot, its webhook may have been removed or changed
+// in the meantime.
+func (b *botUpdateService) retryWebhook(botId uint) {
+	bot, err := b.botRepository.GetBotById(context.Background(), botId)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			b.logger.Error("failed to get bot for webhook retry", "bot", botId, "error", err)
+			b.scheduleRetry(botId)
+			return
+		}
+		b.resetRetry(botId)
+		return
+	}
+
+	if bot.WebhookURL == nil {
+		b.resetRetry(botId)
+		return
+	}
+
+	b.DeliverWebhook(bot)
+}
+
+func (b *botUpdateService) postWebhook(ctx context.Context, bot *domain.Bot, update *dto.BotApiUpdate) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *bot.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if bot.WebhookSecret != nil {
+		req.Header.Set(botWebhookSecretHeader, *bot.WebhookSecret)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *botUpdateService) wait(botId uint) <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, ok := b.waiters[botId]
+	if !ok {
+		ch = make(chan struct{})
+		b.waiters[botId] = ch
+	}
+	return ch
+}
+
+func (b *botUpdateService) notify(botId uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ch, ok := b.waiters[botId]; ok {
+		close(ch)
+		delete(b.waiters, botId)
+	}
+}
+
+func (b *botUpdateService) deliveryLock(botId uint) *sync.Mutex {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lock, ok := b.delivering[botId]
+	if !ok {
+		lock = &sync.Mutex{}
+		b.delivering[botId] = lock
+	}
+	return lock
+}
+
+func (b *botUpdateService) toBotApiUpdates(updates []domain.BotUpdate) []dto.BotApiUpdate {
+	response := make([]dto.BotApiUpdate, 0, len(updates))
+	for _, update := range updates {
+		var apiUpdate dto.BotApiUpdate
+		if err := json.Unmarshal([]byte(update.Payload), &apiUpdate); err != nil {
+			b.logger.Error("failed to unmarshal bot update", "update", update.Id, "error", err)
+			continue
+		}
+		apiUpdate.UpdateId = update.Id
+		response = append(response, apiUpdate)
+	}
+	return response
+}
+
+// toBotApiMessage converts a message of a private chat into the Bot API shape;
+// chatUser is the human on the other side of the bot's chat.
+func toBotApiMessage(message *domain.Message, from, chatUser *domain.User) *dto.BotApiMessage {
+	apiMessage := &dto.BotApiMessage{
+		MessageId: message.Id,
+		From:      toBotApiUser(from, ""),
+		Chat: dto.BotApiChat{
+			Id:        chatUser.Id,
+			Type:      "private",
+			FirstName: chatUser.Name,
+		},
+		Date:        message.CreatedAt.Unix(),
+		ReplyMarkup: toInlineKeyboardDTO(message.ReplyMarkup),
+	}
+
+	switch message.MessageType {
+	case domain.MessageTypeText:
+		apiMessage.Text = message.Content
+	default:
+		apiMessage.Document = &dto.BotApiDocument{
+			FileName: path.Base(message.Content),
+			FileUrl:  message.Content,
+		}
+	}
+	return apiMessage
+}
+
+func toBotApiUser(user *domain.User, username string) *dto.BotApiUser {
+	return &dto.BotApiUser{
+		Id:        user.Id,
+		IsBot:     user.IsBot(),
+		FirstName: user.Name,
+		Username:  username,
+	}
+}
+
+func NewBotUpdateService(botRepository repository.BotRepository, logger utils.LoggerStrategy) BotUpdateService {
+	return &botUpdateService{
+		botRepository: botRepository,
+		httpClient:    utils.NewPublicHTTPClient(10 * time.Second),
+		logger:        logger,
+		waiters:       make(map[uint]chan struct{}),
+		delivering:    make(map[uint]*sync.Mutex),
+		retries:       make(map[uint]*time.Timer),
+		backoffs:      make(map[uint]time.Duration),
+	}
+}
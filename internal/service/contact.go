@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"strings"
+)
+
+// ContactService manages users' address books. Being someone's contact doesn't
+// get around their privacy rules. Online status isn't known here, callers with
+// access to the hub fill it in for the contacts PresenceVisible allows.
+type ContactService interface {
+	AddContact(ctx context.Context, ownerId uint, input *dto.ContactRequest) (*dto.ContactResponse, error)
+	GetContacts(ctx context.Context, ownerId uint) ([]dto.ContactResponse, error)
+	UpdateContact(ctx context.Context, ownerId, contactId uint, input *dto.UpdateContactRequest) (*dto.ContactResponse, error)
+	DeleteContact(ctx context.Context, ownerId, contactId uint) error
+	ImportContacts(ctx context.Context, ownerId uint, input *dto.ImportContactsRequest) (*dto.ImportContactsResponse, error)
+	PresenceVisible(ctx context.Context, ownerId uint, contactIds []uint) (map[uint]bool, error)
+}
+
+type contactService struct {
+	contactRepository repository.ContactRepository
+	userRepository    repository.UserRepository
+	blockRepository   repository.BlockRepository
+	privacyService    PrivacyService
+}
+
+func (c *contactService) AddContact(ctx context.Context, ownerId uint, input *dto.ContactRequest) (*dto.ContactResponse, error) {
+	if input.UserId == ownerId {
+		return nil, repository.ErrContactSelf
+	}
+
+	user, err := c.userRepository.GetUserById(ctx, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := c.blockRepository.IsBlocked(ctx, ownerId, user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check blocks: %w", err)
+	}
+	if blocked {
+		return nil, repository.ErrUserBlocked
+	}
+
+	if _, err := c.contactRepository.GetContact(ctx, ownerId, user.Id); err == nil {
+		return nil, repository.ErrContactExists
+	} else if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get contact: %w", err)
+	}
+
+	contact := &domain.Contact{
+		OwnerId:   ownerId,
+		ContactId: user.Id,
+		Alias:     strings.TrimSpace(input.Alias),
+	}
+
+	if err := c.contactRepository.CreateContact(ctx, contact); err != nil {
+		return nil, fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	contact.Contact = *user
+	return c.toContactResponse(ctx, ownerId, contact)
+}
+
+func (c *contactService) GetContacts(ctx context.Context, ownerId uint) ([]dto.ContactResponse, error) {
+	contacts, err := c.contactRepository.GetContacts(ctx, ownerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacts: %w", err)
+	}
+
+	return c.toContactResponses(ctx, ownerId, contacts)
+}
+
+func (c *contactService) UpdateContact(ctx context.Context, ownerId, contactId uint, input *dto.UpdateContactRequest) (*dto.ContactResponse, error) {
+	contact, err := c.contactRepository.GetContact(ctx, ownerId, contactId)
+	if err != nil {
+		return nil, err
+	}
+
+	contact.Alias = strings.TrimSpace(input.Alias)
+	if err := c.contactRepository.UpdateContact(ctx, contact); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	return c.toContactResponse(ctx, ownerId, contact)
+}
+
+func (c *contactService) DeleteContact(ctx context.Context, ownerId, contactId uint) error {
+	return c.contactRepository.DeleteContact(ctx, ownerId, contactId)
+}
+
+// ImportContacts adds every registered user among the given emails to the
+// owner's contacts, except those in a block with the owner. Users that already
+// are contacts keep their alias.
+func (c *contactService) ImportContacts(ctx context.Context, ownerId uint, input *dto.ImportContactsRequest) (*dto.ImportContactsResponse, error) {
+	emails := make([]string, 0, len(input.Emails))
+	seen := make(map[string]struct{}, len(input.Emails))
+	for _, email := range input.Emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if _, ok := seen[email]; ok {
+			continue
+		}
+		seen[email] = struct{}{}
+		emails = append(emails, email)
+	}
+
+	users, err := c.userRepository.GetUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	blocked, err := c.blockedUserIds(ctx, ownerId)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[string]struct{}, len(users))
+	contacts := make([]domain.Contact, 0, len(users))
+	contactIds := make([]uint, 0, len(users))
+	for _, user := range users {
+		registered[strings.ToLower(user.Email)] = struct{}{}
+		if _, ok := blocked[user.Id]; ok || user.Id == ownerId {
+			continue
+		}
+		contacts = append(contacts, domain.Contact{OwnerId: ownerId, ContactId: user.Id})
+		contactIds = append(contactIds, user.Id)
+	}
+
+	if err := c.contactRepository.CreateContacts(ctx, contacts); err != nil {
+		return nil, fmt.Errorf("failed to create contacts: %w", err)
+	}
+
+	imported, err := c.contactRepository.GetContactsByContactIds(ctx, ownerId, contactIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacts: %w", err)
+	}
+
+	responses, err := c.toContactResponses(ctx, ownerId, imported)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ImportContactsResponse{
+		Contacts:     responses,
+		Unregistered: make([]string, 0),
+	}
+	for _, email := range emails {
+		if _, ok := registered[email]; !ok {
+			response.Unregistered = append(response.Unregistered, email)
+		}
+	}
+	return response, nil
+}
+
+// PresenceVisible tells which of the contacts ownerId may see online, going
+// by their last seen rule. Users in a block with the owner never appear online
+// to them.
+func (c *contactService) PresenceVisible(ctx context.Context, ownerId uint, contactIds []uint) (map[uint]bool, error) {
+	visible, err := c.privacyService.VisibleOwners(ctx, ownerId, contactIds, domain.PrivacyKeyLastSeen)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := c.blockedUserIds(ctx, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	for id := range blocked {
+		delete(visible, id)
+	}
+	return visible, nil
+}
+
+func (c *contactService) blockedUserIds(ctx context.Context, userId uint) (map[uint]struct{}, error) {
+	userIds, err := c.blockRepository.GetBlockedUserIds(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+
+	blocked := make(map[uint]struct{}, len(userIds))
+	for _, id := range userIds {
+		blocked[id] = struct{}{}
+	}
+	return blocked, nil
+}
+
+func (c *contactService) toContactResponse(ctx context.Context, ownerId uint, contact *domain.Contact) (*dto.ContactResponse, error) {
+	responses, err := c.toContactResponses(ctx, ownerId, []domain.Contact{*contact})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// toContactResponses hides the avatars ownerId isn't allowed to see.
+func (c *contactService) toContactResponses(ctx context.Context, ownerId uint, contacts []domain.Contact) ([]dto.ContactResponse, error) {
+	contactIds := make([]uint, len(contacts))
+	for i, contact := range contacts {
+		contactIds[i] = contact.ContactId
+	}
+
+	canSeeAvatar, err := c.privacyService.VisibleOwners(ctx, ownerId, contactIds, domain.PrivacyKeyAvatar)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ContactResponse, len(contacts))
+	for i, contact := range contacts {
+		responses[i] = dto.ContactResponse{
+			User:      *toPublicUserResponse(&contact.Contact),
+			Alias:     contact.Alias,
+			CreatedAt: contact.CreatedAt,
+		}
+		if !canSeeAvatar[contact.ContactId] {
+			responses[i].User.AvatarURL = ""
+		}
+	}
+	return responses, nil
+}
+
+func NewContactService(contactRepository repository.ContactRepository, userRepository repository.UserRepository, blockRepository repository.BlockRepository, privacyService PrivacyService) ContactService {
+	return &contactService{
+		contactRepository: contactRepository,
+		userRepository:    userRepository,
+		blockRepository:   blockRepository,
+		privacyService:    privacyService,
+	}
+}
@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/filestore"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const telegramResultFile = "result.json"
+
+const interruptedImportError = "interrupted by a server restart, run the import again to finish it"
+
+type ImportService interface {
+	StartTelegramImport(ctx context.Context, input *dto.TelegramImportRequest, requestedById uint) (*dto.ImportJobResponse, error)
+	ImportTelegram(ctx context.Context, input *dto.TelegramImportRequest) (*dto.ImportJobResponse, error)
+	GetImportJob(ctx context.Context, id uint) (*dto.ImportJobResponse, error)
+	FailInterruptedJobs(ctx context.Context) error
+}
+
+type importService struct {
+	importRepository  repository.ImportRepository
+	userRepository    repository.UserRepository
+	privateRepository repository.PrivateRepository
+	fileStore         filestore.FileStore
+	logger            utils.LoggerStrategy
+	cfg               *config.Config
+}
+
+type telegramImport struct {
+	job      *domain.ImportJob
+	export   *dto.TelegramExport
+	baseDir  string
+	mapping  map[string]string
+	location *time.Location
+	users    map[string]*domain.User
+}
+
+// StartTelegramImport is the admin API entry point: the export must live under
+// the configured import root and the import keeps running in the background.
+func (i *importService) StartTelegramImport(ctx context.Context, input *dto.TelegramImportRequest, requestedById uint) (*dto.ImportJobResponse, error) {
+	exportPath, err := i.resolveExportPath(input.ExportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := i.prepareTelegramImport(ctx, exportPath, input, &requestedById)
+	if err != nil {
+		return nil, err
+	}
+
+	go i.runTelegramImport(context.Background(), run)
+
+	return i.toImportJobResponse(run.job), nil
+}
+
+// ImportTelegram runs an import synchronously and is used by the CLI command.
+func (i *importService) ImportTelegram(ctx context.Context, input *dto.TelegramImportRequest) (*dto.ImportJobResponse, error) {
+	run, err := i.prepareTelegramImport(ctx, input.ExportPath, input, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	i.runTelegramImport(ctx, run)
+
+	return i.toImportJobResponse(run.job), nil
+}
+
+func (i *importService) GetImportJob(ctx context.Context, id uint) (*dto.ImportJobResponse, error) {
+	job, err := i.importRepository.GetImportJobById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return i.toImportJobResponse(job), nil
+}
+
+// FailInterruptedJobs marks the jobs a previous run of the server left
+// running as failed. Re-running their export picks up where they stopped.
+func (i *importService) FailInterruptedJobs(ctx context.Context) error {
+	failed, err := i.importRepository.FailRunningImportJobs(ctx, interruptedImportError)
+	if err != nil {
+		return fmt.Errorf("failed to fail interrupted import jobs: %w", err)
+	}
+	if failed > 0 {
+		i.logger.Warn("marked interrupted import jobs as failed", "jobs", failed)
+	}
+	return nil
+}
+
+func (i *importService) resolveExportPath(exportPath string) (string, error) {
+	if i.cfg.Import.RootDir == "" {
+		return "", repository.ErrImportDisabled
+	}
+
+	root, err := filepath.Abs(i.cfg.Import.RootDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve import root: %w", err)
+	}
+
+	resolved := filepath.Join(root, filepath.Clean("/"+exportPath))
+	if !isWithinDir(root, resolved) {
+		return "", repository.ErrImportPathNotAllowed
+	}
+	return resolved, nil
+}
+
+func (i *importService) prepareTelegramImport(ctx context.Context, exportPath string, input *dto.TelegramImportRequest, requestedById *uint) (*telegramImport, error) {
+	location := time.UTC
+	if input.Timezone != "" {
+		loaded, err := time.LoadLocation(input.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load timezone: %w", err)
+		}
+		location = loaded
+	}
+
+	export, baseDir, err := i.loadTelegramExport(exportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	total := 0
+	for _, chat := range export.ChatList() {
+		total += len(chat.Messages)
+	}
+
+	job := &domain.ImportJob{
+		Source:        domain.ImportSourceTelegram,
+		ExportPath:    exportPath,
+		RequestedById: requestedById,
+		Status:        domain.ImportStatusRunning,
+		TotalMessages: total,
+	}
+
+	if err := i.importRepository.CreateImportJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return &telegramImport{
+		job:      job,
+		export:   export,
+		baseDir:  baseDir,
+		mapping:  input.UserMapping,
+		location: location,
+		users:    make(map[string]*domain.User),
+	}, nil
+}
+
+func (i *importService) loadTelegramExport(exportPath string) (*dto.TelegramExport, string, error) {
+	info, err := os.Stat(exportPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open export: %w", err)
+	}
+
+	if info.IsDir() {
+		exportPath = filepath.Join(exportPath, telegramResultFile)
+	}
+
+	file, err := os.Open(exportPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open export: %w", err)
+	}
+	defer file.Close()
+
+	var export dto.TelegramExport
+	if err := json.NewDecoder(file).Decode(&export); err != nil {
+		return nil, "", fmt.Errorf("failed to parse export: %w", err)
+	}
+
+	return &export, filepath.Dir(exportPath), nil
+}
+
+func (i *importService) runTelegramImport(ctx context.Context, run *telegramImport) {
+	err := i.importTelegramChats(ctx, run)
+
+	run.job.Status = domain.ImportStatusCompleted
+	if err != nil {
+		i.logger.Error("telegram import failed", "job", run.job.Id, "error", err)
+		message := err.Error()
+		run.job.Status = domain.ImportStatusFailed
+		run.job.Error = &message
+	}
+
+	if err := i.importRepository.UpdateImportJob(ctx, run.job); err != nil {
+		i.logger.Error("failed to update import job", "job", run.job.Id, "error", err)
+	}
+
+	i.logger.Info("telegram import finished", "job", run.job.Id, "status", run.job.Status, "imported", run.job.ImportedMessages, "skipped", run.job.SkippedMessages)
+}
+
+func (i *importService) importTelegramChats(ctx context.Context, run *telegramImport) error {
+	for _, chat := range run.export.ChatList() {
+		if chat.Type != "personal_chat" {
+			i.logger.Warn("skipping unsupported telegram chat", "job", run.job.Id, "chat", chat.Name, "type", chat.Type)
+			run.job.SkippedMessages += len(chat.Messages)
+			continue
+		}
+
+		if err := i.importTelegramChat(ctx, run, &chat); err != nil {
+			return err
+		}
+
+		if err := i.importRepository.UpdateImportJob(ctx, run.job); err != nil {
+			return fmt.Errorf("failed to update import job: %w", err)
+		}
+	}
+	return nil
+}
+
+func (i *importService) importTelegramChat(ctx context.Context, run *telegramImport, chat *dto.TelegramChat) error {
+	fromIds := i.telegramParticipants(chat)
+
+	participants := make(map[uint]*domain.User)
+	for _, fromId := range fromIds {
+		user, err := i.resolveTelegramUser(ctx, run, fromId)
+		if err != nil {
+			return err
+		}
+		if user != nil {
+			participants[user.Id] = user
+		}
+	}
+
+	if len(participants) != 2 {
+		i.logger.Warn("skipping telegram chat without two mapped participants", "job", run.job.Id, "chat", chat.Name, "participants", fromIds)
+		run.job.SkippedMessages += len(chat.Messages)
+		return nil
+	}
+
+	userIds := make([]uint, 0, 2)
+	for id := range participants {
+		userIds = append(userIds, id)
+	}
+
+	private, err := i.getOrCreatePrivate(ctx, userIds[0], userIds[1])
+	if err != nil {
+		return err
+	}
+
+	externalChatId := strings.Join(fromIds, ":")
+	for n, message := range chat.Messages {
+		imported, err := i.importTelegramMessage(ctx, run, private, externalChatId, &message)
+		if err != nil {
+			return err
+		}
+
+		if imported {
+			run.job.ImportedMessages++
+		} else {
+			run.job.SkippedMessages++
+		}
+
+		if (n+1)%100 == 0 {
+			if err := i.importRepository.UpdateImportJob(ctx, run.job); err != nil {
+				return fmt.Errorf("failed to update import job: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// telegramParticipants returns the sorted export user ids of a personal chat:
+// everyone who wrote in it plus the peer, whose id is the chat id.
+func (i *importService) telegramParticipants(chat *dto.TelegramChat) []string {
+	seen := map[string]struct{}{
+		fmt.Sprintf("user%d", chat.Id): {},
+	}
+	for _, message := range chat.Messages {
+		if message.Type == "message" && message.FromId != "" {
+			seen[message.FromId] = struct{}{}
+		}
+	}
+
+	fromIds := make([]string, 0, len(seen))
+	for fromId := range seen {
+		fromIds = append(fromIds, fromId)
+	}
+	sort.Strings(fromIds)
+	return fromIds
+}
+
+func (i *importService) resolveTelegramUser(ctx context.Context, run *telegramImport, fromId string) (*domain.User, error) {
+	if user, ok := run.users[fromId]; ok {
+		return user, nil
+	}
+
+	email, ok := run.mapping[fromId]
+	if !ok {
+		run.users[fromId] = nil
+		return nil, nil
+	}
+
+	user, err := i.userRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			i.logger.Warn("no account for mapped telegram user", "job", run.job.Id, "from_id", fromId, "email", email)
+			run.users[fromId] = nil
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user %s: %w", email, err)
+	}
+
+	run.users[fromId] = user
+	return user, nil
+}
+
+func (i *importService) getOrCreatePrivate(ctx context.Context, user1Id, user2Id uint) (*domain.Private, error) {
+	private, err := i.privateRepository.GetPrivateByUsers(ctx, user1Id, user2Id)
+	if err == nil {
+		return private, nil
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get private: %w", err)
+	}
+
+	private = &domain.Private{
+		User1Id: user1Id,
+		User2Id: user2Id,
+	}
+	if err := i.privateRepository.CreatePrivate(ctx, private); err != nil {
+		return nil, fmt.Errorf("failed to create private: %w", err)
+	}
+	return private, nil
+}
+
+// importTelegramMessage turns one exported message into at most two local
+// messages, the attached media (part 0) and its caption (part 1). It reports
+// whether anything new was written.
+func (i *importService) importTelegramMessage(ctx context.Context, run *telegramImport, private *domain.Private, externalChatId string, message *dto.TelegramMessage) (bool, error) {
+	if message.Type != "message" {
+		return false, nil
+	}
+
+	sender := run.users[message.FromId]
+	if sender == nil {
+		return false, nil
+	}
+
+	createdAt := i.telegramDate(run, message)
+	imported := false
+
+	if media, messageType := i.telegramMedia(message); media != "" {
+		ok, err := i.importTelegramPart(ctx, run, externalChatId, message.Id, 0, func() (*domain.Message, error) {
+			url, err := i.copyTelegramMedia(run, private, sender, media)
+			if err != nil || url == "" {
+				return nil, err
+			}
+			return i.toImportedMessage(private, sender, messageType, url, createdAt), nil
+		})
+		if err != nil {
+			return false, err
+		}
+		imported = imported || ok
+	}
+
+	if text := message.PlainText(); strings.TrimSpace(text) != "" {
+		ok, err := i.importTelegramPart(ctx, run, externalChatId, message.Id, 1, func() (*domain.Message, error) {
+			return i.toImportedMessage(private, sender, domain.MessageTypeText, text, createdAt), nil
+		})
+		if err != nil {
+			return false, err
+		}
+		imported = imported || ok
+	}
+
+	return imported, nil
+}
+
+func (i *importService) importTelegramPart(ctx context.Context, run *telegramImport, externalChatId string, externalMessageId int64, part int, build func() (*domain.Message, error)) (bool, error) {
+	exists, err := i.importRepository.IsMessageImported(ctx, domain.ImportSourceTelegram, externalChatId, externalMessageId, part)
+	if err != nil {
+		return false, fmt.Errorf("failed to check imported message: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	message, err := build()
+	if err != nil {
+		return false, err
+	}
+	if message == nil {
+		return false, nil
+	}
+
+	record := &domain.ImportedMessage{
+		Source:            domain.ImportSourceTelegram,
+		ExternalChatId:    externalChatId,
+		ExternalMessageId: externalMessageId,
+		Part:              part,
+	}
+
+	if err := i.importRepository.CreateImportedMessage(ctx, message, record); err != nil {
+		return false, fmt.Errorf("failed to import message %d: %w", externalMessageId, err)
+	}
+	return true, nil
+}
+
+// copyTelegramMedia copies an exported file into the file store. Exports made
+// without media reference files that are not on disk; those are skipped.
+func (i *importService) copyTelegramMedia(run *telegramImport, private *domain.Private, sender *domain.User, media string) (string, error) {
+	source := filepath.Join(run.baseDir, filepath.FromSlash(media))
+	if !isWithinDir(run.baseDir, source) {
+		i.logger.Warn("skipping telegram media outside the export", "job", run.job.Id, "media", media)
+		return "", nil
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			i.logger.Warn("telegram media not included in export", "job", run.job.Id, "media", media)
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to open media %s: %w", media, err)
+	}
+	defer file.Close()
+
+	// Exports reuse names like photo_1.jpg across folders, the content hash
+	// keeps different files apart
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read media %s: %w", media, err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read media %s: %w", media, err)
+	}
+
+	key := fmt.Sprintf("chats/%d/%d/%s-%s", private.Id, sender.Id, hex.EncodeToString(hash.Sum(nil))[:16], filepath.Base(source))
+	url, err := i.fileStore.Save(key, file)
+	if err != nil {
+		return "", fmt.Errorf("failed to store media %s: %w", media, err)
+	}
+	return url, nil
+}
+
+func (i *importService) telegramMedia(message *dto.TelegramMessage) (string, domain.MessageType) {
+	if message.Photo != "" {
+		return message.Photo, domain.MessageTypeImage
+	}
+	return message.File, domain.MessageTypeFile
+}
+
+// telegramDate prefers the Unix timestamp, older exports only have the local
+// time of the machine they were made on.
+func (i *importService) telegramDate(run *telegramImport, message *dto.TelegramMessage) time.Time {
+	if unix, err := strconv.ParseInt(message.DateUnixtime, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC()
+	}
+	if date, err := time.ParseInLocation("2006-01-02T15:04:05", message.Date, run.location); err == nil {
+		return date.UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (i *importService) toImportedMessage(private *domain.Private, sender *domain.User, messageType domain.MessageType, content string, createdAt time.Time) *domain.Message {
+	return &domain.Message{
+		FromId:      sender.Id,
+		PrivateId:   &private.Id,
+		MessageType: messageType,
+		Content:     content,
+		Delivered:   true,
+		Read:        true,
+		CreatedAt:   createdAt,
+	}
+}
+
+func (i *importService) toImportJobResponse(job *domain.ImportJob) *dto.ImportJobResponse {
+	response := &dto.ImportJobResponse{
+		Id:               job.Id,
+		Source:           string(job.Source),
+		ExportPath:       job.ExportPath,
+		Status:           string(job.Status),
+		TotalMessages:    job.TotalMessages,
+		ImportedMessages: job.ImportedMessages,
+		SkippedMessages:  job.SkippedMessages,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
+	}
+	if job.Error != nil {
+		response.Error = *job.Error
+	}
+	return response
+}
+
+func isWithinDir(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func NewImportService(importRepository repository.ImportRepository, userRepository repository.UserRepository, privateRepository repository.PrivateRepository, fileStore filestore.FileStore, logger utils.LoggerStrategy, cfg *config.Config) ImportService {
+	return &importService{
+		importRepository:  importRepository,
+		userRepository:    userRepository,
+		privateRepository: privateRepository,
+		fileStore:         fileStore,
+		logger:            logger,
+		cfg:               cfg,
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"strconv"
+)
+
+type IncomingWebhookService interface {
+	CreateIncomingWebhook(ctx context.Context, privateId, userId uint, input *dto.IncomingWebhookRequest) (*dto.IncomingWebhookResponse, error)
+	GetIncomingWebhooks(ctx context.Context, privateId, userId uint) ([]dto.IncomingWebhookResponse, error)
+	DeleteIncomingWebhook(ctx context.Context, id, userId uint) error
+
+	PostMessage(ctx context.Context, id uint, token string, input *dto.IncomingWebhookMessageRequest) (*dto.MessageResponse, []uint, error)
+}
+
+type incomingWebhookService struct {
+	incomingWebhookRepository repository.IncomingWebhookRepository
+	privateRepository         repository.PrivateRepository
+	messageService            MessageService
+	rateLimiter               *utils.RateLimiter
+}
+
+func (i *incomingWebhookService) CreateIncomingWebhook(ctx context.Context, privateId, userId uint, input *dto.IncomingWebhookRequest) (*dto.IncomingWebhookResponse, error) {
+	if _, err := i.getOwnPrivate(ctx, privateId, userId); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &domain.IncomingWebhook{
+		PrivateId: privateId,
+		CreatorId: userId,
+		Name:      input.Name,
+		TokenHash: utils.HashToken(token),
+	}
+
+	if err := i.incomingWebhookRepository.CreateIncomingWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create incoming webhook: %w", err)
+	}
+
+	// The URL carries the token, so it is only shown once
+	response := i.toIncomingWebhookResponse(webhook)
+	response.URL = fmt.Sprintf("/v1/hooks/%d/%s", webhook.Id, token)
+	return response, nil
+}
+
+func (i *incomingWebhookService) GetIncomingWebhooks(ctx context.Context, privateId, userId uint) ([]dto.IncomingWebhookResponse, error) {
+	if _, err := i.getOwnPrivate(ctx, privateId, userId); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := i.incomingWebhookRepository.GetIncomingWebhooksByPrivateId(ctx, privateId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incoming webhooks: %w", err)
+	}
+
+	responses := make([]dto.IncomingWebhookResponse, len(webhooks))
+	for idx, webhook := range webhooks {
+		responses[idx] = *i.toIncomingWebhookResponse(&webhook)
+	}
+	return responses, nil
+}
+
+// DeleteIncomingWebhook can be called by either participant of the
+// conversation, not only by the one who created the webhook.
+func (i *incomingWebhookService) DeleteIncomingWebhook(ctx context.Context, id, userId uint) error {
+	webhook, err := i.incomingWebhookRepository.GetIncomingWebhookById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := i.getOwnPrivate(ctx, webhook.PrivateId, userId); err != nil {
+		return err
+	}
+
+	if err := i.incomingWebhookRepository.DeleteIncomingWebhook(ctx, webhook.Id); err != nil {
+		return fmt.Errorf("failed to delete incoming webhook: %w", err)
+	}
+	return nil
+}
+
+// PostMessage sends a text message into the webhook's conversation and returns
+// it together with the ids of the conversation's participants to notify.
+func (i *incomingWebhookService) PostMessage(ctx context.Context, id uint, token string, input *dto.IncomingWebhookMessageRequest) (*dto.MessageResponse, []uint, error) {
+	webhook, err := i.incomingWebhookRepository.GetIncomingWebhookById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil, repository.ErrInvalidIncomingWebhookToken
+		}
+		return nil, nil, fmt.Errorf("failed to get incoming webhook: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(webhook.TokenHash), []byte(utils.HashToken(token))) != 1 {
+		return nil, nil, repository.ErrInvalidIncomingWebhookToken
+	}
+
+	if !i.rateLimiter.Allow(strconv.FormatUint(uint64(webhook.Id), 10)) {
+		return nil, nil, repository.ErrIncomingWebhookRateLimited
+	}
+
+	private, err := i.privateRepository.GetPrivateById(ctx, webhook.PrivateId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get private: %w", err)
+	}
+
+	message, err := i.messageService.SendMessage(ctx, &dto.MessageRequest{
+		PrivateId:   webhook.PrivateId,
+		MessageType: string(domain.MessageTypeText),
+		Content:     input.Text,
+		Format:      input.Format,
+		Integration: webhook.Name,
+	}, webhook.CreatorId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return message, []uint{private.User1Id, private.User2Id}, nil
+}
+
+func (i *incomingWebhookService) getOwnPrivate(ctx context.Context, privateId, userId uint) (*domain.Private, error) {
+	private, err := i.privateRepository.GetPrivateById(ctx, privateId)
+	if err != nil {
+		return nil, err
+	}
+
+	if private.User1Id != userId && private.User2Id != userId {
+		return nil, repository.ErrRecordNotFound
+	}
+	return private, nil
+}
+
+func (i *incomingWebhookService) toIncomingWebhookResponse(webhook *domain.IncomingWebhook) *dto.IncomingWebhookResponse {
+	return &dto.IncomingWebhookResponse{
+		Id:        webhook.Id,
+		PrivateId: webhook.PrivateId,
+		CreatorId: webhook.CreatorId,
+		Name:      webhook.Name,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func NewIncomingWebhookService(incomingWebhookRepository repository.IncomingWebhookRepository, privateRepository repository.PrivateRepository, messageService MessageService, cfg *config.Config) IncomingWebhookService {
+	return &incomingWebhookService{
+		incomingWebhookRepository: incomingWebhookRepository,
+		privateRepository:         privateRepository,
+		messageService:            messageService,
+		rateLimiter:               utils.NewRateLimiter(float64(cfg.IncomingWebhook.RatePerMinute)/60, cfg.IncomingWebhook.Burst),
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"sync"
+	"time"
+)
+
+// LastSeenService collects the times users went offline and writes them in
+// batches, so a wave of disconnects doesn't turn into a wave of updates.
+type LastSeenService interface {
+	Record(userId uint, at time.Time)
+	Flush(ctx context.Context) error
+}
+
+type lastSeenService struct {
+	userRepository repository.UserRepository
+	logger         utils.LoggerStrategy
+	mu             sync.Mutex
+	pending        map[uint]time.Time
+}
+
+func (l *lastSeenService) Record(userId uint, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending[userId] = at
+}
+
+// Flush writes everything recorded so far. On failure the times are kept for
+// the next flush unless a newer one was recorded meanwhile.
+func (l *lastSeenService) Flush(ctx context.Context) error {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = make(map[uint]time.Time)
+	l.mu.Unlock()
+
+	if err := l.userRepository.UpdateLastSeen(ctx, batch); err != nil {
+		l.mu.Lock()
+		for userId, at := range batch {
+			if _, ok := l.pending[userId]; !ok {
+				l.pending[userId] = at
+			}
+		}
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (l *lastSeenService) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		l.mu.Lock()
+		size := len(l.pending)
+		l.mu.Unlock()
+
+		if size == 0 {
+			continue
+		}
+
+		if err := l.Flush(context.Background()); err != nil {
+			l.logger.Error("failed to store last seen times", "users", size, "error", err)
+		}
+	}
+}
+
+func NewLastSeenService(userRepository repository.UserRepository, logger utils.LoggerStrategy, cfg *config.Config) LastSeenService {
+	l := &lastSeenService{
+		userRepository: userRepository,
+		logger:         logger,
+		pending:        make(map[uint]time.Time),
+	}
+
+	go l.run(cfg.LastSeen.FlushInterval)
+	return l
+}
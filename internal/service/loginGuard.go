@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/throttle"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"strings"
+	"time"
+)
+
+// loginGuard throttles failed logins per account and per client IP. Accounts
+// are keyed by the email tried, whether it exists or not, so throttling
+// doesn't tell which emails are registered.
+type loginGuard struct {
+	throttleStore      throttle.Store
+	auditLogRepository repository.AuditLogRepository
+	logger             utils.LoggerStrategy
+	cfg                *config.LoginThrottle
+}
+
+func (g *loginGuard) check(email, ip string) error {
+	now := time.Now()
+	if now.Before(g.throttleStore.BlockedUntil(accountKey(email))) || now.Before(g.throttleStore.BlockedUntil(ipKey(ip))) {
+		return repository.ErrLoginThrottled
+	}
+	return nil
+}
+
+func (g *loginGuard) fail(ctx context.Context, email, ip string, userId *uint) {
+	g.record(ctx, accountKey(email), g.cfg.AccountFreeAttempts, g.cfg.AccountLockoutAfter, &domain.AuditLog{
+		UserId: userId,
+		Action: domain.AuditAccountLocked,
+		IP:     ip,
+		Detail: email,
+	})
+	g.record(ctx, ipKey(ip), g.cfg.IPFreeAttempts, g.cfg.IPLockoutAfter, &domain.AuditLog{
+		Action: domain.AuditIPLocked,
+		IP:     ip,
+	})
+}
+
+// succeed forgets the account's failures. The IP's are kept, otherwise
+// logging into an account of one's own would reset them.
+func (g *loginGuard) succeed(email string) {
+	g.throttleStore.Reset(accountKey(email))
+}
+
+func (g *loginGuard) record(ctx context.Context, key string, freeAttempts, lockoutAfter int, auditLog *domain.AuditLog) {
+	failures := g.throttleStore.Fail(key, g.cfg.Window)
+	now := time.Now()
+
+	switch {
+	case lockoutAfter > 0 && failures >= lockoutAfter:
+		g.throttleStore.Block(key, now.Add(g.cfg.LockoutDuration))
+		g.logger.Warn("login locked out", "action", auditLog.Action, "ip", auditLog.IP, "failures", failures)
+		if err := g.auditLogRepository.CreateAuditLog(ctx, auditLog); err != nil {
+			g.logger.Error("failed to write audit log", "action", auditLog.Action, "error", err)
+		}
+	case failures > freeAttempts:
+		g.throttleStore.Block(key, now.Add(g.backoff(failures-freeAttempts)))
+	}
+}
+
+// backoff doubles the delay with every failure past the free attempts.
+func (g *loginGuard) backoff(failures int) time.Duration {
+	delay := g.cfg.BaseDelay
+	for i := 1; i < failures && delay < g.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, g.cfg.MaxDelay)
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func newLoginGuard(throttleStore throttle.Store, auditLogRepository repository.AuditLogRepository, logger utils.LoggerStrategy, cfg *config.Config) *loginGuard {
+	return &loginGuard{
+		throttleStore:      throttleStore,
+		auditLogRepository: auditLogRepository,
+		logger:             logger,
+		cfg:                &cfg.LoginThrottle,
+	}
+}
@@ -21,6 +21,9 @@ type MessageService interface {
 type messageService struct {
 	messageRepository repository.MessageRepository
 	privateRepository repository.PrivateRepository
+	blockRepository   repository.BlockRepository
+	botUpdateService  BotUpdateService
+	webhookService    WebhookService
 }
 
 func (m *messageService) SendMessage(ctx context.Context, input *dto.MessageRequest, senderId uint) (*dto.MessageResponse, error) {
@@ -33,13 +36,44 @@ func (m *messageService) SendMessage(ctx context.Context, input *dto.MessageRequ
 		return nil, fmt.Errorf("unauthorized to send message in this chat")
 	}
 
+	sender, recipient := &private.User1, &private.User2
+	if private.User2Id == senderId {
+		sender, recipient = &private.User2, &private.User1
+	}
+
+	if recipient.IsDeleted() {
+		return nil, repository.ErrAccountDeleted
+	}
+
+	if input.ReplyMarkup != nil && !sender.IsBot() {
+		return nil, repository.ErrReplyMarkupNotAllowed
+	}
+
+	blocked, err := m.blockRepository.IsBlocked(ctx, private.User1Id, private.User2Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check blocks: %w", err)
+	}
+	if blocked {
+		return nil, repository.ErrUserBlocked
+	}
+
 	message := m.toMessageDomain(input, senderId)
 
 	if err := m.messageRepository.CreateMessage(ctx, message); err != nil {
 		return nil, fmt.Errorf("failed to create message: %w", err)
 	}
 
-	return m.toMessageDTO(message), nil
+	// Bots don't hold a WebSocket, they receive messages as Bot API updates
+	if recipient.IsBot() {
+		m.botUpdateService.PushMessage(ctx, recipient, sender, message)
+	}
+
+	response := toMessageDTO(message)
+	m.webhookService.Publish(ctx, domain.WebhookEventMessageCreated, private, map[string]any{
+		"message": response,
+	})
+
+	return response, nil
 }
 
 func (m *messageService) GetMessage(ctx context.Context, messageId, userId uint) (*dto.MessageResponse, error) {
@@ -67,7 +101,7 @@ func (m *messageService) GetMessage(ctx context.Context, messageId, userId uint)
 		return nil, fmt.Errorf("unauthorized to view this message")
 	}
 
-	return m.toMessageDTO(message), nil
+	return toMessageDTO(message), nil
 }
 
 func (m *messageService) GetPrivateMessages(ctx context.Context, privateId, userId uint, page, limit int) (*dto.MessageListResponse, error) {
@@ -111,7 +145,7 @@ func (m *messageService) GetPrivateMessages(ctx context.Context, privateId, user
 	}
 
 	for i, msg := range messages {
-		response.Messages[i] = *m.toMessageDTO(&msg)
+		response.Messages[i] = *toMessageDTO(&msg)
 	}
 
 	return response, nil
@@ -137,7 +171,7 @@ func (m *messageService) GetUndeliveredMessages(ctx context.Context, privateId,
 
 	response := make([]dto.MessageResponse, len(messages))
 	for i, msg := range messages {
-		response[i] = *m.toMessageDTO(&msg)
+		response[i] = *toMessageDTO(&msg)
 	}
 
 	return response, nil
@@ -181,6 +215,11 @@ func (m *messageService) MarkMessageAsRead(ctx context.Context, messageId, userI
 		return fmt.Errorf("failed to mark message as read: %w", err)
 	}
 
+	message.Read = true
+	m.webhookService.Publish(ctx, domain.WebhookEventMessageRead, private, map[string]any{
+		"message": toMessageDTO(message),
+	})
+
 	return nil
 }
 
@@ -222,35 +261,95 @@ func (m *messageService) MarkMessageAsDelivered(ctx context.Context, messageId,
 		return fmt.Errorf("failed to mark message as delivered: %w", err)
 	}
 
+	message.Delivered = true
+	m.webhookService.Publish(ctx, domain.WebhookEventMessageDelivered, private, map[string]any{
+		"message": toMessageDTO(message),
+	})
+
 	return nil
 }
 
 func (m *messageService) toMessageDomain(input *dto.MessageRequest, senderId uint) *domain.Message {
+	format := domain.MessageFormat(input.Format)
+	if format == "" {
+		format = domain.MessageFormatPlain
+	}
+
+	var integrationName *string
+	if input.Integration != "" {
+		integrationName = &input.Integration
+	}
+
 	return &domain.Message{
-		FromId:      senderId,
-		PrivateId:   &input.PrivateId,
-		MessageType: domain.MessageType(input.MessageType),
-		Content:     input.Content,
-		Delivered:   false,
-		Read:        false,
+		FromId:          senderId,
+		PrivateId:       &input.PrivateId,
+		MessageType:     domain.MessageType(input.MessageType),
+		Content:         input.Content,
+		Format:          format,
+		Delivered:       false,
+		Read:            false,
+		ReplyMarkup:     toInlineKeyboardDomain(input.ReplyMarkup),
+		IntegrationName: integrationName,
 	}
 }
 
-func (m *messageService) toMessageDTO(message *domain.Message) *dto.MessageResponse {
-	return &dto.MessageResponse{
+func toMessageDTO(message *domain.Message) *dto.MessageResponse {
+	response := &dto.MessageResponse{
 		Id:          message.Id,
 		FromId:      message.FromId,
 		MessageType: string(message.MessageType),
 		Content:     message.Content,
+		Format:      string(message.Format),
 		Delivered:   message.Delivered,
 		Read:        message.Read,
+		ReplyMarkup: toInlineKeyboardDTO(message.ReplyMarkup),
 		CreatedAt:   message.CreatedAt,
 	}
+	if message.PrivateId != nil {
+		response.PrivateId = *message.PrivateId
+	}
+	if message.IntegrationName != nil {
+		response.IntegrationName = *message.IntegrationName
+	}
+	return response
+}
+
+func toInlineKeyboardDomain(markup *dto.InlineKeyboardMarkup) *domain.InlineKeyboardMarkup {
+	if markup == nil {
+		return nil
+	}
+
+	keyboard := make([][]domain.InlineKeyboardButton, len(markup.InlineKeyboard))
+	for i, row := range markup.InlineKeyboard {
+		keyboard[i] = make([]domain.InlineKeyboardButton, len(row))
+		for j, button := range row {
+			keyboard[i][j] = domain.InlineKeyboardButton(button)
+		}
+	}
+	return &domain.InlineKeyboardMarkup{InlineKeyboard: keyboard}
+}
+
+func toInlineKeyboardDTO(markup *domain.InlineKeyboardMarkup) *dto.InlineKeyboardMarkup {
+	if markup == nil {
+		return nil
+	}
+
+	keyboard := make([][]dto.InlineKeyboardButton, len(markup.InlineKeyboard))
+	for i, row := range markup.InlineKeyboard {
+		keyboard[i] = make([]dto.InlineKeyboardButton, len(row))
+		for j, button := range row {
+			keyboard[i][j] = dto.InlineKeyboardButton(button)
+		}
+	}
+	return &dto.InlineKeyboardMarkup{InlineKeyboard: keyboard}
 }
 
-func NewMessageService(messageRepository repository.MessageRepository, privateRepository repository.PrivateRepository) MessageService {
+func NewMessageService(messageRepository repository.MessageRepository, privateRepository repository.PrivateRepository, blockRepository repository.BlockRepository, botUpdateService BotUpdateService, webhookService WebhookService) MessageService {
 	return &messageService{
 		messageRepository: messageRepository,
 		privateRepository: privateRepository,
+		blockRepository:   blockRepository,
+		botUpdateService:  botUpdateService,
+		webhookService:    webhookService,
 	}
 }
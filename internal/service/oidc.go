@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/oidc"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type OIDCService interface {
+	Start(ctx context.Context, input *dto.OIDCStartRequest, platform string) (*dto.OIDCStartResponse, error)
+	Callback(ctx context.Context, input *dto.OIDCCallbackRequest, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error)
+}
+
+// oidcState is what a started login needs to be finished. It stays on the
+// server, the PKCE verifier must not travel through the browser.
+type oidcState struct {
+	verifier   string
+	nonce      string
+	platform   string
+	deviceName string
+	expiresAt  time.Time
+}
+
+type oidcService struct {
+	provider               *oidc.Provider
+	authService            AuthService
+	userRepository         repository.UserRepository
+	userIdentityRepository repository.UserIdentityRepository
+	logger                 utils.LoggerStrategy
+	cfg                    *config.Config
+
+	mu        sync.Mutex
+	states    map[string]*oidcState
+	lastSweep time.Time
+}
+
+func (o *oidcService) Start(ctx context.Context, input *dto.OIDCStartRequest, platform string) (*dto.OIDCStartResponse, error) {
+	if o.provider == nil {
+		return nil, repository.ErrOIDCNotConfigured
+	}
+
+	verifier, challenge, err := oidc.GenerateVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := o.provider.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		o.logger.Error("failed to start single sign-on", "error", err)
+		return nil, repository.ErrOIDCFailed
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	o.sweep(now)
+	o.states[state] = &oidcState{
+		verifier:   verifier,
+		nonce:      nonce,
+		platform:   platform,
+		deviceName: input.DeviceName,
+		expiresAt:  now.Add(o.cfg.OIDC.StateExpires),
+	}
+
+	return &dto.OIDCStartResponse{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresIn:        int64(o.cfg.OIDC.StateExpires.Seconds()),
+	}, nil
+}
+
+// Callback finishes a login started with Start. The provider's account is
+// matched by its subject, the first time by verified email: an existing user
+// with that email is linked, otherwise a new one is created. Only accounts
+// whose owner verified the email are linked, anyone could have signed up with
+// an address they don't own and kept the password.
+func (o *oidcService) Callback(ctx context.Context, input *dto.OIDCCallbackRequest, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error) {
+	if o.provider == nil {
+		return nil, nil, repository.ErrOIDCNotConfigured
+	}
+
+	state := o.takeState(input.State)
+	if state == nil || state.platform != device.Platform {
+		return nil, nil, repository.ErrInvalidOIDCState
+	}
+
+	idToken, err := o.provider.Exchange(ctx, input.Code, state.verifier)
+	if err != nil {
+		o.logger.Warn("single sign-on failed", "error", err)
+		return nil, nil, repository.ErrOIDCFailed
+	}
+	if idToken.Nonce != state.nonce {
+		return nil, nil, repository.ErrOIDCFailed
+	}
+
+	user, err := o.getOrCreateUser(ctx, idToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return o.authService.LoginUser(ctx, user, state.deviceName, device)
+}
+
+func (o *oidcService) getOrCreateUser(ctx context.Context, idToken *oidc.IDToken) (*domain.User, error) {
+	identity, err := o.userIdentityRepository.GetUserIdentity(ctx, idToken.Issuer, idToken.Subject)
+	if err == nil {
+		return &identity.User, nil
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get identity: %w", err)
+	}
+
+	if !idToken.EmailVerified || idToken.Email == "" {
+		return nil, repository.ErrOIDCEmailNotVerified
+	}
+
+	identity = &domain.UserIdentity{
+		Provider: idToken.Issuer,
+		Subject:  idToken.Subject,
+		Email:    idToken.Email,
+	}
+
+	user, err := o.userRepository.GetUserByEmail(ctx, idToken.Email)
+	switch {
+	case err == nil:
+		if user.IsBot() {
+			return nil, repository.ErrOIDCFailed
+		}
+		if !user.IsEmailVerified() {
+			return nil, repository.ErrOIDCAccountNotVerified
+		}
+
+		identity.UserId = user.Id
+		if err := o.userIdentityRepository.CreateUserIdentity(ctx, identity); err != nil {
+			return nil, fmt.Errorf("failed to link identity: %w", err)
+		}
+		return user, nil
+	case errors.Is(err, repository.ErrRecordNotFound):
+		user, err := o.toUserDomain(idToken)
+		if err != nil {
+			return nil, err
+		}
+		if err := o.userIdentityRepository.CreateUserWithIdentity(ctx, user, identity); err != nil {
+			return nil, fmt.Errorf("failed to create user: %w", err)
+		}
+		return user, nil
+	default:
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+}
+
+func (o *oidcService) takeState(key string) *oidcState {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	state, ok := o.states[key]
+	if !ok {
+		return nil
+	}
+	delete(o.states, key)
+
+	if !time.Now().Before(state.expiresAt) {
+		return nil
+	}
+	return state
+}
+
+// sweep drops logins that were started but never finished.
+func (o *oidcService) sweep(now time.Time) {
+	if now.Sub(o.lastSweep) < time.Minute {
+		return
+	}
+	o.lastSweep = now
+
+	for key, state := range o.states {
+		if !now.Before(state.expiresAt) {
+			delete(o.states, key)
+		}
+	}
+}
+
+// toUserDomain gives new users a random password nobody knows, they can set
+// one through the password reset flow.
+func (o *oidcService) toUserDomain(idToken *oidc.IDToken) (*domain.User, error) {
+	password, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	name := strings.TrimSpace(idToken.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(idToken.Email, "@")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		name = string([]rune(name)[:100])
+	}
+
+	now := time.Now()
+	return &domain.User{
+		Name:            name,
+		Email:           idToken.Email,
+		Password:        hashedPassword,
+		EmailVerifiedAt: &now,
+	}, nil
+}
+
+// NewOIDCService takes a nil provider when single sign-on isn't configured.
+func NewOIDCService(provider *oidc.Provider, authService AuthService, userRepository repository.UserRepository, userIdentityRepository repository.UserIdentityRepository, logger utils.LoggerStrategy, cfg *config.Config) OIDCService {
+	return &oidcService{
+		provider:               provider,
+		authService:            authService,
+		userRepository:         userRepository,
+		userIdentityRepository: userIdentityRepository,
+		logger:                 logger,
+		cfg:                    cfg,
+		states:                 make(map[string]*oidcState),
+		lastSweep:              time.Now(),
+	}
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/oidc"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+)
+
+const (
+	testOIDCClientId    = "telegopher"
+	testOIDCRedirectURL = "http://localhost:3000/oidc/callback"
+	testOIDCKeyId       = "test-key"
+)
+
+// oidcTestProvider is an httptest identity provider serving discovery, the
+// authorization and token endpoints and its keys. Its one account logs in
+// as soon as it is sent to the authorization endpoint.
+type oidcTestProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu            sync.Mutex
+	email         string
+	emailVerified bool
+	codes         map[string]oidcTestCode
+	tokenRequests int
+}
+
+// oidcTestCode is what the provider remembers about an authorization code
+// until it is redeemed.
+type oidcTestCode struct {
+	challenge string
+	nonce     string
+}
+
+func newOIDCTestProvider(t *testing.T) *oidcTestProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	p := &oidcTestProvider{
+		key:           key,
+		email:         "jane@example.com",
+		emailVerified: true,
+		codes:         make(map[string]oidcTestCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /jwks", p.jwks)
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *oidcTestProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeTestJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.server.URL,
+		"authorization_endpoint": p.server.URL + "/authorize",
+		"token_endpoint":         p.server.URL + "/token",
+		"jwks_uri":               p.server.URL + "/jwks",
+	})
+}
+
+func (p *oidcTestProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != testOIDCClientId || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code := rand.Text()
+	p.mu.Lock()
+	p.codes[code] = oidcTestCode{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	p.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *oidcTestProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	p.tokenRequests++
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	email, emailVerified := p.email, p.emailVerified
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge ||
+		r.PostForm.Get("redirect_uri") != testOIDCRedirectURL || r.PostForm.Get("client_id") != testOIDCClientId {
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.server.URL,
+		"sub":            "subject-1",
+		"aud":            testOIDCClientId,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute).Unix(),
+		"nonce":          code.nonce,
+		"email":          email,
+		"email_verified": emailVerified,
+		"name":           "Jane Doe",
+	})
+	token.Header["kid"] = testOIDCKeyId
+
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeTestJSON(w, http.StatusOK, map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+func (p *oidcTestProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeTestJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testOIDCKeyId,
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+// login follows authorizationURL the way a browser would and returns the
+// code the provider redirects back with.
+func (p *oidcTestProvider) login(t *testing.T, authorizationURL string) string {
+	t.Helper()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authorizationURL)
+	if err != nil {
+		t.Fatalf("failed to log in at the provider: %v", err)
+	}
+	defer resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("provider didn't redirect back: %v", err)
+	}
+	return location.Query().Get("code")
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fakeOIDCUserStore stands in for both the user and the identity repository.
+type fakeOIDCUserStore struct {
+	repository.UserRepository
+	repository.UserIdentityRepository
+
+	users      []*domain.User
+	identities []domain.UserIdentity
+}
+
+func (f *fakeOIDCUserStore) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, repository.ErrRecordNotFound
+}
+
+func (f *fakeOIDCUserStore) GetUserIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, repository.ErrRecordNotFound
+}
+
+func (f *fakeOIDCUserStore) CreateUserIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	f.identities = append(f.identities, *identity)
+	return nil
+}
+
+func (f *fakeOIDCUserStore) CreateUserWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	user.Id = uint(len(f.users) + 1)
+	identity.UserId = user.Id
+	identity.User = *user
+
+	f.users = append(f.users, user)
+	f.identities = append(f.identities, *identity)
+	return nil
+}
+
+// fakeOIDCAuthService records who was logged in instead of starting a session.
+type fakeOIDCAuthService struct {
+	AuthService
+
+	loggedIn []*domain.User
+}
+
+func (f *fakeOIDCAuthService) LoginUser(ctx context.Context, user *domain.User, deviceName string, device *dto.DeviceInfo) (*dto.LoginResponse, *dto.TwoFactorChallengeResponse, error) {
+	f.loggedIn = append(f.loggedIn, user)
+	return &dto.LoginResponse{AccessToken: "access-token"}, nil, nil
+}
+
+func newTestOIDCService(t *testing.T) (OIDCService, *oidcTestProvider, *fakeOIDCUserStore, *fakeOIDCAuthService) {
+	t.Helper()
+
+	provider := newOIDCTestProvider(t)
+	store := &fakeOIDCUserStore{}
+	authService := &fakeOIDCAuthService{}
+
+	cfg := &config.Config{
+		OIDC: config.OIDC{StateExpires: time.Minute},
+	}
+
+	logger := utils.NewLoggerContext(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service := NewOIDCService(oidc.NewProvider(
+		oidc.WithIssuer(provider.server.URL),
+		oidc.WithClientId(testOIDCClientId),
+		oidc.WithRedirectURL(testOIDCRedirectURL),
+	), authService, store, store, logger, cfg)
+	return service, provider, store, authService
+}
+
+var testWebDevice = &dto.DeviceInfo{Platform: string(domain.PlatformWeb)}
+
+func TestOIDCLogin(t *testing.T) {
+	service, provider, store, authService := newTestOIDCService(t)
+	ctx := context.Background()
+
+	start, err := service.Start(ctx, &dto.OIDCStartRequest{DeviceName: "laptop"}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	code := provider.login(t, start.AuthorizationURL)
+
+	login, challenge, err := service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: start.State}, testWebDevice)
+	if err != nil {
+		t.Fatalf("callback failed: %v", err)
+	}
+	if login == nil || challenge != nil {
+		t.Fatalf("got login %v and challenge %v, want a login", login, challenge)
+	}
+
+	if len(authService.loggedIn) != 1 {
+		t.Fatalf("got %d logins, want 1", len(authService.loggedIn))
+	}
+	user := authService.loggedIn[0]
+	if user.Email != "jane@example.com" || user.Name != "Jane Doe" || !user.IsEmailVerified() {
+		t.Errorf("got user %q <%s> verified %t", user.Name, user.Email, user.IsEmailVerified())
+	}
+
+	if len(store.identities) != 1 {
+		t.Fatalf("got %d identities, want 1", len(store.identities))
+	}
+	if identity := store.identities[0]; identity.Provider != provider.server.URL || identity.Subject != "subject-1" || identity.UserId != user.Id {
+		t.Errorf("got identity %+v", identity)
+	}
+
+	// The state is used up
+	if _, _, err := service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: start.State}, testWebDevice); !errors.Is(err, repository.ErrInvalidOIDCState) {
+		t.Fatalf("got %v replaying the callback, want %v", err, repository.ErrInvalidOIDCState)
+	}
+}
+
+func TestOIDCStateMismatch(t *testing.T) {
+	service, provider, _, authService := newTestOIDCService(t)
+	ctx := context.Background()
+
+	start, err := service.Start(ctx, &dto.OIDCStartRequest{}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	code := provider.login(t, start.AuthorizationURL)
+
+	_, _, err = service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: "forged-state"}, testWebDevice)
+	if !errors.Is(err, repository.ErrInvalidOIDCState) {
+		t.Fatalf("got %v, want %v", err, repository.ErrInvalidOIDCState)
+	}
+
+	// A state started on another platform doesn't match either
+	_, _, err = service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: start.State}, &dto.DeviceInfo{Platform: string(domain.PlatformMobile)})
+	if !errors.Is(err, repository.ErrInvalidOIDCState) {
+		t.Fatalf("got %v from another platform, want %v", err, repository.ErrInvalidOIDCState)
+	}
+
+	if provider.tokenRequests != 0 {
+		t.Errorf("got %d token requests, want none", provider.tokenRequests)
+	}
+	if len(authService.loggedIn) != 0 {
+		t.Errorf("got %d logins, want none", len(authService.loggedIn))
+	}
+}
+
+func TestOIDCVerifierMismatch(t *testing.T) {
+	service, provider, store, authService := newTestOIDCService(t)
+	ctx := context.Background()
+
+	// A code issued for one login is injected into another, whose verifier
+	// doesn't match the code's challenge
+	victim, err := service.Start(ctx, &dto.OIDCStartRequest{}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	code := provider.login(t, victim.AuthorizationURL)
+
+	attacker, err := service.Start(ctx, &dto.OIDCStartRequest{}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	_, _, err = service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: attacker.State}, testWebDevice)
+	if !errors.Is(err, repository.ErrOIDCFailed) {
+		t.Fatalf("got %v, want %v", err, repository.ErrOIDCFailed)
+	}
+
+	if provider.tokenRequests != 1 {
+		t.Errorf("got %d token requests, want 1", provider.tokenRequests)
+	}
+	if len(store.users) != 0 || len(authService.loggedIn) != 0 {
+		t.Errorf("got %d users and %d logins, want none", len(store.users), len(authService.loggedIn))
+	}
+}
+
+func TestOIDCUnverifiedEmail(t *testing.T) {
+	service, provider, store, authService := newTestOIDCService(t)
+	ctx := context.Background()
+	provider.emailVerified = false
+
+	start, err := service.Start(ctx, &dto.OIDCStartRequest{}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	code := provider.login(t, start.AuthorizationURL)
+
+	_, _, err = service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: start.State}, testWebDevice)
+	if !errors.Is(err, repository.ErrOIDCEmailNotVerified) {
+		t.Fatalf("got %v, want %v", err, repository.ErrOIDCEmailNotVerified)
+	}
+
+	if len(store.users) != 0 || len(store.identities) != 0 || len(authService.loggedIn) != 0 {
+		t.Errorf("got %d users, %d identities and %d logins, want none", len(store.users), len(store.identities), len(authService.loggedIn))
+	}
+}
+
+func TestOIDCLinksVerifiedAccount(t *testing.T) {
+	service, provider, store, authService := newTestOIDCService(t)
+	ctx := context.Background()
+
+	verifiedAt := time.Now().Add(-time.Hour)
+	existing := &domain.User{Id: 7, Name: "Jane", Email: "jane@example.com", EmailVerifiedAt: &verifiedAt}
+	store.users = append(store.users, existing)
+
+	start, err := service.Start(ctx, &dto.OIDCStartRequest{}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	code := provider.login(t, start.AuthorizationURL)
+
+	if _, _, err := service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: start.State}, testWebDevice); err != nil {
+		t.Fatalf("callback failed: %v", err)
+	}
+
+	if len(authService.loggedIn) != 1 || authService.loggedIn[0] != existing {
+		t.Fatalf("got logins %v, want the existing user", authService.loggedIn)
+	}
+	if len(store.users) != 1 {
+		t.Errorf("got %d users, want the existing one only", len(store.users))
+	}
+	if len(store.identities) != 1 || store.identities[0].UserId != existing.Id || store.identities[0].Subject != "subject-1" {
+		t.Fatalf("got identities %+v, want one linked to user %d", store.identities, existing.Id)
+	}
+}
+
+func TestOIDCRefusesUnverifiedAccount(t *testing.T) {
+	service, provider, store, authService := newTestOIDCService(t)
+	ctx := context.Background()
+
+	// Someone signed up with the address but never proved they own it
+	store.users = append(store.users, &domain.User{Id: 7, Name: "Squatter", Email: "jane@example.com"})
+
+	start, err := service.Start(ctx, &dto.OIDCStartRequest{}, string(domain.PlatformWeb))
+	if err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	code := provider.login(t, start.AuthorizationURL)
+
+	_, _, err = service.Callback(ctx, &dto.OIDCCallbackRequest{Code: code, State: start.State}, testWebDevice)
+	if !errors.Is(err, repository.ErrOIDCAccountNotVerified) {
+		t.Fatalf("got %v, want %v", err, repository.ErrOIDCAccountNotVerified)
+	}
+
+	if store.users[0].IsEmailVerified() {
+		t.Error("the existing account was marked verified")
+	}
+	if len(store.identities) != 0 || len(authService.loggedIn) != 0 {
+		t.Errorf("got %d identities and %d logins, want none", len(store.identities), len(authService.loggedIn))
+	}
+}
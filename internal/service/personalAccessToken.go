@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"time"
+)
+
+// personalAccessTokenUseInterval is how precisely the last use of a token is
+// tracked.
+const personalAccessTokenUseInterval = time.Minute
+
+type PersonalAccessTokenService interface {
+	CreateToken(ctx context.Context, userId uint, input *dto.PersonalAccessTokenRequest) (*dto.CreatedPersonalAccessTokenResponse, error)
+	GetTokens(ctx context.Context, userId uint) ([]dto.PersonalAccessTokenResponse, error)
+	RevokeToken(ctx context.Context, userId, tokenId uint) error
+
+	Authenticate(ctx context.Context, token string) (*domain.PersonalAccessToken, error)
+}
+
+type personalAccessTokenService struct {
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	logger                        utils.LoggerStrategy
+}
+
+func (p *personalAccessTokenService) CreateToken(ctx context.Context, userId uint, input *dto.PersonalAccessTokenRequest) (*dto.CreatedPersonalAccessTokenResponse, error) {
+	secret, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	token := domain.PersonalAccessTokenPrefix + secret
+
+	scopes := make([]domain.TokenScope, len(input.Scopes))
+	for i, scope := range input.Scopes {
+		scopes[i] = domain.TokenScope(scope)
+	}
+
+	personalAccessToken := &domain.PersonalAccessToken{
+		UserId:    userId,
+		Name:      input.Name,
+		TokenHash: utils.HashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	if err := p.personalAccessTokenRepository.CreatePersonalAccessToken(ctx, personalAccessToken); err != nil {
+		return nil, fmt.Errorf("failed to create personal access token: %w", err)
+	}
+
+	return &dto.CreatedPersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: *toPersonalAccessTokenResponse(personalAccessToken),
+		Token:                       token,
+	}, nil
+}
+
+func (p *personalAccessTokenService) GetTokens(ctx context.Context, userId uint) ([]dto.PersonalAccessTokenResponse, error) {
+	tokens, err := p.personalAccessTokenRepository.GetPersonalAccessTokensByUserId(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal access tokens: %w", err)
+	}
+
+	responses := make([]dto.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = *toPersonalAccessTokenResponse(&token)
+	}
+	return responses, nil
+}
+
+func (p *personalAccessTokenService) RevokeToken(ctx context.Context, userId, tokenId uint) error {
+	return p.personalAccessTokenRepository.DeletePersonalAccessToken(ctx, tokenId, userId)
+}
+
+// Authenticate returns the token with its user, and notes that it was used.
+func (p *personalAccessTokenService) Authenticate(ctx context.Context, token string) (*domain.PersonalAccessToken, error) {
+	personalAccessToken, err := p.personalAccessTokenRepository.GetPersonalAccessTokenByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, repository.ErrInvalidPersonalAccessToken
+		}
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+
+	now := time.Now()
+	if personalAccessToken.IsExpired(now) || personalAccessToken.User.IsDeleted() || personalAccessToken.User.IsSuspended(now) {
+		return nil, repository.ErrInvalidPersonalAccessToken
+	}
+
+	if err := p.personalAccessTokenRepository.MarkPersonalAccessTokenUsed(ctx, personalAccessToken.Id, now, personalAccessTokenUseInterval); err != nil {
+		p.logger.Error("failed to track personal access token use", "token", personalAccessToken.Id, "error", err)
+	}
+	return personalAccessToken, nil
+}
+
+func toPersonalAccessTokenResponse(token *domain.PersonalAccessToken) *dto.PersonalAccessTokenResponse {
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &dto.PersonalAccessTokenResponse{
+		Id:         token.Id,
+		Name:       token.Name,
+		Scopes:     scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func NewPersonalAccessTokenService(personalAccessTokenRepository repository.PersonalAccessTokenRepository, logger utils.LoggerStrategy) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		logger:                        logger,
+	}
+}
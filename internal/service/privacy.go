@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"slices"
+)
+
+// PrivacyService stores users' privacy rules and answers who passes them. The
+// batch lookups let callers check many users with a constant number of
+// queries.
+type PrivacyService interface {
+	GetPrivacyRules(ctx context.Context, userId uint) ([]dto.PrivacyRuleResponse, error)
+	UpdatePrivacyRule(ctx context.Context, userId uint, key string, input *dto.PrivacyRuleRequest) (*dto.PrivacyRuleResponse, error)
+
+	CanSee(ctx context.Context, viewerId, ownerId uint, key domain.PrivacyKey) (bool, error)
+	VisibleOwners(ctx context.Context, viewerId uint, ownerIds []uint, key domain.PrivacyKey) (map[uint]bool, error)
+	AllowedViewers(ctx context.Context, ownerId uint, viewerIds []uint, key domain.PrivacyKey) (map[uint]bool, error)
+}
+
+type privacyService struct {
+	privacyRepository repository.PrivacyRepository
+	contactRepository repository.ContactRepository
+}
+
+func (p *privacyService) GetPrivacyRules(ctx context.Context, userId uint) ([]dto.PrivacyRuleResponse, error) {
+	rules, err := p.privacyRepository.GetPrivacyRules(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy rules: %w", err)
+	}
+
+	responses := make([]dto.PrivacyRuleResponse, len(domain.PrivacyKeys))
+	for i, key := range domain.PrivacyKeys {
+		rule := domain.DefaultPrivacyRule(userId, key)
+		if idx := slices.IndexFunc(rules, func(r domain.PrivacyRule) bool { return r.Key == key }); idx >= 0 {
+			rule = &rules[idx]
+		}
+		responses[i] = *toPrivacyRuleResponse(rule)
+	}
+	return responses, nil
+}
+
+func (p *privacyService) UpdatePrivacyRule(ctx context.Context, userId uint, key string, input *dto.PrivacyRuleRequest) (*dto.PrivacyRuleResponse, error) {
+	rule := domain.DefaultPrivacyRule(userId, domain.PrivacyKey(key))
+	rule.Level = domain.PrivacyLevel(input.Level)
+	for _, id := range input.AllowUserIds {
+		if id != userId {
+			rule.AllowUserIds = append(rule.AllowUserIds, id)
+		}
+	}
+	for _, id := range input.DenyUserIds {
+		if id != userId {
+			rule.DenyUserIds = append(rule.DenyUserIds, id)
+		}
+	}
+
+	if err := p.privacyRepository.UpsertPrivacyRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update privacy rule: %w", err)
+	}
+
+	return toPrivacyRuleResponse(rule), nil
+}
+
+// CanSee reports whether viewerId passes ownerId's rule for key.
+func (p *privacyService) CanSee(ctx context.Context, viewerId, ownerId uint, key domain.PrivacyKey) (bool, error) {
+	if viewerId == ownerId {
+		return true, nil
+	}
+
+	visible, err := p.VisibleOwners(ctx, viewerId, []uint{ownerId}, key)
+	if err != nil {
+		return false, err
+	}
+	return visible[ownerId], nil
+}
+
+// VisibleOwners reports for each of ownerIds whether viewerId passes their
+// rule for key.
+func (p *privacyService) VisibleOwners(ctx context.Context, viewerId uint, ownerIds []uint, key domain.PrivacyKey) (map[uint]bool, error) {
+	rules, err := p.privacyRepository.GetPrivacyRulesForUsers(ctx, ownerIds, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy rules: %w", err)
+	}
+
+	byOwner := make(map[uint]*domain.PrivacyRule, len(rules))
+	contactOwnerIds := make([]uint, 0)
+	for i, rule := range rules {
+		byOwner[rule.UserId] = &rules[i]
+		if rule.Level == domain.PrivacyLevelContacts {
+			contactOwnerIds = append(contactOwnerIds, rule.UserId)
+		}
+	}
+
+	// Only rules limited to contacts need to know who has viewerId as contact
+	hasViewer, err := p.contactRepository.GetOwnerIdsHavingContact(ctx, viewerId, contactOwnerIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacts: %w", err)
+	}
+
+	visible := make(map[uint]bool, len(ownerIds))
+	for _, ownerId := range ownerIds {
+		rule, ok := byOwner[ownerId]
+		if !ok {
+			rule = domain.DefaultPrivacyRule(ownerId, key)
+		}
+		visible[ownerId] = rule.Allows(viewerId, slices.Contains(hasViewer, ownerId))
+	}
+	return visible, nil
+}
+
+// AllowedViewers reports for each of viewerIds whether they pass ownerId's
+// rule for key.
+func (p *privacyService) AllowedViewers(ctx context.Context, ownerId uint, viewerIds []uint, key domain.PrivacyKey) (map[uint]bool, error) {
+	rules, err := p.privacyRepository.GetPrivacyRulesForUsers(ctx, []uint{ownerId}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy rules: %w", err)
+	}
+
+	rule := domain.DefaultPrivacyRule(ownerId, key)
+	if len(rules) > 0 {
+		rule = &rules[0]
+	}
+
+	contacts := make(map[uint]struct{})
+	if rule.Level == domain.PrivacyLevelContacts {
+		contactIds, err := p.contactRepository.GetContactIds(ctx, ownerId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contacts: %w", err)
+		}
+		for _, id := range contactIds {
+			contacts[id] = struct{}{}
+		}
+	}
+
+	allowed := make(map[uint]bool, len(viewerIds))
+	for _, viewerId := range viewerIds {
+		_, isContact := contacts[viewerId]
+		allowed[viewerId] = rule.Allows(viewerId, isContact)
+	}
+	return allowed, nil
+}
+
+func toPrivacyRuleResponse(rule *domain.PrivacyRule) *dto.PrivacyRuleResponse {
+	return &dto.PrivacyRuleResponse{
+		Key:          string(rule.Key),
+		Level:        string(rule.Level),
+		AllowUserIds: rule.AllowUserIds,
+		DenyUserIds:  rule.DenyUserIds,
+	}
+}
+
+func NewPrivacyService(privacyRepository repository.PrivacyRepository, contactRepository repository.ContactRepository) PrivacyService {
+	return &privacyService{
+		privacyRepository: privacyRepository,
+		contactRepository: contactRepository,
+	}
+}
@@ -18,6 +18,8 @@ type PrivateService interface {
 type privateService struct {
 	privateRepository repository.PrivateRepository
 	userRepository    repository.UserRepository
+	blockRepository   repository.BlockRepository
+	privacyService    PrivacyService
 }
 
 func (p *privateService) CreatePrivate(ctx context.Context, user1Id, user2Id uint) (*dto.PrivateResponse, error) {
@@ -29,6 +31,22 @@ func (p *privateService) CreatePrivate(ctx context.Context, user1Id, user2Id uin
 		return nil, err
 	}
 
+	blocked, err := p.blockRepository.IsBlocked(ctx, user1Id, user2Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check blocks: %w", err)
+	}
+	if blocked {
+		return nil, repository.ErrUserBlocked
+	}
+
+	allowed, err := p.privacyService.CanSee(ctx, user1Id, user2Id, domain.PrivacyKeyPrivateChats)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, repository.ErrPrivacyRestricted
+	}
+
 	exists, err := p.privateRepository.CheckPrivateExists(ctx, user1Id, user2Id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing private: %w", err)
@@ -94,9 +112,13 @@ func (p *privateService) validateUsers(ctx context.Context, user1Id, user2Id uin
 
 func (p *privateService) verifyUsersExist(ctx context.Context, userIds ...uint) error {
 	for _, userId := range userIds {
-		if _, err := p.userRepository.GetUserById(ctx, userId); err != nil {
+		user, err := p.userRepository.GetUserById(ctx, userId)
+		if err != nil {
 			return fmt.Errorf("user %d not found: %w", userId, err)
 		}
+		if user.IsDeleted() {
+			return repository.ErrAccountDeleted
+		}
 	}
 	return nil
 }
@@ -114,9 +136,11 @@ func (p *privateService) toPrivateResponse(private *domain.Private) *dto.Private
 	}
 }
 
-func NewPrivateService(privateRepository repository.PrivateRepository, userRepository repository.UserRepository) PrivateService {
+func NewPrivateService(privateRepository repository.PrivateRepository, userRepository repository.UserRepository, blockRepository repository.BlockRepository, privacyService PrivacyService) PrivateService {
 	return &privateService{
 		privateRepository: privateRepository,
 		userRepository:    userRepository,
+		blockRepository:   blockRepository,
+		privacyService:    privacyService,
 	}
 }
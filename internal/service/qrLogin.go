@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"sync"
+	"time"
+)
+
+// QRLoginService logs a new device in with the approval of one that is
+// already logged in. The new device gets a token to show as a QR code and
+// waits for the login on the channel it got along with it; the token only
+// works while it waits and only once.
+type QRLoginService interface {
+	Start(deviceName string, device *dto.DeviceInfo) (*dto.QRLoginTokenResponse, <-chan *dto.LoginResponse, error)
+	Cancel(token string)
+	Approve(ctx context.Context, userId uint, input *dto.QRLoginApproveRequest) error
+}
+
+type qrLogin struct {
+	deviceName string
+	device     *dto.DeviceInfo
+	expiresAt  time.Time
+	result     chan *dto.LoginResponse
+}
+
+type qrLoginService struct {
+	authService AuthService
+	rateLimiter *utils.RateLimiter
+	cfg         *config.Config
+
+	mu      sync.Mutex
+	pending map[string]*qrLogin
+}
+
+func (q *qrLoginService) Start(deviceName string, device *dto.DeviceInfo) (*dto.QRLoginTokenResponse, <-chan *dto.LoginResponse, error) {
+	if !q.rateLimiter.Allow(device.IP) {
+		return nil, nil, repository.ErrQRLoginRateLimited
+	}
+
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	login := &qrLogin{
+		deviceName: deviceName,
+		device:     device,
+		expiresAt:  time.Now().Add(q.cfg.QRLogin.TokenExpires),
+		result:     make(chan *dto.LoginResponse, 1),
+	}
+
+	q.mu.Lock()
+	q.pending[token] = login
+	q.mu.Unlock()
+
+	return &dto.QRLoginTokenResponse{
+		Token:     token,
+		ExpiresIn: int64(q.cfg.QRLogin.TokenExpires.Seconds()),
+	}, login.result, nil
+}
+
+// Cancel drops a token nobody waits for anymore. Every Start must be followed
+// by a Cancel once the device stops waiting.
+func (q *qrLoginService) Cancel(token string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.pending, token)
+}
+
+// Approve logs the waiting device into userId's account. The token is used
+// up whether or not the login succeeds.
+func (q *qrLoginService) Approve(ctx context.Context, userId uint, input *dto.QRLoginApproveRequest) error {
+	q.mu.Lock()
+	login, ok := q.pending[input.Token]
+	delete(q.pending, input.Token)
+	q.mu.Unlock()
+
+	if !ok || time.Now().After(login.expiresAt) {
+		return repository.ErrInvalidQRLoginToken
+	}
+
+	response, err := q.authService.LoginLinkedDevice(ctx, userId, login.deviceName, login.device)
+	if err != nil {
+		return fmt.Errorf("failed to log the new device in: %w", err)
+	}
+
+	login.result <- response
+	return nil
+}
+
+func NewQRLoginService(authService AuthService, cfg *config.Config) QRLoginService {
+	return &qrLoginService{
+		authService: authService,
+		rateLimiter: utils.NewRateLimiter(float64(cfg.QRLogin.IPRatePerMinute)/60, cfg.QRLogin.IPRatePerMinute),
+		cfg:         cfg,
+		pending:     make(map[string]*qrLogin),
+	}
+}
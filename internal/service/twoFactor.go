@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"strconv"
+	"time"
+)
+
+type TwoFactorService interface {
+	Enroll(ctx context.Context, userId uint) (*dto.TwoFactorEnrollResponse, error)
+	Confirm(ctx context.Context, userId uint, input *dto.TwoFactorCodeRequest) error
+	Disable(ctx context.Context, userId uint, input *dto.DisableTwoFactorRequest) error
+
+	IsEnabled(ctx context.Context, userId uint) (bool, error)
+	VerifyCode(ctx context.Context, userId uint, code string) error
+}
+
+type twoFactorService struct {
+	twoFactorRepository repository.TwoFactorRepository
+	userRepository      repository.UserRepository
+	rateLimiter         *utils.RateLimiter
+	cfg                 *config.Config
+}
+
+// Enroll starts over with a new secret and recovery codes as long as the
+// enrollment isn't confirmed.
+func (t *twoFactorService) Enroll(ctx context.Context, userId uint) (*dto.TwoFactorEnrollResponse, error) {
+	user, err := t.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	enabled, err := t.IsEnabled(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, repository.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, t.cfg.TwoFactor.RecoveryCodes)
+	recoveryCodes := make([]domain.RecoveryCode, len(codes))
+	for i := range codes {
+		if codes[i], err = utils.GenerateRecoveryCode(); err != nil {
+			return nil, err
+		}
+		recoveryCodes[i] = domain.RecoveryCode{
+			UserId:   userId,
+			CodeHash: utils.HashToken(utils.NormalizeRecoveryCode(codes[i])),
+		}
+	}
+
+	if err := t.twoFactorRepository.SaveTwoFactor(ctx, &domain.TwoFactor{UserId: userId, Secret: secret}, recoveryCodes); err != nil {
+		return nil, fmt.Errorf("failed to save two-factor enrollment: %w", err)
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:        secret,
+		OTPAuthURI:    utils.TOTPURI(t.cfg.TwoFactor.Issuer, user.Email, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Confirm enables two-factor authentication once the user shows their
+// authenticator produces valid codes.
+func (t *twoFactorService) Confirm(ctx context.Context, userId uint, input *dto.TwoFactorCodeRequest) error {
+	twoFactor, err := t.getTwoFactor(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorNotEnabled) {
+			return repository.ErrTwoFactorNotEnrolled
+		}
+		return err
+	}
+	if twoFactor.IsEnabled() {
+		return repository.ErrTwoFactorAlreadyEnabled
+	}
+
+	if !t.allow(userId) {
+		return repository.ErrTwoFactorRateLimited
+	}
+
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, input.Code, time.Now())
+	if !ok {
+		return repository.ErrInvalidTwoFactorCode
+	}
+
+	confirmed, err := t.twoFactorRepository.ConfirmTwoFactor(ctx, userId, step)
+	if err != nil {
+		return fmt.Errorf("failed to confirm two-factor authentication: %w", err)
+	}
+	if !confirmed {
+		return repository.ErrTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+func (t *twoFactorService) Disable(ctx context.Context, userId uint, input *dto.DisableTwoFactorRequest) error {
+	user, err := t.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !utils.CheckPasswordHash(user.Password, input.Password) {
+		return repository.ErrIncorrectPassword
+	}
+
+	if err := t.VerifyCode(ctx, userId, input.Code); err != nil {
+		return err
+	}
+
+	if err := t.twoFactorRepository.DeleteTwoFactor(ctx, userId); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+func (t *twoFactorService) IsEnabled(ctx context.Context, userId uint) (bool, error) {
+	twoFactor, err := t.getTwoFactor(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorNotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+	return twoFactor.IsEnabled(), nil
+}
+
+// VerifyCode accepts a TOTP code or an unused recovery code, each only once.
+// Attempts are rate limited per user, six digits are quick to guess otherwise.
+func (t *twoFactorService) VerifyCode(ctx context.Context, userId uint, code string) error {
+	twoFactor, err := t.getTwoFactor(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.IsEnabled() {
+		return repository.ErrTwoFactorNotEnabled
+	}
+
+	if !t.allow(userId) {
+		return repository.ErrTwoFactorRateLimited
+	}
+
+	if step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		used, err := t.twoFactorRepository.UseTwoFactorStep(ctx, userId, step)
+		if err != nil {
+			return fmt.Errorf("failed to use two-factor code: %w", err)
+		}
+		if !used {
+			return repository.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := t.twoFactorRepository.UseRecoveryCode(ctx, userId, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if !used {
+		return repository.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (t *twoFactorService) getTwoFactor(ctx context.Context, userId uint) (*domain.TwoFactor, error) {
+	twoFactor, err := t.twoFactorRepository.GetTwoFactor(ctx, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, repository.ErrTwoFactorNotEnabled
+		}
+		return nil, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	return twoFactor, nil
+}
+
+func (t *twoFactorService) allow(userId uint) bool {
+	return t.rateLimiter.Allow(strconv.FormatUint(uint64(userId), 10))
+}
+
+func NewTwoFactorService(twoFactorRepository repository.TwoFactorRepository, userRepository repository.UserRepository, cfg *config.Config) TwoFactorService {
+	return &twoFactorService{
+		twoFactorRepository: twoFactorRepository,
+		userRepository:      userRepository,
+		rateLimiter:         utils.NewRateLimiter(float64(cfg.TwoFactor.AttemptsPerMinute)/60, cfg.TwoFactor.AttemptsPerMinute),
+		cfg:                 cfg,
+	}
+}
@@ -1,41 +1,391 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/infra/filestore"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	avatarSize = 512
+	// avatarMaxPixels guards against images that are small on disk but decode
+	// into huge bitmaps.
+	avatarMaxPixels = 50_000_000
 )
 
 type UserService interface {
-	GetUserById(ctx context.Context, id uint) (*dto.UserResponse, error)
+	GetUserById(ctx context.Context, id, viewerId uint) (*dto.UserResponse, error)
+	EnsureActive(ctx context.Context, userId uint) error
+	UpdateProfile(ctx context.Context, userId uint, input *dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	UpdateAvatar(ctx context.Context, userId uint, r io.Reader) (*dto.UserResponse, error)
+	DeleteAvatar(ctx context.Context, userId uint) (*dto.UserResponse, error)
+
+	SetUsername(ctx context.Context, userId uint, input *dto.UsernameRequest) (*dto.UserResponse, error)
+	GetUsernameHistory(ctx context.Context, userId uint) ([]dto.UsernameChangeResponse, error)
+	GetUserByUsername(ctx context.Context, username string, viewerId uint) (*dto.PublicUserResponse, error)
+	SearchUsers(ctx context.Context, query string, viewerId uint, page, limit int) ([]dto.PublicUserResponse, int64, error)
 }
 
 type userService struct {
-	userRepository repository.UserRepository
+	userRepository  repository.UserRepository
+	privacyService  PrivacyService
+	blockRepository repository.BlockRepository
+	fileStore       filestore.FileStore
+	logger          utils.LoggerStrategy
 }
 
-func (u *userService) GetUserById(ctx context.Context, id uint) (*dto.UserResponse, error) {
+// GetUserById leaves out what viewerId isn't allowed to see by the user's
+// privacy rules. Users in a block with each other see neither the avatar nor
+// any last seen.
+func (u *userService) GetUserById(ctx context.Context, id, viewerId uint) (*dto.UserResponse, error) {
 	user, err := u.userRepository.GetUserById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	return u.toUserResponse(user), nil
+	if viewerId != user.Id {
+		blocked, err := u.blockRepository.IsBlocked(ctx, viewerId, user.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check blocks: %w", err)
+		}
+		if blocked {
+			response := toUserResponse(user)
+			response.AvatarURL = ""
+			response.LastSeenAt = nil
+			return response, nil
+		}
+	}
+
+	canSeeAvatar, err := u.privacyService.CanSee(ctx, viewerId, user.Id, domain.PrivacyKeyAvatar)
+	if err != nil {
+		return nil, err
+	}
+
+	canSeeLastSeen, err := u.privacyService.CanSee(ctx, viewerId, user.Id, domain.PrivacyKeyLastSeen)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toUserResponse(user)
+	if !canSeeAvatar {
+		response.AvatarURL = ""
+	}
+	if !canSeeLastSeen {
+		response.LastSeenAt = nil
+		response.LastSeen = string(domain.CoarseLastSeen(user.LastSeenAt, time.Now()))
+	}
+	return response, nil
+}
+
+// EnsureActive turns away accounts that were suspended or deleted, for
+// connections that outlive a single request.
+func (u *userService) EnsureActive(ctx context.Context, userId uint) error {
+	user, err := u.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	switch {
+	case user.IsDeleted():
+		return repository.ErrAccountDeleted
+	case user.IsSuspended(time.Now()):
+		return repository.ErrAccountSuspended
+	}
+	return nil
+}
+
+func (u *userService) UpdateProfile(ctx context.Context, userId uint, input *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	user, err := u.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if input.Name != nil {
+		user.Name = *input.Name
+	}
+	if input.Bio != nil {
+		user.Bio = *input.Bio
+	}
+
+	if err := u.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return toUserResponse(user), nil
+}
+
+// UpdateAvatar crops the uploaded image to a centered square and stores it as
+// an avatarSize JPEG. Every upload gets a new key so clients don't keep showing
+// a cached old avatar.
+func (u *userService) UpdateAvatar(ctx context.Context, userId uint, r io.Reader) (*dto.UserResponse, error) {
+	user, err := u.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width*config.Height > avatarMaxPixels {
+		return nil, repository.ErrInvalidImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, repository.ErrInvalidImage
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, u.toAvatar(img), &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+
+	avatarURL, err := u.fileStore.Save(fmt.Sprintf("avatars/%d/%d.jpg", user.Id, time.Now().UnixNano()), &buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store avatar: %w", err)
+	}
+
+	previous := user.AvatarURL
+	user.AvatarURL = &avatarURL
+	if err := u.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	u.deleteAvatarFile(user.Id, previous)
+	return toUserResponse(user), nil
+}
+
+func (u *userService) DeleteAvatar(ctx context.Context, userId uint) (*dto.UserResponse, error) {
+	user, err := u.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	previous := user.AvatarURL
+	user.AvatarURL = nil
+	if err := u.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	u.deleteAvatarFile(user.Id, previous)
+	return toUserResponse(user), nil
+}
+
+// deleteAvatarFile removes an avatar the user no longer has. The profile is
+// updated already, so failing to delete is only logged.
+func (u *userService) deleteAvatarFile(userId uint, avatarURL *string) {
+	if avatarURL == nil {
+		return
+	}
+
+	key, ok := u.fileStore.Key(*avatarURL)
+	if !ok || !strings.HasPrefix(key, fmt.Sprintf("avatars/%d/", userId)) {
+		return
+	}
+
+	if err := u.fileStore.Delete(key); err != nil {
+		u.logger.Error("failed to delete old avatar", "user_id", userId, "key", key, "error", err)
+	}
+}
+
+// SetUsername changes or, given an empty username, removes the user's
+// username. Uniqueness is case-insensitive, but the casing the user picked is
+// kept for display.
+func (u *userService) SetUsername(ctx context.Context, userId uint, input *dto.UsernameRequest) (*dto.UserResponse, error) {
+	user, err := u.userRepository.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var username *string
+	if input.Username != "" {
+		username = &input.Username
+	}
+
+	if equalUsernames(user.Username, username) {
+		return toUserResponse(user), nil
+	}
+
+	if username != nil && (user.Username == nil || !strings.EqualFold(*user.Username, *username)) {
+		exists, err := u.userRepository.CheckUsernameExists(ctx, *username, user.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check username: %w", err)
+		}
+		if exists {
+			return nil, repository.ErrUsernameExists
+		}
+	}
+
+	change := &domain.UsernameChange{
+		UserId:      user.Id,
+		OldUsername: user.Username,
+		NewUsername: username,
+	}
+
+	user.Username = username
+	if err := u.userRepository.UpdateUsername(ctx, user, change); err != nil {
+		return nil, fmt.Errorf("failed to update username: %w", err)
+	}
+
+	return toUserResponse(user), nil
+}
+
+func (u *userService) GetUsernameHistory(ctx context.Context, userId uint) ([]dto.UsernameChangeResponse, error) {
+	changes, err := u.userRepository.GetUsernameChanges(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get username history: %w", err)
+	}
+
+	responses := make([]dto.UsernameChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = dto.UsernameChangeResponse{CreatedAt: change.CreatedAt}
+		if change.OldUsername != nil {
+			responses[i].OldUsername = *change.OldUsername
+		}
+		if change.NewUsername != nil {
+			responses[i].NewUsername = *change.NewUsername
+		}
+	}
+	return responses, nil
+}
+
+func (u *userService) GetUserByUsername(ctx context.Context, username string, viewerId uint) (*dto.PublicUserResponse, error) {
+	user, err := u.userRepository.GetUserByUsername(ctx, strings.TrimPrefix(username, "@"))
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := u.toPublicUserResponses(ctx, []domain.User{*user}, viewerId)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// SearchUsers only finds users who set a username, so people without one
+// can't be discovered.
+func (u *userService) SearchUsers(ctx context.Context, query string, viewerId uint, page, limit int) ([]dto.PublicUserResponse, int64, error) {
+	users, total, err := u.userRepository.SearchUsers(ctx, query, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	responses, err := u.toPublicUserResponses(ctx, users, viewerId)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+// toPublicUserResponses hides the avatars viewerId isn't allowed to see,
+// including those of users in a block with the viewer.
+func (u *userService) toPublicUserResponses(ctx context.Context, users []domain.User, viewerId uint) ([]dto.PublicUserResponse, error) {
+	userIds := make([]uint, len(users))
+	for i, user := range users {
+		userIds[i] = user.Id
+	}
+
+	canSeeAvatar, err := u.privacyService.VisibleOwners(ctx, viewerId, userIds, domain.PrivacyKeyAvatar)
+	if err != nil {
+		return nil, err
+	}
+
+	blockedIds, err := u.blockRepository.GetBlockedUserIds(ctx, viewerId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+	for _, id := range blockedIds {
+		delete(canSeeAvatar, id)
+	}
+
+	responses := make([]dto.PublicUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *toPublicUserResponse(&user)
+		if !canSeeAvatar[user.Id] {
+			responses[i].AvatarURL = ""
+		}
+	}
+	return responses, nil
+}
+
+func (u *userService) toAvatar(img image.Image) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(bounds.Min).Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+
+	size := min(side, avatarSize)
+	resized := utils.ResizeImage(img, crop, size, size)
+
+	// JPEG has no alpha channel, transparent parts become white
+	avatar := image.NewRGBA(resized.Bounds())
+	draw.Draw(avatar, avatar.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(avatar, avatar.Bounds(), resized, image.Point{}, draw.Over)
+	return avatar
+}
+
+func toUserResponse(user *domain.User) *dto.UserResponse {
+	response := &dto.UserResponse{
+		Id:            user.Id,
+		Name:          user.Name,
+		Email:         user.Email,
+		Bio:           user.Bio,
+		LastSeenAt:    user.LastSeenAt,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt,
+	}
+	if user.Username != nil {
+		response.Username = *user.Username
+	}
+	if user.AvatarURL != nil {
+		response.AvatarURL = *user.AvatarURL
+	}
+	return response
+}
+
+func toPublicUserResponse(user *domain.User) *dto.PublicUserResponse {
+	response := &dto.PublicUserResponse{
+		Id:   user.Id,
+		Name: user.Name,
+		Bio:  user.Bio,
+	}
+	if user.Username != nil {
+		response.Username = *user.Username
+	}
+	if user.AvatarURL != nil {
+		response.AvatarURL = *user.AvatarURL
+	}
+	return response
 }
 
-func (u *userService) toUserResponse(user *domain.User) *dto.UserResponse {
-	return &dto.UserResponse{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+func equalUsernames(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
+	return *a == *b
 }
 
-func NewUserService(userRepository repository.UserRepository) UserService {
+func NewUserService(userRepository repository.UserRepository, privacyService PrivacyService, blockRepository repository.BlockRepository, fileStore filestore.FileStore, logger utils.LoggerStrategy) UserService {
 	return &userService{
-		userRepository: userRepository,
+		userRepository:  userRepository,
+		privacyService:  privacyService,
+		blockRepository: blockRepository,
+		fileStore:       fileStore,
+		logger:          logger,
 	}
 }
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/dto"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	webhookEventHeader     = "X-Telegopher-Event"
+	webhookDeliveryHeader  = "X-Telegopher-Delivery"
+	webhookTimestampHeader = "X-Telegopher-Timestamp"
+	webhookSignatureHeader = "X-Telegopher-Signature"
+)
+
+// WebhookService manages outgoing webhooks and delivers conversation events to
+// them. Deliveries run in the background and never fail the triggering call.
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, input *dto.WebhookRequest, userId uint) (*dto.WebhookResponse, error)
+	GetWebhooks(ctx context.Context, userId uint) ([]dto.WebhookResponse, error)
+	EnableWebhook(ctx context.Context, id, userId uint) (*dto.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id, userId uint) error
+	GetDeliveries(ctx context.Context, id, userId uint, page, limit int) (*dto.WebhookDeliveryListResponse, error)
+
+	Publish(ctx context.Context, event domain.WebhookEvent, private *domain.Private, data map[string]any)
+}
+
+type webhookService struct {
+	webhookRepository repository.WebhookRepository
+	privateRepository repository.PrivateRepository
+	httpClient        *http.Client
+	logger            utils.LoggerStrategy
+	cfg               *config.Config
+}
+
+func (w *webhookService) CreateWebhook(ctx context.Context, input *dto.WebhookRequest, userId uint) (*dto.WebhookResponse, error) {
+	if input.PrivateId != nil {
+		private, err := w.privateRepository.GetPrivateById(ctx, *input.PrivateId)
+		if err != nil {
+			return nil, err
+		}
+
+		if private.User1Id != userId && private.User2Id != userId {
+			return nil, repository.ErrRecordNotFound
+		}
+	}
+
+	if err := utils.CheckPublicURL(ctx, input.URL); err != nil {
+		return nil, repository.ErrWebhookURLNotAllowed
+	}
+
+	secret, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.WebhookEvent, len(input.Events))
+	for i, event := range input.Events {
+		events[i] = domain.WebhookEvent(event)
+	}
+
+	webhook := &domain.Webhook{
+		UserId:    userId,
+		PrivateId: input.PrivateId,
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+	}
+
+	if err := w.webhookRepository.CreateWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	// The secret is only shown once, receivers need it to verify signatures
+	response := w.toWebhookResponse(webhook)
+	response.Secret = secret
+	return response, nil
+}
+
+func (w *webhookService) GetWebhooks(ctx context.Context, userId uint) ([]dto.WebhookResponse, error) {
+	webhooks, err := w.webhookRepository.GetWebhooksByUserId(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	responses := make([]dto.WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = *w.toWebhookResponse(&webhook)
+	}
+	return responses, nil
+}
+
+// EnableWebhook switches a webhook back on after it was disabled for failing,
+// starting again from a clean failure count.
+func (w *webhookService) EnableWebhook(ctx context.Context, id, userId uint) (*dto.WebhookResponse, error) {
+	webhook, err := w.getOwnWebhook(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.webhookRepository.EnableWebhook(ctx, webhook.Id); err != nil {
+		return nil, fmt.Errorf("failed to enable webhook: %w", err)
+	}
+
+	webhook.Active = true
+	webhook.FailureCount = 0
+	return w.toWebhookResponse(webhook), nil
+}
+
+func (w *webhookService) DeleteWebhook(ctx context.Context, id, userId uint) error {
+	webhook, err := w.getOwnWebhook(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := w.webhookRepository.DeleteWebhook(ctx, webhook.Id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+func (w *webhookService) GetDeliveries(ctx context.Context, id, userId uint, page, limit int) (*dto.WebhookDeliveryListResponse, error) {
+	webhook, err := w.getOwnWebhook(ctx, id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+
+	deliveries, err := w.webhookRepository.GetWebhookDeliveries(ctx, webhook.Id, offset, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	hasNextPage := false
+	if len(deliveries) > limit {
+		hasNextPage = true
+		deliveries = deliveries[:limit]
+	}
+
+	response := &dto.WebhookDeliveryListResponse{
+		Deliveries:  make([]dto.WebhookDeliveryResponse, len(deliveries)),
+		Page:        page,
+		Limit:       limit,
+		HasNextPage: hasNextPage,
+	}
+
+	for i, delivery := range deliveries {
+		response.Deliveries[i] = w.toWebhookDeliveryResponse(&delivery)
+	}
+	return response, nil
+}
+
+// Publish sends event to the active webhooks of both participants of private
+// that subscribed to it.
+func (w *webhookService) Publish(ctx context.Context, event domain.WebhookEvent, private *domain.Private, data map[string]any) {
+	webhooks, err := w.webhookRepository.GetActiveWebhooksForEvent(ctx, []uint{private.User1Id, private.User2Id}, private.Id, event)
+	if err != nil {
+		w.logger.Error("failed to get webhooks for event", "event", event, "private", private.Id, "error", err)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	eventId, err := utils.GenerateRefreshToken()
+	if err != nil {
+		w.logger.Error("failed to generate webhook event id", "error", err)
+		return
+	}
+
+	body, err := json.Marshal(&dto.WebhookPayload{
+		Id:        eventId,
+		Event:     string(event),
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		w.logger.Error("failed to marshal webhook payload", "event", event, "error", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		go w.deliver(webhook, eventId, event, body)
+	}
+}
+
+// deliver posts body until the endpoint answers with a 2xx status, waiting
+// twice as long after every failed attempt. Each attempt is logged, and a
+// delivery that runs out of attempts counts towards disabling the webhook.
+// The webhook is read again before every attempt, so one that was disabled or
+// deleted in the meantime gets nothing more.
+func (w *webhookService) deliver(webhook domain.Webhook, eventId string, event domain.WebhookEvent, body []byte) {
+	ctx := context.Background()
+	backoff := w.cfg.Webhook.InitialBackoff
+
+	for attempt := 1; attempt <= w.cfg.Webhook.MaxAttempts; attempt++ {
+		current, err := w.webhookRepository.GetWebhookById(ctx, webhook.Id)
+		if err != nil {
+			if !errors.Is(err, repository.ErrRecordNotFound) {
+				w.logger.Error("failed to get webhook for delivery", "webhook", webhook.Id, "error", err)
+			}
+			return
+		}
+		if !current.Active {
+			return
+		}
+		webhook = *current
+
+		delivery := w.post(ctx, &webhook, eventId, event, body)
+		delivery.Attempt = attempt
+
+		if err := w.webhookRepository.CreateWebhookDelivery(ctx, delivery); err != nil {
+			w.logger.Error("failed to log webhook delivery", "webhook", webhook.Id, "error", err)
+		}
+
+		if delivery.Success {
+			if err := w.webhookRepository.RecordWebhookSuccess(ctx, webhook.Id); err != nil {
+				w.logger.Error("failed to reset webhook failures", "webhook", webhook.Id, "error", err)
+			}
+			return
+		}
+
+		if attempt < w.cfg.Webhook.MaxAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	w.logger.Warn("webhook delivery failed", "webhook", webhook.Id, "event", event, "event_id", eventId)
+
+	if err := w.webhookRepository.RecordWebhookFailure(ctx, webhook.Id, w.cfg.Webhook.MaxFailures); err != nil {
+		w.logger.Error("failed to record webhook failure", "webhook", webhook.Id, "error", err)
+	}
+}
+
+func (w *webhookService) post(ctx context.Context, webhook *domain.Webhook, eventId string, event domain.WebhookEvent, body []byte) *domain.WebhookDelivery {
+	delivery := &domain.WebhookDelivery{
+		WebhookId: webhook.Id,
+		EventId:   eventId,
+		Event:     event,
+	}
+
+	fail := func(err error) *domain.WebhookDelivery {
+		message := err.Error()
+		delivery.Error = &message
+		return delivery
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fail(err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, string(event))
+	req.Header.Set(webhookDeliveryHeader, eventId)
+	req.Header.Set(webhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhookSignatureHeader, "sha256="+utils.SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := w.httpClient.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		return fail(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = &resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fail(fmt.Errorf("webhook responded with status %d", resp.StatusCode))
+	}
+
+	delivery.Success = true
+	return delivery
+}
+
+func (w *webhookService) getOwnWebhook(ctx context.Context, id, userId uint) (*domain.Webhook, error) {
+	webhook, err := w.webhookRepository.GetWebhookById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	if webhook.UserId != userId {
+		return nil, repository.ErrRecordNotFound
+	}
+	return webhook, nil
+}
+
+func (w *webhookService) toWebhookResponse(webhook *domain.Webhook) *dto.WebhookResponse {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+
+	return &dto.WebhookResponse{
+		Id:           webhook.Id,
+		URL:          webhook.URL,
+		Events:       events,
+		PrivateId:    webhook.PrivateId,
+		Active:       webhook.Active,
+		FailureCount: webhook.FailureCount,
+		CreatedAt:    webhook.CreatedAt,
+	}
+}
+
+func (w *webhookService) toWebhookDeliveryResponse(delivery *domain.WebhookDelivery) dto.WebhookDeliveryResponse {
+	response := dto.WebhookDeliveryResponse{
+		Id:         delivery.Id,
+		EventId:    delivery.EventId,
+		Event:      string(delivery.Event),
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Success:    delivery.Success,
+		DurationMs: delivery.DurationMs,
+		CreatedAt:  delivery.CreatedAt,
+	}
+	if delivery.Error != nil {
+		response.Error = *delivery.Error
+	}
+	return response
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository, privateRepository repository.PrivateRepository, logger utils.LoggerStrategy, cfg *config.Config) WebhookService {
+	return &webhookService{
+		webhookRepository: webhookRepository,
+		privateRepository: privateRepository,
+		httpClient:        utils.NewPublicHTTPClient(cfg.Webhook.Timeout),
+		logger:            logger,
+		cfg:               cfg,
+	}
+}
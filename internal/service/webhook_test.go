@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/repository"
+	"github.com/saleh-ghazimoradi/TeleGopher/utils"
+)
+
+// fakeWebhookRepository keeps a single webhook and its delivery log in memory.
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+
+	mu         sync.Mutex
+	webhook    *domain.Webhook
+	deliveries []domain.WebhookDelivery
+	successes  int
+	failures   int
+}
+
+func (f *fakeWebhookRepository) GetWebhookById(ctx context.Context, id uint) (*domain.Webhook, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.webhook == nil || f.webhook.Id != id {
+		return nil, repository.ErrRecordNotFound
+	}
+	webhook := *f.webhook
+	return &webhook, nil
+}
+
+func (f *fakeWebhookRepository) CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.deliveries = append(f.deliveries, *delivery)
+	return nil
+}
+
+func (f *fakeWebhookRepository) RecordWebhookSuccess(ctx context.Context, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.successes++
+	return nil
+}
+
+func (f *fakeWebhookRepository) RecordWebhookFailure(ctx context.Context, id uint, maxFailures int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures++
+	return nil
+}
+
+func (f *fakeWebhookRepository) setActive(active bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.webhook.Active = active
+}
+
+// webhookReceiver is an httptest endpoint answering with the given statuses in
+// turn, repeating the last one.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*receivedWebhook
+}
+
+type receivedWebhook struct {
+	at     time.Time
+	header http.Header
+	body   []byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	r.requests = append(r.requests, &receivedWebhook{at: time.Now(), header: req.Header.Clone(), body: body})
+	status := r.statuses[min(len(r.requests), len(r.statuses))-1]
+	r.mu.Unlock()
+
+	w.WriteHeader(status)
+}
+
+func newTestWebhookService(t *testing.T, statuses ...int) (*webhookService, *fakeWebhookRepository, *webhookReceiver) {
+	t.Helper()
+
+	receiver := &webhookReceiver{statuses: statuses}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	repo := &fakeWebhookRepository{
+		webhook: &domain.Webhook{
+			Id:     1,
+			UserId: 1,
+			URL:    server.URL,
+			Secret: "test-secret",
+			Events: []domain.WebhookEvent{domain.WebhookEventMessageCreated},
+			Active: true,
+		},
+	}
+
+	cfg := &config.Config{
+		Webhook: config.Webhook{
+			Timeout:        time.Second,
+			MaxAttempts:    3,
+			InitialBackoff: 20 * time.Millisecond,
+			MaxFailures:    10,
+		},
+	}
+
+	logger := utils.NewLoggerContext(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service := NewWebhookService(repo, nil, logger, cfg).(*webhookService)
+	// The receivers listen on loopback, which real deliveries refuse to dial
+	service.httpClient = server.Client()
+	return service, repo, receiver
+}
+
+func TestWebhookSignatureVerifies(t *testing.T) {
+	service, repo, receiver := newTestWebhookService(t, http.StatusOK)
+
+	body, _ := json.Marshal(map[string]any{"hello": "world"})
+	service.deliver(*repo.webhook, "event-1", domain.WebhookEventMessageCreated, body)
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(receiver.requests))
+	}
+	request := receiver.requests[0]
+
+	if string(request.body) != string(body) {
+		t.Fatalf("got body %q, want %q", request.body, body)
+	}
+	if got := request.header.Get(webhookEventHeader); got != string(domain.WebhookEventMessageCreated) {
+		t.Errorf("got event header %q", got)
+	}
+	if got := request.header.Get(webhookDeliveryHeader); got != "event-1" {
+		t.Errorf("got delivery header %q", got)
+	}
+
+	// Verify the way a receiver would, with nothing but the shared secret
+	timestamp, err := strconv.ParseInt(request.header.Get(webhookTimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	want := "sha256=" + utils.SignWebhookPayload("test-secret", timestamp, request.body)
+	if got := request.header.Get(webhookSignatureHeader); !hmac.Equal([]byte(got), []byte(want)) {
+		t.Fatalf("got signature %q, want %q", got, want)
+	}
+	if wrong := "sha256=" + utils.SignWebhookPayload("other-secret", timestamp, request.body); wrong == want {
+		t.Fatal("signature doesn't depend on the secret")
+	}
+
+	if repo.successes != 1 || repo.failures != 0 {
+		t.Fatalf("got %d successes and %d failures, want 1 and 0", repo.successes, repo.failures)
+	}
+}
+
+func TestWebhookRetriesWithBackoff(t *testing.T) {
+	service, repo, receiver := newTestWebhookService(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+
+	service.deliver(*repo.webhook, "event-1", domain.WebhookEventMessageCreated, []byte(`{}`))
+
+	if len(receiver.requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(receiver.requests))
+	}
+
+	backoff := service.cfg.Webhook.InitialBackoff
+	for i := 1; i < len(receiver.requests); i++ {
+		if gap := receiver.requests[i].at.Sub(receiver.requests[i-1].at); gap < backoff {
+			t.Errorf("attempt %d came %s after the previous one, want at least %s", i+1, gap, backoff)
+		}
+		backoff *= 2
+	}
+
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("got %d logged deliveries, want 3", len(repo.deliveries))
+	}
+	for i, delivery := range repo.deliveries {
+		if delivery.Attempt != i+1 {
+			t.Errorf("delivery %d has attempt %d", i, delivery.Attempt)
+		}
+		if wantSuccess := i == 2; delivery.Success != wantSuccess {
+			t.Errorf("delivery %d has success %t, want %t", i, delivery.Success, wantSuccess)
+		}
+	}
+	if status := repo.deliveries[0].StatusCode; status == nil || *status != http.StatusInternalServerError {
+		t.Errorf("first delivery has status %v, want 500", status)
+	}
+
+	if repo.successes != 1 || repo.failures != 0 {
+		t.Fatalf("got %d successes and %d failures, want 1 and 0", repo.successes, repo.failures)
+	}
+}
+
+func TestWebhookFailingDeliveryRecordsFailure(t *testing.T) {
+	service, repo, receiver := newTestWebhookService(t, http.StatusServiceUnavailable)
+
+	service.deliver(*repo.webhook, "event-1", domain.WebhookEventMessageCreated, []byte(`{}`))
+
+	if len(receiver.requests) != service.cfg.Webhook.MaxAttempts {
+		t.Fatalf("got %d requests, want %d", len(receiver.requests), service.cfg.Webhook.MaxAttempts)
+	}
+	if len(repo.deliveries) != service.cfg.Webhook.MaxAttempts {
+		t.Fatalf("got %d logged deliveries, want %d", len(repo.deliveries), service.cfg.Webhook.MaxAttempts)
+	}
+	for i, delivery := range repo.deliveries {
+		if delivery.Success || delivery.Error == nil {
+			t.Errorf("delivery %d wasn't logged as failed", i)
+		}
+	}
+
+	if repo.failures != 1 || repo.successes != 0 {
+		t.Fatalf("got %d failures and %d successes, want 1 and 0", repo.failures, repo.successes)
+	}
+}
+
+func TestWebhookStopsRetryingOnceDisabled(t *testing.T) {
+	service, repo, receiver := newTestWebhookService(t, http.StatusInternalServerError)
+
+	// Disable the webhook as soon as the first attempt arrives
+	handler := receiver.ServeHTTP
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo.setActive(false)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	repo.webhook.URL = server.URL
+
+	service.deliver(*repo.webhook, "event-1", domain.WebhookEventMessageCreated, []byte(`{}`))
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(receiver.requests))
+	}
+	if repo.failures != 0 {
+		t.Fatalf("got %d recorded failures, want 0", repo.failures)
+	}
+}
+
+func TestWebhookStopsRetryingOnceDeleted(t *testing.T) {
+	service, repo, receiver := newTestWebhookService(t, http.StatusInternalServerError)
+
+	webhook := *repo.webhook
+	handler := receiver.ServeHTTP
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo.mu.Lock()
+		repo.webhook = nil
+		repo.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	repo.webhook.URL = server.URL
+	webhook.URL = server.URL
+
+	service.deliver(webhook, "event-1", domain.WebhookEventMessageCreated, []byte(`{}`))
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(receiver.requests))
+	}
+}
@@ -3,39 +3,70 @@ package ws
 import (
 	"github.com/coder/websocket"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
-	"log"
 	"sync"
 )
 
 type Client struct {
-	User *domain.User    `json:"user"`
-	Conn *websocket.Conn `json:"-"`
-	Send chan Event      `json:"-"`
-	once sync.Once
+	User          *domain.User    `json:"user"`
+	SessionId     uint            `json:"-"`
+	EmailVerified bool            `json:"-"`
+	Conn          *websocket.Conn `json:"-"`
+	Send          chan Event      `json:"-"`
+	done          chan struct{}
+	once          sync.Once
+	mu            sync.Mutex
+	sendClosed    bool
 }
 
-func (c *Client) SendEvent(event Event) {
+// SendEvent queues event for the client, dropping it when the queue is full
+// or the connection has already gone away.
+func (c *Client) SendEvent(event Event) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sendClosed {
+		return false
+	}
+
 	select {
 	case c.Send <- event:
+		return true
 	default:
+		return false
 	}
 }
 
+// Close asks the goroutine owning the connection to flush the queued events
+// and disconnect. It is safe to call from any goroutine, any number of times.
 func (c *Client) Close() {
 	c.once.Do(func() {
-		if c.Conn != nil {
-			if err := c.Conn.Close(websocket.StatusNormalClosure, "Closing connection"); err != nil {
-				log.Println("failed to close connection")
-			}
-		}
-		close(c.Send)
+		close(c.done)
 	})
 }
 
-func NewClient(user *domain.User, conn *websocket.Conn) *Client {
+// Done is closed once Close has been called.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// CloseSend closes the Send channel. Only the goroutine owning the connection
+// calls it, once it stops writing; events sent afterwards are dropped.
+func (c *Client) CloseSend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.sendClosed {
+		c.sendClosed = true
+		close(c.Send)
+	}
+}
+
+func NewClient(user *domain.User, sessionId uint, conn *websocket.Conn) *Client {
 	return &Client{
-		User: user,
-		Conn: conn,
-		Send: make(chan Event, 512),
+		User:      user,
+		SessionId: sessionId,
+		Conn:      conn,
+		Send:      make(chan Event, 512),
+		done:      make(chan struct{}),
 	}
 }
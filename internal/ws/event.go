@@ -3,17 +3,26 @@ package ws
 type EventType string
 
 const (
-	EventCurrentUsers   EventType = "current_users"
-	EventUserOnline     EventType = "online"
-	EventUserOffline    EventType = "offline"
-	EventNewPrivate     EventType = "new_private"
-	EventMessage        EventType = "message"
-	EventDelivered      EventType = "delivered"
-	EventRead           EventType = "read"
-	EventTyping         EventType = "typing"
-	EventError          EventType = "error"
-	EventHeartbeat      EventType = "heartbeat"
-	EventServerShutdown EventType = "shutdown"
+	EventCurrentUsers     EventType = "current_users"
+	EventUserOnline       EventType = "online"
+	EventUserOffline      EventType = "offline"
+	EventNewPrivate       EventType = "new_private"
+	EventProfileUpdated   EventType = "profile_updated"
+	EventMessage          EventType = "message"
+	EventMessageEdited    EventType = "message_edited"
+	EventCallbackQuery    EventType = "callback_query"
+	EventCallbackAnswer   EventType = "callback_answer"
+	EventDelivered        EventType = "delivered"
+	EventRead             EventType = "read"
+	EventTyping           EventType = "typing"
+	EventError            EventType = "error"
+	EventHeartbeat        EventType = "heartbeat"
+	EventServerShutdown   EventType = "shutdown"
+	EventSessionRevoked   EventType = "session_revoked"
+	EventAccountSuspended EventType = "account_suspended"
+	EventQRLoginToken     EventType = "qr_login_token"
+	EventQRLoginSuccess   EventType = "qr_login_success"
+	EventQRLoginExpired   EventType = "qr_login_expired"
 )
 
 type Event struct {
@@ -2,17 +2,90 @@ package ws
 
 import (
 	"context"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"github.com/saleh-ghazimoradi/TeleGopher/internal/service"
 	"github.com/saleh-ghazimoradi/TeleGopher/utils"
 	"sync"
+	"time"
 )
 
 type Hub struct {
-	Clients        map[uint]map[*Client]struct{}
-	privateService service.PrivateService
-	messageService service.MessageService
-	logger         utils.LoggerStrategy
-	mu             sync.RWMutex
+	Clients         map[uint]map[*Client]struct{}
+	privateService  service.PrivateService
+	messageService  service.MessageService
+	blockService    service.BlockService
+	privacyService  service.PrivacyService
+	lastSeenService service.LastSeenService
+	logger          utils.LoggerStrategy
+	mu              sync.RWMutex
+}
+
+// broadcastPresence sends a presence event about user, leaving out the users
+// in a block with them. Viewers who may not see their last seen aren't told
+// they came online, and going offline only gives them the coarse last seen.
+// The avatar is only included for viewers allowed to see it.
+func (h *Hub) broadcastPresence(user *domain.User, eventType EventType) {
+	ctx := context.Background()
+	blocked, err := h.blockedUserIds(user.Id)
+	if err != nil {
+		return
+	}
+
+	h.mu.RLock()
+	viewerIds := make([]uint, 0, len(h.Clients))
+	for id := range h.Clients {
+		if _, ok := blocked[id]; !ok && id != user.Id {
+			viewerIds = append(viewerIds, id)
+		}
+	}
+	h.mu.RUnlock()
+
+	canSeeLastSeen, err := h.privacyService.AllowedViewers(ctx, user.Id, viewerIds, domain.PrivacyKeyLastSeen)
+	if err != nil {
+		h.logger.Error("failed to check last seen privacy", "user", user.Id, "err", err)
+		return
+	}
+
+	canSeeAvatar, err := h.privacyService.AllowedViewers(ctx, user.Id, viewerIds, domain.PrivacyKeyAvatar)
+	if err != nil {
+		h.logger.Error("failed to check avatar privacy", "user", user.Id, "err", err)
+		return
+	}
+
+	for _, id := range viewerIds {
+		if !canSeeLastSeen[id] && eventType != EventUserOffline {
+			continue
+		}
+		h.SendEventToUserIds([]uint{id}, user.Id, eventType, presencePayload(user, canSeeAvatar[id], canSeeLastSeen[id]))
+	}
+}
+
+func presencePayload(user *domain.User, withAvatar, withLastSeen bool) map[string]any {
+	payload := user.ToMap()
+	if !withAvatar {
+		payload["avatar_url"] = nil
+	}
+	if !withLastSeen {
+		payload["last_seen_at"] = nil
+		payload["last_seen"] = domain.CoarseLastSeen(user.LastSeenAt, time.Now())
+	}
+	return payload
+}
+
+// blockedUserIds fails when the blocks can't be read, callers then send
+// nothing rather than risk reaching a blocked user.
+func (h *Hub) blockedUserIds(userId uint) (map[uint]struct{}, error) {
+	userIds, err := h.blockService.GetBlockedUserIds(context.Background(), userId)
+	if err != nil {
+		h.logger.Error("failed to get blocked users", "user", userId, "err", err)
+		return nil, err
+	}
+
+	blocked := make(map[uint]struct{}, len(userIds))
+	for _, id := range userIds {
+		blocked[id] = struct{}{}
+	}
+	return blocked, nil
 }
 
 func (h *Hub) BroadcastToAll(event Event) {
@@ -21,9 +94,7 @@ func (h *Hub) BroadcastToAll(event Event) {
 
 	for _, conn := range h.Clients {
 		for client := range conn {
-			select {
-			case client.Send <- event:
-			default:
+			if !client.SendEvent(event) {
 				h.logger.Warn("dropped event for client", "client", client.User.Id, "channel full")
 			}
 		}
@@ -47,16 +118,21 @@ func (h *Hub) GetClients(userId uint) ([]*Client, bool) {
 	return clients, true
 }
 
+func (h *Hub) IsOnline(userId uint) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.Clients[userId]) > 0
+}
+
 func (h *Hub) SendEventToUserIds(userIds []uint, senderId uint, eventType EventType, payload map[string]any) {
 	for _, id := range userIds {
-		h.mu.RLock()
-		connections, ok := h.Clients[id]
-		h.mu.RUnlock()
+		clients, ok := h.GetClients(id)
 		if !ok {
 			continue
 		}
 
-		for c := range connections {
+		for _, c := range clients {
 			c.SendEvent(Event{
 				EventType: eventType,
 				Payload:   payload,
@@ -78,10 +154,7 @@ func (h *Hub) RegisterClient(client *Client) {
 	h.mu.Unlock()
 
 	if firstConnection {
-		h.BroadcastToAll(Event{
-			EventType: EventUserOnline,
-			Payload:   client.User.ToMap(),
-		})
+		h.broadcastPresence(client.User, EventUserOnline)
 
 		go func() {
 			ctx := context.Background()
@@ -91,12 +164,34 @@ func (h *Hub) RegisterClient(client *Client) {
 				return
 			}
 
+			blocked, err := h.blockedUserIds(client.User.Id)
+			if err != nil {
+				return
+			}
+
 			for _, private := range privates {
 				msg, err := h.messageService.GetUndeliveredMessages(ctx, private.Id, client.User.Id)
 				if err != nil {
 					h.logger.Error("failed to get undelivered messages", "err", err)
 					continue
 				}
+				if len(msg) == 0 {
+					continue
+				}
+
+				otherId := private.User1Id
+				if otherId == client.User.Id {
+					otherId = private.User2Id
+				}
+				if _, ok := blocked[otherId]; ok {
+					continue
+				}
+
+				canSeeLastSeen, err := h.privacyService.CanSee(ctx, otherId, client.User.Id, domain.PrivacyKeyLastSeen)
+				if err != nil || !canSeeLastSeen {
+					continue
+				}
+
 				for _, m := range msg {
 					if m.FromId == client.User.Id {
 						continue
@@ -128,41 +223,67 @@ func (h *Hub) UnregisterClient(client *Client) {
 	h.mu.Unlock()
 
 	if noConnectionLeft {
-		h.BroadcastToAll(Event{
-			EventType: EventUserOffline,
-			Payload:   client.User.ToMap(),
-		})
+		now := time.Now().UTC()
+		h.lastSeenService.Record(client.User.Id, now)
+
+		user := *client.User
+		user.LastSeenAt = &now
+		h.broadcastPresence(&user, EventUserOffline)
 	}
 }
 
+// SendCurrentClients tells client who is online, as far as their privacy
+// settings allow client to know.
 func (h *Hub) SendCurrentClients(client *Client) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	users := make([]map[string]any, 0)
-	seen := make(map[uint]struct{})
+	ctx := context.Background()
+	blocked, err := h.blockedUserIds(client.User.Id)
+	if err != nil {
+		return
+	}
 
+	h.mu.RLock()
+	online := make(map[uint]*domain.User, len(h.Clients))
+	userIds := make([]uint, 0, len(h.Clients))
 	for userId, connections := range h.Clients {
 		if userId == client.User.Id {
 			continue
 		}
 
-		_, ok := seen[userId]
-		if ok {
+		if _, ok := blocked[userId]; ok {
 			continue
 		}
 
 		for connection := range connections {
-			users = append(users, connection.User.ToMap())
-			seen[userId] = struct{}{}
+			online[userId] = connection.User
+			userIds = append(userIds, userId)
 			break
 		}
 	}
+	h.mu.RUnlock()
 
-	client.Send <- Event{
+	canSeeLastSeen, err := h.privacyService.VisibleOwners(ctx, client.User.Id, userIds, domain.PrivacyKeyLastSeen)
+	if err != nil {
+		h.logger.Error("failed to check last seen privacy", "user", client.User.Id, "err", err)
+		return
+	}
+
+	canSeeAvatar, err := h.privacyService.VisibleOwners(ctx, client.User.Id, userIds, domain.PrivacyKeyAvatar)
+	if err != nil {
+		h.logger.Error("failed to check avatar privacy", "user", client.User.Id, "err", err)
+		return
+	}
+
+	users := make([]map[string]any, 0, len(userIds))
+	for _, userId := range userIds {
+		if canSeeLastSeen[userId] {
+			users = append(users, presencePayload(online[userId], canSeeAvatar[userId], true))
+		}
+	}
+
+	client.SendEvent(Event{
 		EventType: EventCurrentUsers,
 		Payload:   users,
-	}
+	})
 }
 
 func (h *Hub) SendError(clientId uint, message string) {
@@ -179,12 +300,83 @@ func (h *Hub) SendError(clientId uint, message string) {
 	}
 }
 
+// CloseSessions disconnects the clients connected with the given sessions of
+// userId. The clients are only told to go away, their own goroutines close the
+// connection once the last events are written.
+func (h *Hub) CloseSessions(userId uint, sessionIds ...uint) {
+	revoked := make(map[uint]struct{}, len(sessionIds))
+	for _, sessionId := range sessionIds {
+		revoked[sessionId] = struct{}{}
+	}
+
+	var clients []*Client
+	h.mu.RLock()
+	for client := range h.Clients[userId] {
+		if _, ok := revoked[client.SessionId]; ok {
+			clients = append(clients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		h.UnregisterClient(client)
+		client.SendEvent(Event{
+			EventType: EventSessionRevoked,
+			Payload:   "Session has been revoked",
+		})
+		client.Close()
+	}
+}
+
+// CloseSuspended disconnects every client of a user who was just suspended,
+// telling them why.
+func (h *Hub) CloseSuspended(userId uint, reason string) {
+	clients, ok := h.GetClients(userId)
+	if !ok {
+		return
+	}
+
+	for _, client := range clients {
+		h.UnregisterClient(client)
+		client.SendEvent(Event{
+			EventType: EventAccountSuspended,
+			Payload:   reason,
+		})
+		client.Close()
+	}
+}
+
+// Stats counts the users online and their connections.
+func (h *Hub) Stats() (users, connections int) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, clients := range h.Clients {
+		if len(clients) > 0 {
+			users++
+		}
+		connections += len(clients)
+	}
+	return users, connections
+}
+
 func (h *Hub) Shutdown() {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	h.logger.Info("Shutting down hub, notifying all clients...")
 
+	now := time.Now().UTC()
+	for userId := range h.Clients {
+		h.lastSeenService.Record(userId, now)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := h.lastSeenService.Flush(ctx); err != nil {
+		h.logger.Error("failed to store last seen times", "err", err)
+	}
+
 	for _, connections := range h.Clients {
 		for client := range connections {
 			client.SendEvent(Event{
@@ -198,11 +390,14 @@ func (h *Hub) Shutdown() {
 	h.logger.Info("Hub shutdown complete")
 }
 
-func NewHub(privateService service.PrivateService, messageService service.MessageService, logger utils.LoggerStrategy) *Hub {
+func NewHub(privateService service.PrivateService, messageService service.MessageService, blockService service.BlockService, privacyService service.PrivacyService, lastSeenService service.LastSeenService, logger utils.LoggerStrategy) *Hub {
 	return &Hub{
-		Clients:        make(map[uint]map[*Client]struct{}),
-		privateService: privateService,
-		messageService: messageService,
-		logger:         logger,
+		Clients:         make(map[uint]map[*Client]struct{}),
+		privateService:  privateService,
+		messageService:  messageService,
+		blockService:    blockService,
+		privacyService:  privacyService,
+		lastSeenService: lastSeenService,
+		logger:          logger,
 	}
 }
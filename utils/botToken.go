@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GenerateBotToken returns a Bot API token in the "<bot id>:<secret>" shape used
+// by Telegram, together with the hash of the secret that should be stored.
+func GenerateBotToken(botId uint) (string, string, error) {
+	secret, err := GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return fmt.Sprintf("%d:%s", botId, secret), HashToken(secret), nil
+}
+
+func ParseBotToken(token string) (uint, string, bool) {
+	id, secret, ok := strings.Cut(token, ":")
+	if !ok || secret == "" {
+		return 0, "", false
+	}
+
+	botId, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return uint(botId), secret, true
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+const challengeTokenPurpose = "two_factor_challenge"
+
+// ChallengeClaims back the token handed out after the password step of a
+// two-factor login. They carry what the login started with, so the second
+// step finishes the same login.
+type ChallengeClaims struct {
+	UserId     uint   `json:"user_id"`
+	Platform   string `json:"platform"`
+	DeviceName string `json:"device_name"`
+	jwt.RegisteredClaims
+}
+
+func GenerateChallengeToken(secret string, userId uint, platform, deviceName string, expiresIn time.Duration) (string, error) {
+	tokenId, err := GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	claims := &ChallengeClaims{
+		UserId:     userId,
+		Platform:   platform,
+		DeviceName: deviceName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			Subject:   fmt.Sprint(userId),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenId,
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(purposeKey(secret, challengeTokenPurpose))
+}
+
+func ValidateChallengeToken(tokenString, secret string) (*ChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ChallengeClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return purposeKey(secret, challengeTokenPurpose), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*ChallengeClaims); ok && token.Valid {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
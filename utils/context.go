@@ -5,15 +5,31 @@ import "context"
 type ContextKey string
 
 const (
-	UserIdKey   ContextKey = "user_id"
-	NameKey     ContextKey = "name"
-	PlatformKey ContextKey = "X-Platform"
+	UserIdKey        ContextKey = "user_id"
+	SessionIdKey     ContextKey = "sid"
+	TokenIdKey       ContextKey = "jti"
+	EmailVerifiedKey ContextKey = "email_verified"
+	NameKey          ContextKey = "name"
+	PlatformKey      ContextKey = "X-Platform"
+	TokenScopesKey   ContextKey = "scopes"
 )
 
 func WithUserId(ctx context.Context, id uint) context.Context {
 	return context.WithValue(ctx, UserIdKey, id)
 }
 
+func WithSessionId(ctx context.Context, id uint) context.Context {
+	return context.WithValue(ctx, SessionIdKey, id)
+}
+
+func WithTokenId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, TokenIdKey, id)
+}
+
+func WithEmailVerified(ctx context.Context, verified bool) context.Context {
+	return context.WithValue(ctx, EmailVerifiedKey, verified)
+}
+
 func WithName(ctx context.Context, name string) context.Context {
 	return context.WithValue(ctx, NameKey, name)
 }
@@ -22,11 +38,30 @@ func WithPlatform(ctx context.Context, platform string) context.Context {
 	return context.WithValue(ctx, PlatformKey, platform)
 }
 
+func WithTokenScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, TokenScopesKey, scopes)
+}
+
 func UserIdFromContext(ctx context.Context) (uint, bool) {
 	id, ok := ctx.Value(UserIdKey).(uint)
 	return id, ok
 }
 
+func SessionIdFromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(SessionIdKey).(uint)
+	return id, ok
+}
+
+func TokenIdFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(TokenIdKey).(string)
+	return id, ok
+}
+
+func EmailVerifiedFromContext(ctx context.Context) bool {
+	verified, _ := ctx.Value(EmailVerifiedKey).(bool)
+	return verified
+}
+
 func NameFromContext(ctx context.Context) (string, bool) {
 	name, ok := ctx.Value(NameKey).(string)
 	return name, ok
@@ -36,3 +71,10 @@ func PlatformFromContext(ctx context.Context) (string, bool) {
 	platform, ok := ctx.Value(PlatformKey).(string)
 	return platform, ok
 }
+
+// TokenScopesFromContext is only set for requests made with a personal access
+// token, sessions aren't limited to scopes.
+func TokenScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(TokenScopesKey).([]string)
+	return scopes, ok
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+const EmailTokenVerification = "email_verification"
+
+// EmailClaims back the tokens sent by email. They carry the address they were
+// sent to, so they stop working once the user changes it.
+type EmailClaims struct {
+	UserId  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+func GenerateEmailToken(secret string, userId uint, email, purpose string, expiresIn time.Duration) (string, error) {
+	claims := &EmailClaims{
+		UserId:  userId,
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			Subject:   fmt.Sprint(userId),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(purposeKey(secret, purpose))
+}
+
+func ValidateEmailToken(tokenString, secret, purpose string) (*EmailClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailClaims{}, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return purposeKey(secret, purpose), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*EmailClaims); ok && token.Valid && claims.Purpose == purpose {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// purposeKey derives a key per purpose, so neither access tokens nor tokens
+// of another purpose pass as one of these and the other way around.
+func purposeKey(secret, purpose string) []byte {
+	return []byte(secret + "/" + purpose)
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+)
+
+// ResizeImage scales the src region of img to width x height by averaging the
+// source pixels that fall into each destination pixel. It is meant for
+// downscaling; upscaling repeats pixels.
+func ResizeImage(img image.Image, src image.Rectangle, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := src.Dx(), src.Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*srcHeight/height
+		y1 := max(src.Min.Y+(y+1)*srcHeight/height, y0+1)
+
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*srcWidth/width
+			x1 := max(src.Min.X+(x+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
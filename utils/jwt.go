@@ -5,34 +5,52 @@ import (
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/saleh-ghazimoradi/TeleGopher/config"
+	"github.com/saleh-ghazimoradi/TeleGopher/internal/domain"
 	"time"
 )
 
 type Claims struct {
-	UserId   uint   `json:"user_id"`
-	Name     string `json:"name"`
-	Platform string `json:"X-Platform"`
+	UserId    uint   `json:"user_id"`
+	SessionId uint   `json:"sid"`
+	Name      string `json:"name"`
+	Platform  string `json:"X-Platform"`
+	// EmailVerified is as of when the token was issued, clients refresh the
+	// token after verifying to pick the change up.
+	EmailVerified bool `json:"email_verified"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(cfg *config.Config, userId uint, name, platform string) (string, error) {
-	if platform != "web" && platform != "mobile" {
+// TokenKeys signs access tokens and finds the key to verify one with.
+type TokenKeys interface {
+	Sign(claims jwt.Claims) (string, error)
+	Keyfunc(token *jwt.Token) (any, error)
+}
+
+func GenerateToken(keys TokenKeys, cfg *config.Config, userId, sessionId uint, name, platform string, emailVerified bool) (string, error) {
+	if !domain.IsValidPlatform(platform) {
 		return "", errors.New("invalid platform for token")
 	}
 
+	tokenId, err := GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
 	accessClaims := &Claims{
-		UserId:   userId,
-		Name:     name,
-		Platform: platform,
+		UserId:        userId,
+		SessionId:     sessionId,
+		Name:          name,
+		Platform:      platform,
+		EmailVerified: emailVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.ExpiresIn)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.AccessTokenExpires(platform))),
 			Subject:   fmt.Sprint(userId),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenId,
 		},
 	}
 
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessToken, err := at.SignedString([]byte(cfg.JWT.Secret))
+	accessToken, err := keys.Sign(accessClaims)
 	if err != nil {
 		return "", err
 	}
@@ -40,14 +58,8 @@ func GenerateToken(cfg *config.Config, userId uint, name, platform string) (stri
 	return accessToken, nil
 }
 
-func ValidateToken(tokenString, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
-		if !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(secret), nil
-	})
+func ValidateToken(tokenString string, keys TokenKeys) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.Keyfunc)
 
 	if err != nil {
 		return nil, err
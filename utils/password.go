@@ -10,3 +10,13 @@ func HashPassword(password string) (string, error) {
 func CheckPasswordHash(hashedPassword, password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)) == nil
 }
+
+// dummyPasswordHash is the hash of a random, discarded password at
+// bcrypt.DefaultCost, so comparing against it takes as long as a real check.
+const dummyPasswordHash = "$2a$10$XnDtw7Mg9dZFdz4XW9GUaezsCTs7mA.4Lryk134o1RlgO9aooWJiu"
+
+// CheckDummyPassword spends the time of a password check when there is no
+// hash to check against, e.g. for an unknown email.
+func CheckDummyPassword(password string) {
+	CheckPasswordHash(dummyPasswordHash, password)
+}
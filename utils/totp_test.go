@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors, base32
+// encoded the way users' secrets are stored.
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// The RFC's vectors have eight digits, six digit codes are their last six.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	for _, vector := range rfc6238Vectors {
+		if got := totpCode(key, vector.unix/totpPeriod); got != vector.code {
+			t.Errorf("at %d got %s, want %s", vector.unix, got, vector.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	for _, vector := range rfc6238Vectors {
+		now := time.Unix(vector.unix, 0)
+
+		step, ok := ValidateTOTP(rfc6238Secret, vector.code, now)
+		if !ok || step != vector.unix/totpPeriod {
+			t.Errorf("at %d got step %d and %t, want step %d", vector.unix, step, ok, vector.unix/totpPeriod)
+		}
+
+		// A period of clock drift either way is tolerated, two aren't
+		for _, drift := range []int64{-totpPeriod, totpPeriod} {
+			if _, ok := ValidateTOTP(rfc6238Secret, vector.code, now.Add(time.Duration(drift)*time.Second)); !ok {
+				t.Errorf("at %d code rejected with %ds drift", vector.unix, drift)
+			}
+		}
+		if _, ok := ValidateTOTP(rfc6238Secret, vector.code, now.Add(2*totpPeriod*time.Second)); ok {
+			t.Errorf("at %d code accepted two periods late", vector.unix)
+		}
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	for _, code := range []string{"", "28708", "2870820", "000000"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, now); ok {
+			t.Errorf("code %q accepted", code)
+		}
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", now); ok {
+		t.Error("code accepted for an invalid secret")
+	}
+}